@@ -5,14 +5,19 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	pokemonapp "github.com/user/dcminigames/internal/application/pokemon"
 	unoapp "github.com/user/dcminigames/internal/application/uno"
+	pokemonrepo "github.com/user/dcminigames/internal/domain/pokemon/repository"
+	unorepo "github.com/user/dcminigames/internal/domain/uno/repository"
 	"github.com/user/dcminigames/internal/infrastructure/activity"
 	"github.com/user/dcminigames/internal/infrastructure/discord"
 	"github.com/user/dcminigames/internal/infrastructure/imaging"
+	"github.com/user/dcminigames/internal/infrastructure/persistence/file"
 	"github.com/user/dcminigames/internal/infrastructure/persistence/memory"
 	"github.com/user/dcminigames/internal/infrastructure/pokeapi"
 	"github.com/user/dcminigames/internal/interfaces/discord/commands"
@@ -20,6 +25,12 @@ import (
 )
 
 func main() {
+	// 子命令：生成 PokeAPI 内置快照
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		runSnapshot(os.Args[2:])
+		return
+	}
+
 	configPath := flag.String("config", "config.yaml", "配置文件路径")
 	flag.Parse()
 
@@ -37,23 +48,44 @@ func main() {
 		log.Fatalf("创建 Bot 失败: %v", err)
 	}
 
-	// 初始化依赖
-	gameRepo := memory.NewGameRepository()
-	cardRenderer := imaging.NewCardRenderer(cfg.Uno.AssetsPath)
-	unoHandler := unoapp.NewHandler(gameRepo, cardRenderer)
-	unoCommands := commands.NewUnoCommands(bot, unoHandler)
-
-	// 预加载宝可梦数据（避免首次使用时超时）
+	// 预加载宝可梦数据（避免首次使用时超时；恢复对战时需要从图鉴重新加载宝可梦）
 	log.Println("正在预加载宝可梦数据...")
+	pokeapi.Configure(pokeapi.Options{
+		CacheDir: cfg.PokeAPI.CacheDir,
+		MaxAge:   time.Duration(cfg.PokeAPI.MaxAgeHours) * time.Hour,
+		Offline:  cfg.PokeAPI.Offline,
+	})
+	loadStart := time.Now()
 	if err := pokeapi.EnsureDataLoaded(); err != nil {
+		// 没有内置快照时无法离线启动，提示运维生成快照
+		if !pokeapi.HasEmbeddedData() {
+			log.Fatalf("加载宝可梦数据失败，且程序没有内置 PokeAPI 快照: %v\n"+
+				"请在可联网的环境中运行 `go run ./cmd/bot snapshot` 生成快照后重新编译，或在 %s 中提供本地缓存", err, cfg.PokeAPI.CacheDir)
+		}
 		log.Printf("预加载宝可梦数据失败: %v", err)
 	} else {
-		log.Printf("宝可梦数据加载完成，共 %d 只宝可梦", pokeapi.GetTotalPokemonCount())
+		log.Printf("宝可梦数据加载完成，共 %d 只宝可梦（耗时 %v）", pokeapi.GetTotalPokemonCount(), time.Since(loadStart).Round(time.Millisecond))
+	}
+
+	// 初始化存储（file 后端在启动时恢复进行中的游戏与对战）
+	repos, err := newRepositories(cfg.Storage)
+	if err != nil {
+		log.Fatalf("初始化存储失败: %v", err)
 	}
 
+	// 初始化依赖
+	gameRepo := repos.games
+	cardRenderer := imaging.NewCardRenderer(cfg.Uno.AssetsPath)
+	unoHandler := unoapp.NewHandler(gameRepo, cardRenderer)
+	unoCommands := commands.NewUnoCommands(bot, unoHandler)
+
 	// 初始化宝可梦对战
-	battleRepo := memory.NewBattleRepository()
-	pokemonHandler := pokemonapp.NewHandler(battleRepo)
+	pokemonHandler := pokemonapp.NewHandler(repos.battles, repos.presets)
+	if count, err := pokemonHandler.RestorePresets(); err != nil {
+		log.Printf("%v", err)
+	} else if count > 0 {
+		log.Printf("已恢复 %d 个配队预设", count)
+	}
 	pokemonCommands := commands.NewPokemonCommands(bot, pokemonHandler)
 
 	// 初始化 Activity 服务（无名杀/三国杀）
@@ -110,4 +142,48 @@ func main() {
 			log.Printf("关闭 Activity 服务失败: %v", err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// repositories 应用使用的仓储
+type repositories struct {
+	games   unorepo.GameRepository
+	battles pokemonrepo.BattleRepository
+	presets pokemonapp.PresetRepository
+}
+
+// newRepositories 按配置创建仓储；file 后端会从数据目录恢复进行中的 UNO 游戏与宝可梦对战
+func newRepositories(cfg config.StorageConfig) (*repositories, error) {
+	if cfg.Backend != config.StorageFile {
+		log.Println("使用内存存储，重启后游戏与对战数据将丢失")
+		return &repositories{
+			games:   memory.NewGameRepository(),
+			battles: memory.NewBattleRepository(),
+			presets: memory.NewPresetRepository(),
+		}, nil
+	}
+
+	games, err := file.NewGameRepository(filepath.Join(cfg.Path, "uno"))
+	if err != nil {
+		return nil, err
+	}
+	battles, err := file.NewBattleRepository(filepath.Join(cfg.Path, "battles"), pokeapi.GetPredefinedPokemon)
+	if err != nil {
+		return nil, err
+	}
+	presets, err := file.NewPresetRepository(filepath.Join(cfg.Path, "presets"))
+	if err != nil {
+		return nil, err
+	}
+
+	gameCount, err := games.Restore()
+	if err != nil {
+		return nil, err
+	}
+	battleCount, err := battles.Restore()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("使用文件存储（%s），已恢复 %d 局 UNO 游戏、%d 场宝可梦对战", cfg.Path, gameCount, battleCount)
+
+	return &repositories{games: games, battles: battles, presets: presets}, nil
+}
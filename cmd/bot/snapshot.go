@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/user/dcminigames/internal/infrastructure/pokeapi"
+)
+
+// runSnapshot 下载 PokeAPI CSV 并生成内置快照与预编译图鉴（bot snapshot [-out 目录] [-cache 目录]）
+// 生成后重新编译即可在无网络时启动
+func runSnapshot(args []string) {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	out := fs.String("out", "internal/infrastructure/pokeapi/snapshot", "快照输出目录")
+	cacheDir := fs.String("cache", "./data/pokeapi", "CSV 缓存目录（留空则不缓存）")
+	fs.Parse(args)
+
+	// 有效期为 0：每个文件都联网校验，保证快照为最新数据
+	pokeapi.Configure(pokeapi.Options{CacheDir: *cacheDir})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	log.Printf("正在生成 PokeAPI 快照到 %s ...", *out)
+	if err := pokeapi.BakeSnapshot(ctx, *out); err != nil {
+		log.Fatalf("生成快照失败: %v", err)
+	}
+	log.Println("快照生成完成，重新编译后即可离线启动")
+}
@@ -3,12 +3,14 @@ package pokemon
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
 	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/domain/pokemon/repository"
 	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
-	"github.com/user/dcminigames/internal/infrastructure/persistence/memory"
 	"github.com/user/dcminigames/internal/infrastructure/pokeapi"
 )
 
@@ -16,27 +18,19 @@ import (
 type PokemonConfig struct {
 	PokemonID   int
 	Nature      valueobject.Nature
-	AbilitySlot int                   // 0=第一特性, 1=第二特性, -1=隐藏特性
-	MoveIndices []int                 // 选择的技能索引
-	TeraType    valueobject.PokeType  // 太晶属性
-}
-
-// TeamPreset 配队预设
-type TeamPreset struct {
-	ID          string           // 预设ID
-	Name        string           // 预设名称
-	UserID      string           // 用户ID
-	PokemonID   int              // 宝可梦ID
-	PokemonName string           // 宝可梦名称（显示用）
-	Nature      valueobject.Nature
-	AbilitySlot int
-	MoveIndices []int
-	TeraType    valueobject.PokeType
+	AbilitySlot int                        // 0=第一特性, 1=第二特性, -1=隐藏特性
+	MoveIndices []int                      // 选择的技能索引（对应 Format 范围内的可学技能）
+	Format      valueobject.LearnsetFormat // 可学技能范围（空=全国图鉴）
+	TeraType    valueobject.PokeType       // 太晶属性
+	ItemID      int                        // 携带道具ID（0=不携带）
+	EVs         *entity.Stats              // 努力值（nil=全0）
+	IVs         *entity.Stats              // 个体值（nil=6V）
 }
 
 // Handler 宝可梦对战应用层处理器
 type Handler struct {
-	repo        *memory.BattleRepository
+	repo        repository.BattleRepository
+	presetRepo  PresetRepository
 	client      *pokeapi.Client
 	configMu    sync.RWMutex
 	configs     map[string]*PokemonConfig // key: channelID:playerID
@@ -45,12 +39,16 @@ type Handler struct {
 }
 
 // NewHandler 创建处理器
-func NewHandler(repo *memory.BattleRepository) *Handler {
+func NewHandler(repo repository.BattleRepository, presetRepo PresetRepository) *Handler {
+	// 形态变化类特性从图鉴查询目标形态的种族值与属性
+	ability.GetRegistry().SetFormSource(pokeapi.GetFormData)
+
 	return &Handler{
-		repo:    repo,
-		client:  pokeapi.NewClient(),
-		configs: make(map[string]*PokemonConfig),
-		presets: make(map[string][]*TeamPreset),
+		repo:       repo,
+		presetRepo: presetRepo,
+		client:     pokeapi.NewClient(),
+		configs:    make(map[string]*PokemonConfig),
+		presets:    make(map[string][]*TeamPreset),
 	}
 }
 
@@ -87,10 +85,23 @@ func (h *Handler) CreateBattle(channelID, playerID, username string) (*entity.Ba
 
 // CreateBattleWithTeamSize 创建指定队伍大小的对战
 func (h *Handler) CreateBattleWithTeamSize(channelID, playerID, username string, teamSize entity.TeamSize) (*entity.Battle, error) {
+	return h.CreateBattleWithMode(channelID, playerID, username, teamSize, valueobject.ModeSingle)
+}
+
+// CreateBattleWithMode 创建指定队伍大小与对战模式的快速对战
+func (h *Handler) CreateBattleWithMode(channelID, playerID, username string, teamSize entity.TeamSize, mode valueobject.BattleMode) (*entity.Battle, error) {
+	return h.CreateBattleWithConfig(channelID, playerID, username, entity.NewQuickConfig(teamSize, mode))
+}
+
+// CreateBattleWithConfig 按对战配置（规则预设）创建对战
+func (h *Handler) CreateBattleWithConfig(channelID, playerID, username string, config *valueobject.BattleConfig) (*entity.Battle, error) {
 	if h.repo.Exists(channelID) {
 		return nil, fmt.Errorf("该频道已有对战进行中")
 	}
-	battle := entity.NewBattleWithTeamSize(uuid.New().String(), channelID, teamSize)
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	battle := entity.NewBattleWithConfig(uuid.New().String(), channelID, config)
 	if err := battle.AddPlayer(playerID, username); err != nil {
 		return nil, err
 	}
@@ -102,10 +113,23 @@ func (h *Handler) CreateBattleWithTeamSize(channelID, playerID, username string,
 
 // CreateAIBattle 创建人机对战
 func (h *Handler) CreateAIBattle(channelID, playerID, username string, teamSize entity.TeamSize) (*entity.Battle, error) {
+	return h.CreateAIBattleWithMode(channelID, playerID, username, teamSize, valueobject.ModeSingle)
+}
+
+// CreateAIBattleWithMode 创建指定对战模式的人机对战
+func (h *Handler) CreateAIBattleWithMode(channelID, playerID, username string, teamSize entity.TeamSize, mode valueobject.BattleMode) (*entity.Battle, error) {
+	return h.CreateAIBattleWithConfig(channelID, playerID, username, entity.NewQuickConfig(teamSize, mode))
+}
+
+// CreateAIBattleWithConfig 按对战配置（规则预设）创建人机对战
+func (h *Handler) CreateAIBattleWithConfig(channelID, playerID, username string, config *valueobject.BattleConfig) (*entity.Battle, error) {
 	if h.repo.Exists(channelID) {
 		return nil, fmt.Errorf("该频道已有对战进行中")
 	}
-	battle := entity.NewAIBattle(uuid.New().String(), channelID, teamSize)
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	battle := entity.NewAIBattleWithConfig(uuid.New().String(), channelID, config)
 	// 玩家加入
 	if err := battle.AddPlayer(playerID, username); err != nil {
 		return nil, err
@@ -115,7 +139,7 @@ func (h *Handler) CreateAIBattle(channelID, playerID, username string, teamSize
 		return nil, err
 	}
 	// AI 自动选择宝可梦
-	if err := h.aiSelectPokemon(battle, teamSize); err != nil {
+	if err := h.aiSelectPokemon(battle, battle.TeamSize); err != nil {
 		return nil, err
 	}
 	if err := h.repo.Save(battle); err != nil {
@@ -124,6 +148,23 @@ func (h *Handler) CreateAIBattle(channelID, playerID, username string, teamSize
 	return battle, nil
 }
 
+// validateConfig 检查对战配置是否可用
+func validateConfig(config *valueobject.BattleConfig) error {
+	if config == nil {
+		return fmt.Errorf("未知的对战规则")
+	}
+	switch config.Mode {
+	case valueobject.ModeSingle:
+		return nil
+	case valueobject.ModeDouble:
+		if config.BringCount < 2 {
+			return fmt.Errorf("双打至少需要 2 只宝可梦")
+		}
+		return nil
+	}
+	return fmt.Errorf("暂不支持%s模式", config.Mode.DisplayName())
+}
+
 // aiSelectPokemon AI 自动选择宝可梦
 func (h *Handler) aiSelectPokemon(battle *entity.Battle, teamSize entity.TeamSize) error {
 	// 热门宝可梦 ID 列表（用于 AI 选择）
@@ -152,12 +193,13 @@ func (h *Handler) aiSelectPokemon(battle *entity.Battle, teamSize entity.TeamSiz
 
 	count := int(teamSize)
 	selected := 0
+	usedItems := make(map[int]bool) // 道具条款：不携带重复的道具
 
 	for _, pokemonID := range popularPokemonIDs {
 		if selected >= count {
 			break
 		}
-		pokemon := pokeapi.GetPredefinedPokemon(pokemonID)
+		pokemon := pokeapi.GetPokemonInFormat(pokemonID, battle.Config.Learnset)
 		if pokemon == nil {
 			continue
 		}
@@ -171,14 +213,25 @@ func (h *Handler) aiSelectPokemon(battle *entity.Battle, teamSize entity.TeamSiz
 		}
 		pokemon.Nature = natures[rand.Intn(len(natures))]
 
-		// 选择前4个技能
-		if len(pokemon.LearnableMoves) > 4 {
-			pokemon.LearnableMoves = pokemon.LearnableMoves[:4]
+		// 选择前4个符合规则的技能
+		var moves []*entity.Move
+		for _, move := range pokemon.LearnableMoves {
+			if len(moves) >= 4 {
+				break
+			}
+			if ok, _ := battle.IsMoveAllowed(move); ok {
+				moves = append(moves, move)
+			}
 		}
+		pokemon.LearnableMoves = moves
+		pokemon.HeldItem = aiChooseGimmickItem(battle, pokemon, usedItems)
 
-		if err := battle.SetPokemon(entity.AIPlayerID, pokemon, 50); err != nil {
+		if err := battle.SetPokemon(entity.AIPlayerID, pokemon, 50, pokeapi.CanLearnMove); err != nil {
 			continue
 		}
+		if pokemon.HeldItem != nil {
+			usedItems[pokemon.HeldItem.ID] = true
+		}
 		selected++
 	}
 
@@ -188,10 +241,46 @@ func (h *Handler) aiSelectPokemon(battle *entity.Battle, teamSize entity.TeamSiz
 	return nil
 }
 
+// aiChooseGimmickItem AI 为宝可梦选择特殊系统道具：可超级进化时携带超级石，否则携带最强攻击技能属性的Z纯晶
+func aiChooseGimmickItem(battle *entity.Battle, pokemon *entity.Pokemon, usedItems map[int]bool) *valueobject.Item {
+	if battle.GimmickAllowed(valueobject.GimmickMegaEvo) {
+		stones := pokeapi.GetMegaStones(pokemon.ID)
+		if len(stones) > 0 {
+			stone := stones[rand.Intn(len(stones))]
+			if !usedItems[stone.ID] {
+				return stone
+			}
+		}
+	}
+	if battle.GimmickAllowed(valueobject.GimmickZMove) {
+		var strongest *entity.Move
+		for _, move := range pokemon.LearnableMoves {
+			if move.Power > 0 && (strongest == nil || move.Power > strongest.Power) {
+				strongest = move
+			}
+		}
+		if strongest != nil {
+			if crystal := pokeapi.GetZCrystal(strongest.Type); crystal != nil && !usedItems[crystal.ID] {
+				return crystal
+			}
+		}
+	}
+	return nil
+}
+
 // AIChooseAction AI 选择行动
 func (h *Handler) AIChooseAction(battle *entity.Battle) *entity.BattleAction {
+	return h.AIChooseActionForSlot(battle, 0)
+}
+
+// AIChooseActionForSlot AI 为指定场上位置选择行动（双打时同时选择目标）
+func (h *Handler) AIChooseActionForSlot(battle *entity.Battle, slot int) *entity.BattleAction {
 	aiPlayer := battle.GetAIPlayer()
-	if aiPlayer == nil || aiPlayer.Pokemon == nil {
+	if aiPlayer == nil {
+		return nil
+	}
+	battler := aiPlayer.GetSlot(slot)
+	if battler == nil {
 		return nil
 	}
 
@@ -200,28 +289,159 @@ func (h *Handler) AIChooseAction(battle *entity.Battle) *entity.BattleAction {
 		return nil
 	}
 
-	// 简单 AI 策略：选择伤害最高的技能
+	// 可攻击的对手位置
+	var targetSlots []int
+	for targetSlot := 0; targetSlot < battle.ActiveSlotCount(); targetSlot++ {
+		if target := humanPlayer.GetSlot(targetSlot); target != nil && target.IsAlive() {
+			targetSlots = append(targetSlots, targetSlot)
+		}
+	}
+	if len(targetSlots) == 0 {
+		targetSlots = []int{0}
+	}
+
+	// 简单 AI 策略：选择伤害最高的技能与目标
 	bestMoveIdx := 0
+	bestTarget := targetSlots[0]
 	bestScore := 0.0
+	firstUsable := -1 // 没有可造成伤害的技能时使用第一个可选技能
 
-	for idx, move := range aiPlayer.Pokemon.Moves {
+	for idx, move := range battler.Moves {
 		if !move.CanUse() {
 			continue
 		}
+		if ok, _ := battler.CanSelectMove(move); !ok {
+			continue
+		}
+		if ok, _ := battle.IsMoveAllowed(move); !ok {
+			continue
+		}
+		if ok, _ := battle.ItemAllowsMove(battler, move); !ok {
+			continue
+		}
+		if firstUsable < 0 {
+			firstUsable = idx
+		}
 
-		// 计算预估伤害分数
-		score := float64(move.Power)
+		for _, targetSlot := range targetSlots {
+			target := humanPlayer.GetSlot(targetSlot)
+			if target == nil {
+				continue
+			}
 
-		// 属性克制加成
-		effectiveness := valueobject.GetEffectiveness(move.Type, humanPlayer.Pokemon.Pokemon.Types)
-		score *= effectiveness
+			// 计算预估伤害分数
+			score := float64(move.Power)
 
-		// STAB 加成
-		for _, atkType := range aiPlayer.Pokemon.Pokemon.Types {
-			if atkType == move.Type {
-				score *= 1.5
-				break
+			// 属性克制加成
+			effectiveness := valueobject.GetEffectiveness(move.Type, target.Pokemon.Types)
+			score *= effectiveness
+
+			// STAB 加成
+			for _, atkType := range battler.Pokemon.Types {
+				if atkType == move.Type {
+					score *= 1.5
+					break
+				}
+			}
+
+			// 范围技能同时命中多个对手
+			if !battle.MoveNeedsTarget(move) && len(targetSlots) > 1 &&
+				(move.Target == entity.TargetAllOpponents || move.Target == entity.TargetAllOtherPokemon) {
+				score *= entity.SpreadDamageModifier * float64(len(targetSlots))
+			}
+
+			// 添加随机因素避免太机械
+			score *= (0.9 + rand.Float64()*0.2)
+
+			if score > bestScore {
+				bestScore = score
+				bestMoveIdx = idx
+				bestTarget = targetSlot
+			}
+		}
+	}
+
+	if bestScore == 0 && firstUsable >= 0 {
+		bestMoveIdx = firstUsable
+	}
+
+	action := &entity.BattleAction{
+		Type:       entity.ActionMove,
+		MoveIndex:  bestMoveIdx,
+		Slot:       slot,
+		TargetSlot: bestTarget,
+	}
+
+	// 能超级进化时立即超级进化；选中的攻击技能能变为Z招式时使用Z招式；
+	// 选中的攻击技能与太晶属性一致时发动太晶化；只剩最后两只宝可梦时发动极巨化
+	if bestMoveIdx < len(battler.Moves) {
+		move := battler.Moves[bestMoveIdx]
+		if ok, _ := battle.CanUseGimmick(aiPlayer, slot, valueobject.GimmickMegaEvo); ok {
+			action.Gimmick = valueobject.GimmickMegaEvo
+		}
+		if action.Gimmick == "" && move.Power > 0 && battler.CanZMove(move) {
+			if ok, _ := battle.CanUseGimmick(aiPlayer, slot, valueobject.GimmickZMove); ok {
+				action.Gimmick = valueobject.GimmickZMove
+			}
+		}
+		if action.Gimmick == "" && move.Power > 0 && move.Type == battler.TeraType {
+			if ok, _ := battle.CanUseGimmick(aiPlayer, slot, valueobject.GimmickTerastal); ok {
+				action.Gimmick = valueobject.GimmickTerastal
+			}
+		}
+		if action.Gimmick == "" && aiPlayer.GetAliveCount() <= 2 {
+			if ok, _ := battle.CanUseGimmick(aiPlayer, slot, valueobject.GimmickDynamax); ok {
+				action.Gimmick = valueobject.GimmickDynamax
+			}
+		}
+	}
+
+	return action
+}
+
+// AIChooseReplacement AI 选择替补：优先选择能克制对手在场宝可梦、且不被其克制的队员，没有可选队员时返回 -1
+func (h *Handler) AIChooseReplacement(battle *entity.Battle) int {
+	aiPlayer := battle.GetAIPlayer()
+	humanPlayer := battle.GetHumanPlayer()
+	if aiPlayer == nil || humanPlayer == nil {
+		return -1
+	}
+
+	var foe *entity.Battler
+	for _, battler := range humanPlayer.ActiveBattlers() {
+		if battler.IsAlive() {
+			foe = battler
+			break
+		}
+	}
+
+	bestIdx := -1
+	bestScore := 0.0
+	for idx, member := range aiPlayer.Team {
+		if !member.IsAlive() || aiPlayer.IsActive(member) {
+			continue
+		}
+
+		// 剩余HP越多越优先
+		score := 0.5 + member.GetHPPercent()/100
+		if foe != nil {
+			// 攻击技能对对手的最佳克制倍率
+			offense := 1.0
+			for _, move := range member.Moves {
+				if move.Power > 0 && move.CanUse() {
+					if effectiveness := valueobject.GetEffectiveness(move.Type, foe.Types); effectiveness > offense {
+						offense = effectiveness
+					}
+				}
+			}
+			// 对手属性对该队员的最大克制倍率
+			defense := 0.25
+			for _, foeType := range foe.Types {
+				if effectiveness := valueobject.GetEffectiveness(foeType, member.Types); effectiveness > defense {
+					defense = effectiveness
+				}
 			}
+			score *= offense / defense
 		}
 
 		// 添加随机因素避免太机械
@@ -229,14 +449,35 @@ func (h *Handler) AIChooseAction(battle *entity.Battle) *entity.BattleAction {
 
 		if score > bestScore {
 			bestScore = score
-			bestMoveIdx = idx
+			bestIdx = idx
 		}
 	}
+	return bestIdx
+}
 
-	return &entity.BattleAction{
-		Type:      entity.ActionMove,
-		MoveIndex: bestMoveIdx,
+// aiChooseReplacements AI 为所有等待替补的位置选择替补（回合中途的换人会继续执行本回合）
+func (h *Handler) aiChooseReplacements(battle *entity.Battle) []string {
+	if !battle.IsAIBattle {
+		return nil
+	}
+	aiPlayer := battle.GetAIPlayer()
+	if aiPlayer == nil {
+		return nil
+	}
+
+	var logs []string
+	for battle.NeedsReplacement(aiPlayer.ID) {
+		index := h.AIChooseReplacement(battle)
+		if index < 0 {
+			break
+		}
+		replacementLogs, err := battle.ChooseReplacement(aiPlayer.ID, index)
+		if err != nil {
+			break
+		}
+		logs = append(logs, replacementLogs...)
 	}
+	return logs
 }
 
 // ExecuteAITurn 执行 AI 回合（玩家行动后自动触发）
@@ -255,18 +496,29 @@ func (h *Handler) ExecuteAITurn(channelID string) ([]string, error) {
 		return nil, nil
 	}
 
-	// AI 选择行动
-	if aiPlayer.Action == nil {
-		aiAction := h.AIChooseAction(battle)
-		if aiAction != nil {
-			aiPlayer.Action = aiAction
+	// 等待替补时 AI 只选择替补
+	if battle.State == entity.BattleStateReplacing {
+		logs := h.aiChooseReplacements(battle)
+		if err := h.repo.Save(battle); err != nil {
+			return nil, err
 		}
+		return logs, nil
 	}
 
-	// 执行回合
+	// AI 为每个需要行动的位置选择行动
+	for slot := battle.GetPendingSlot(aiPlayer); slot >= 0; slot = battle.GetPendingSlot(aiPlayer) {
+		aiAction := h.AIChooseActionForSlot(battle, slot)
+		if aiAction == nil {
+			break
+		}
+		aiPlayer.SetSlotAction(slot, aiAction)
+	}
+
+	// 执行回合（AI 的宝可梦倒下或使用U转等技能后由 AI 选择替补）
 	var logs []string
 	if battle.BothActionsReady() {
 		logs = battle.ExecuteTurn()
+		logs = append(logs, h.aiChooseReplacements(battle)...)
 	}
 
 	if err := h.repo.Save(battle); err != nil {
@@ -300,59 +552,234 @@ func (h *Handler) SelectPokemon(channelID, playerID string, pokemonID, level int
 		return err
 	}
 
-	pokemon := pokeapi.GetPredefinedPokemon(pokemonID)
+	// 技能索引对应配置的可学技能范围（没有配置时使用对战的可学技能范围）
+	config := h.GetConfig(channelID, playerID)
+	format := battle.Config.Learnset
+	if config != nil {
+		format = config.Format
+	}
+	pokemon := pokeapi.GetPokemonInFormat(pokemonID, format)
 	if pokemon == nil {
 		return fmt.Errorf("未找到宝可梦")
 	}
 
 	// 应用玩家配置
-	config := h.GetConfig(channelID, playerID)
 	if config != nil {
-		// 应用性格
-		if config.Nature != "" {
-			pokemon.Nature = config.Nature
-		}
-		// 应用特性
-		if config.AbilitySlot >= 0 && config.AbilitySlot < len(pokemon.Abilities) {
-			pokemon.SelectedAbility = &pokemon.Abilities[config.AbilitySlot]
-		}
-		// 应用技能选择
-		if len(config.MoveIndices) > 0 {
-			var selectedMoves []*entity.Move
-			for _, idx := range config.MoveIndices {
-				if idx >= 0 && idx < len(pokemon.LearnableMoves) {
-					selectedMoves = append(selectedMoves, pokemon.LearnableMoves[idx])
+		h.applyConfig(pokemon, config)
+		// 清除配置
+		h.ClearConfig(channelID, playerID)
+	}
+
+	if err := battle.SetPokemon(playerID, pokemon, level, pokeapi.CanLearnMove); err != nil {
+		return err
+	}
+
+	// 人机对战进入队伍预览时，AI 根据玩家的队伍选择出战宝可梦
+	if battle.State == entity.BattleStatePreview && battle.IsAIBattle {
+		if err := h.aiChooseBring(battle); err != nil {
+			return err
+		}
+	}
+
+	return h.repo.Save(battle)
+}
+
+// applyConfig 将玩家配置应用到宝可梦
+func (h *Handler) applyConfig(pokemon *entity.Pokemon, config *PokemonConfig) {
+	// 应用性格
+	if config.Nature != "" {
+		pokemon.Nature = config.Nature
+	}
+	// 应用特性（-1 为隐藏特性）
+	if config.AbilitySlot >= 0 && config.AbilitySlot < len(pokemon.Abilities) {
+		pokemon.SelectedAbility = &pokemon.Abilities[config.AbilitySlot]
+	} else if config.AbilitySlot == -1 && pokemon.HiddenAbility != nil {
+		pokemon.SelectedAbility = pokemon.HiddenAbility
+	}
+	// 应用技能选择
+	if len(config.MoveIndices) > 0 {
+		var selectedMoves []*entity.Move
+		for _, idx := range config.MoveIndices {
+			if idx >= 0 && idx < len(pokemon.LearnableMoves) {
+				selectedMoves = append(selectedMoves, pokemon.LearnableMoves[idx])
+			}
+		}
+		if len(selectedMoves) > 0 {
+			pokemon.LearnableMoves = selectedMoves
+		}
+	}
+	// 应用太晶属性
+	if config.TeraType != "" {
+		pokemon.TeraType = config.TeraType
+	}
+	// 应用携带道具
+	pokemon.HeldItem = h.GetItem(config.ItemID)
+	// 应用努力值与个体值
+	pokemon.EVs = copyStats(config.EVs)
+	pokemon.IVs = copyStats(config.IVs)
+}
+
+// ChangeConfigFormat 切换配置的可学技能范围，已选技能在新范围内仍可学会时保留
+func (h *Handler) ChangeConfigFormat(config *PokemonConfig, format valueobject.LearnsetFormat) error {
+	next := pokeapi.GetPokemonInFormat(config.PokemonID, format)
+	if next == nil {
+		return fmt.Errorf("该宝可梦无法在「%s」范围内使用", format.DisplayName())
+	}
+	indices := make([]int, 0, len(config.MoveIndices))
+	if current := pokeapi.GetPokemonInFormat(config.PokemonID, config.Format); current != nil {
+		for _, idx := range config.MoveIndices {
+			if idx < 0 || idx >= len(current.LearnableMoves) {
+				continue
+			}
+			for nextIdx, move := range next.LearnableMoves {
+				if move.ID == current.LearnableMoves[idx].ID {
+					indices = append(indices, nextIdx)
+					break
+				}
+			}
+		}
+	}
+	config.Format = format
+	config.MoveIndices = indices
+	return nil
+}
+
+// PreviewStats 按配置计算指定等级下的实际能力值
+func (h *Handler) PreviewStats(config *PokemonConfig, level int) (entity.Stats, error) {
+	pokemon := pokeapi.GetPokemonInFormat(config.PokemonID, config.Format)
+	if pokemon == nil {
+		return entity.Stats{}, fmt.Errorf("未找到宝可梦")
+	}
+	h.applyConfig(pokemon, config)
+	build := entity.NewPokemonBuild(pokemon)
+	build.Level = level
+	return build.CalculateStats(), nil
+}
+
+// copyStats 复制六维数值（避免配置与预设共享同一份数据）
+func copyStats(stats *entity.Stats) *entity.Stats {
+	if stats == nil {
+		return nil
+	}
+	copied := *stats
+	return &copied
+}
+
+// aiChooseBring AI 在队伍预览中根据玩家的全部宝可梦选择出战成员与首发
+// 评分 = 自身技能对玩家各宝可梦的最佳克制倍率之和 - 玩家各宝可梦属性对自身的克制倍率之和
+func (h *Handler) aiChooseBring(battle *entity.Battle) error {
+	aiPlayer := battle.GetAIPlayer()
+	humanPlayer := battle.GetHumanPlayer()
+	if aiPlayer == nil || humanPlayer == nil {
+		return fmt.Errorf("对战玩家不完整")
+	}
+
+	type candidate struct {
+		index int
+		score float64
+	}
+	candidates := make([]candidate, 0, len(aiPlayer.Roster))
+	for idx, battler := range aiPlayer.Roster {
+		score := 0.0
+		for _, foe := range humanPlayer.Roster {
+			best := 0.0
+			for _, move := range battler.Moves {
+				if move.Power <= 0 {
+					continue
+				}
+				if effectiveness := valueobject.GetEffectiveness(move.Type, foe.Pokemon.Types); effectiveness > best {
+					best = effectiveness
 				}
 			}
-			if len(selectedMoves) > 0 {
-				pokemon.LearnableMoves = selectedMoves
+			score += best
+			for _, foeType := range foe.Pokemon.Types {
+				score -= valueobject.GetEffectiveness(foeType, battler.Pokemon.Types) / 2
 			}
 		}
-		// 应用太晶属性
-		if config.TeraType != "" {
-			pokemon.TeraType = config.TeraType
+		// 添加随机因素避免每次选择相同
+		score *= 0.9 + rand.Float64()*0.2
+		candidates = append(candidates, candidate{index: idx, score: score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	indices := make([]int, 0, battle.BringCount())
+	for _, c := range candidates {
+		if len(indices) >= battle.BringCount() {
+			break
 		}
-		// 清除配置
-		h.ClearConfig(channelID, playerID)
+		indices = append(indices, c.index)
 	}
+	return battle.SetBring(entity.AIPlayerID, indices)
+}
 
-	if err := battle.SetPokemon(playerID, pokemon, level); err != nil {
+// ToggleBring 队伍预览中选择/取消出战宝可梦
+func (h *Handler) ToggleBring(channelID, playerID string, index int) error {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return err
+	}
+	if err := battle.ToggleBring(playerID, index); err != nil {
 		return err
 	}
+	return h.repo.Save(battle)
+}
 
+// ResetBring 队伍预览中清空出战选择
+func (h *Handler) ResetBring(channelID, playerID string) error {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return err
+	}
+	if err := battle.ResetBring(playerID); err != nil {
+		return err
+	}
 	return h.repo.Save(battle)
 }
 
+// ConfirmBring 确认出战选择，返回双方是否都已确认（对战开始）
+func (h *Handler) ConfirmBring(channelID, playerID string) (bool, error) {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return false, err
+	}
+	if err := battle.ConfirmBring(playerID); err != nil {
+		return false, err
+	}
+	if err := h.repo.Save(battle); err != nil {
+		return false, err
+	}
+	return battle.State == entity.BattleStateBattling, nil
+}
+
 // UseMove 使用技能
 func (h *Handler) UseMove(channelID, playerID string, moveIndex int) ([]string, error) {
+	return h.UseMoveWithTarget(channelID, playerID, moveIndex, 0, false, "")
+}
+
+// UseMoveWithTarget 使用技能并指定目标（双打时使用）
+// 行动位置为玩家下一个尚未选择行动的位置；targetSlot 为对手位置，targetAlly 为 true 时以同伴为目标
+// gimmick 不为空时在使用技能前发动特殊系统（如太晶化）
+func (h *Handler) UseMoveWithTarget(channelID, playerID string, moveIndex, targetSlot int, targetAlly bool, gimmick valueobject.GimmickSystem) ([]string, error) {
 	battle, err := h.repo.FindByChannelID(channelID)
 	if err != nil {
 		return nil, err
 	}
 
+	slot, err := pendingSlot(battle, playerID)
+	if err != nil {
+		return nil, err
+	}
+
 	action := &entity.BattleAction{
-		Type:      entity.ActionMove,
-		MoveIndex: moveIndex,
+		Type:       entity.ActionMove,
+		MoveIndex:  moveIndex,
+		Slot:       slot,
+		TargetSlot: targetSlot,
+		TargetAlly: targetAlly,
+		Gimmick:    gimmick,
 	}
 
 	if err := battle.SetAction(playerID, action); err != nil {
@@ -363,6 +790,7 @@ func (h *Handler) UseMove(channelID, playerID string, moveIndex int) ([]string,
 	var logs []string
 	if battle.BothActionsReady() {
 		logs = battle.ExecuteTurn()
+		logs = append(logs, h.aiChooseReplacements(battle)...)
 	}
 
 	if err := h.repo.Save(battle); err != nil {
@@ -379,6 +807,18 @@ func (h *Handler) Forfeit(channelID, playerID string) ([]string, error) {
 		return nil, err
 	}
 
+	// 等待替补时无法选择行动，直接认输
+	if battle.State == entity.BattleStateReplacing {
+		logs, err := battle.Forfeit(playerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.repo.Save(battle); err != nil {
+			return nil, err
+		}
+		return logs, nil
+	}
+
 	action := &entity.BattleAction{
 		Type: entity.ActionForfeit,
 	}
@@ -422,13 +862,19 @@ func (h *Handler) SwitchPokemon(channelID, playerID string, switchIndex int) ([]
 	if !target.IsAlive() {
 		return nil, fmt.Errorf("该宝可梦已倒下")
 	}
-	if target == player.Pokemon {
+	if player.IsActive(target) {
 		return nil, fmt.Errorf("该宝可梦已在场上")
 	}
 
+	slot, err := pendingSlot(battle, playerID)
+	if err != nil {
+		return nil, err
+	}
+
 	action := &entity.BattleAction{
 		Type:        entity.ActionSwitch,
 		SwitchIndex: switchIndex,
+		Slot:        slot,
 	}
 
 	if err := battle.SetAction(playerID, action); err != nil {
@@ -439,6 +885,7 @@ func (h *Handler) SwitchPokemon(channelID, playerID string, switchIndex int) ([]
 	var logs []string
 	if battle.BothActionsReady() {
 		logs = battle.ExecuteTurn()
+		logs = append(logs, h.aiChooseReplacements(battle)...)
 	}
 
 	if err := h.repo.Save(battle); err != nil {
@@ -448,6 +895,45 @@ func (h *Handler) SwitchPokemon(channelID, playerID string, switchIndex int) ([]
 	return logs, nil
 }
 
+// ChooseReplacement 选择替补上场（宝可梦倒下或使用U转等技能后）
+func (h *Handler) ChooseReplacement(channelID, playerID string, switchIndex int) ([]string, error) {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := battle.ChooseReplacement(playerID, switchIndex)
+	if err != nil {
+		return nil, err
+	}
+	logs = append(logs, h.aiChooseReplacements(battle)...)
+
+	if err := h.repo.Save(battle); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// pendingSlot 获取玩家下一个需要选择行动的场上位置
+func pendingSlot(battle *entity.Battle, playerID string) (int, error) {
+	if battle.State == entity.BattleStateReplacing {
+		return 0, fmt.Errorf("等待替补上场中，暂时不能选择行动")
+	}
+	if battle.State != entity.BattleStateBattling {
+		return 0, fmt.Errorf("对战未开始")
+	}
+	player := battle.GetPlayer(playerID)
+	if player == nil {
+		return 0, fmt.Errorf("你不在对战中")
+	}
+	slot := battle.GetPendingSlot(player)
+	if slot < 0 {
+		return 0, fmt.Errorf("你已选择行动，等待对手...")
+	}
+	return slot, nil
+}
+
 // EndBattle 结束对战
 func (h *Handler) EndBattle(channelID string) error {
 	return h.repo.Delete(channelID)
@@ -463,96 +949,52 @@ func (h *Handler) GetPokemonByID(id int) *entity.Pokemon {
 	return pokeapi.GetPredefinedPokemon(id)
 }
 
-// GetSpriteURL 获取精灵图URL
-func (h *Handler) GetSpriteURL(pokemonID int) string {
-	return pokeapi.GetSpriteURL(pokemonID)
+// GetPokemonInFormat 通过ID获取宝可梦（可学技能按指定范围列出，范围内不可用时返回 nil）
+func (h *Handler) GetPokemonInFormat(id int, format valueobject.LearnsetFormat) *entity.Pokemon {
+	return pokeapi.GetPokemonInFormat(id, format)
 }
 
-// IsPlayerInBattle 检查玩家是否在对战中
-func (h *Handler) IsPlayerInBattle(playerID string) bool {
-	_, err := h.repo.FindByPlayerID(playerID)
-	return err == nil
+// SearchPokemon 按名称或ID搜索在指定可学技能范围内可用的宝可梦
+func (h *Handler) SearchPokemon(keyword string, format valueobject.LearnsetFormat) []*entity.Pokemon {
+	return pokeapi.SearchPredefinedPokemon(keyword, format)
 }
 
-// SavePreset 保存配队预设
-func (h *Handler) SavePreset(userID, name string, config *PokemonConfig) (*TeamPreset, error) {
-	pokemon := pokeapi.GetPredefinedPokemon(config.PokemonID)
-	if pokemon == nil {
-		return nil, fmt.Errorf("未找到宝可梦")
-	}
-
-	preset := &TeamPreset{
-		ID:          uuid.New().String()[:8],
-		Name:        name,
-		UserID:      userID,
-		PokemonID:   config.PokemonID,
-		PokemonName: pokemon.Name,
-		Nature:      config.Nature,
-		AbilitySlot: config.AbilitySlot,
-		MoveIndices: append([]int{}, config.MoveIndices...),
-		TeraType:    config.TeraType,
-	}
-
-	h.presetMu.Lock()
-	defer h.presetMu.Unlock()
-
-	// 检查是否超过最大数量（最多10个预设）
-	if len(h.presets[userID]) >= 10 {
-		return nil, fmt.Errorf("预设数量已达上限（10个）")
-	}
-
-	h.presets[userID] = append(h.presets[userID], preset)
-	return preset, nil
+// GetMoveLearn 获取宝可梦在可学技能范围内学会某个技能的方式
+func (h *Handler) GetMoveLearn(pokemonID, moveID int, format valueobject.LearnsetFormat) (valueobject.MoveLearn, bool) {
+	return pokeapi.GetMoveLearn(pokemonID, moveID, format)
 }
 
-// GetPresets 获取用户所有预设
-func (h *Handler) GetPresets(userID string) []*TeamPreset {
-	h.presetMu.RLock()
-	defer h.presetMu.RUnlock()
-	return h.presets[userID]
+// GetEvolutionChain 获取宝可梦所属的进化链（不会进化的宝可梦返回 nil）
+func (h *Handler) GetEvolutionChain(pokemonID int) valueobject.EvolutionChain {
+	return pokeapi.GetEvolutionChain(pokemonID)
 }
 
-// GetPreset 获取指定预设
-func (h *Handler) GetPreset(userID, presetID string) *TeamPreset {
-	h.presetMu.RLock()
-	defer h.presetMu.RUnlock()
-	for _, p := range h.presets[userID] {
-		if p.ID == presetID {
-			return p
-		}
+// LearnsetFormatFor 获取频道对战默认的可学技能范围（没有对战时为全国图鉴）
+func (h *Handler) LearnsetFormatFor(channelID string) valueobject.LearnsetFormat {
+	if battle, err := h.repo.FindByChannelID(channelID); err == nil {
+		return battle.Config.Learnset
 	}
-	return nil
+	return valueobject.LearnsetNational
 }
 
-// DeletePreset 删除预设
-func (h *Handler) DeletePreset(userID, presetID string) error {
-	h.presetMu.Lock()
-	defer h.presetMu.Unlock()
+// GetSpriteURL 获取精灵图URL
+func (h *Handler) GetSpriteURL(pokemonID int) string {
+	return pokeapi.GetSpriteURL(pokemonID)
+}
 
-	presets := h.presets[userID]
-	for i, p := range presets {
-		if p.ID == presetID {
-			h.presets[userID] = append(presets[:i], presets[i+1:]...)
-			return nil
-		}
-	}
-	return fmt.Errorf("预设不存在")
+// IsPlayerInBattle 检查玩家是否在对战中
+func (h *Handler) IsPlayerInBattle(playerID string) bool {
+	_, err := h.repo.FindByPlayerID(playerID)
+	return err == nil
 }
 
-// LoadPresetToConfig 加载预设到当前配置
-func (h *Handler) LoadPresetToConfig(channelID, userID, presetID string) error {
-	preset := h.GetPreset(userID, presetID)
-	if preset == nil {
-		return fmt.Errorf("预设不存在")
+// GetItem 通过ID获取携带道具（常规道具优先，其次为超级石/Z纯晶），0 或未知ID返回 nil
+func (h *Handler) GetItem(id int) *valueobject.Item {
+	if id <= 0 {
+		return nil
 	}
-
-	config := &PokemonConfig{
-		PokemonID:   preset.PokemonID,
-		Nature:      preset.Nature,
-		AbilitySlot: preset.AbilitySlot,
-		MoveIndices: append([]int{}, preset.MoveIndices...),
-		TeraType:    preset.TeraType,
+	if item := valueobject.GetItemByID(id); item != nil {
+		return item
 	}
-	h.SetConfig(channelID, userID, config)
-	return nil
+	return pokeapi.GetItem(id)
 }
@@ -0,0 +1,400 @@
+package pokemon
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+	"github.com/user/dcminigames/internal/infrastructure/pokeapi"
+)
+
+const (
+	// maxPresetsPerUser 每个用户最多保存的预设数量
+	maxPresetsPerUser = 10
+	// maxPresetMembers 每个预设最多的宝可梦数量
+	maxPresetMembers = 6
+)
+
+// PresetFormats 预设可选的适用规则（空字符串表示通用）
+var PresetFormats = append([]string{"", valueobject.RulesetQuick}, valueobject.RulesetIDs...)
+
+// TeamPreset 配队预设（最多 6 只宝可梦的完整配置）
+type TeamPreset struct {
+	ID      string          // 预设ID
+	Name    string          // 预设名称
+	UserID  string          // 用户ID
+	Format  string          // 适用的规则预设ID（空=通用）
+	Members []*PresetMember // 队伍成员（按出场顺序）
+}
+
+// PresetMember 预设中的一只宝可梦
+type PresetMember struct {
+	PokemonName string         // 宝可梦名称（显示用）
+	Config      *PokemonConfig // 完整配置（性格、特性、技能、道具、努力值/个体值、太晶属性）
+}
+
+// FormatDisplayName 获取预设适用规则的显示名称
+func (p *TeamPreset) FormatDisplayName() string {
+	if p.Format == "" {
+		return "通用"
+	}
+	return valueobject.RulesetDisplayName(p.Format)
+}
+
+// MatchesBattle 预设是否适用于指定对战（通用预设适用于所有对战）
+func (p *TeamPreset) MatchesBattle(battle *entity.Battle) bool {
+	return p.Format == "" || battle == nil || p.Format == battle.Config.RulesetID()
+}
+
+// PresetRepository 配队预设仓储（按用户整体保存）
+type PresetRepository interface {
+	// Save 保存用户的全部预设（为空时删除）
+	Save(userID string, presets []*TeamPreset) error
+	// FindAll 获取所有用户的预设（key: userID）
+	FindAll() (map[string][]*TeamPreset, error)
+}
+
+// RestorePresets 从仓储恢复所有用户的预设，返回恢复的预设数量
+func (h *Handler) RestorePresets() (int, error) {
+	all, err := h.presetRepo.FindAll()
+	if err != nil {
+		return 0, fmt.Errorf("恢复配队预设失败: %w", err)
+	}
+
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	count := 0
+	for userID, presets := range all {
+		h.presets[userID] = presets
+		count += len(presets)
+	}
+	return count, nil
+}
+
+// persistPresets 保存用户的全部预设到仓储（调用方需持有锁）
+func (h *Handler) persistPresets(userID string) error {
+	if err := h.presetRepo.Save(userID, h.presets[userID]); err != nil {
+		return fmt.Errorf("保存预设失败: %w", err)
+	}
+	return nil
+}
+
+// copyConfig 深拷贝配置（避免预设与当前配置共享同一份数据）
+func copyConfig(config *PokemonConfig) *PokemonConfig {
+	return &PokemonConfig{
+		PokemonID:   config.PokemonID,
+		Nature:      config.Nature,
+		AbilitySlot: config.AbilitySlot,
+		MoveIndices: append([]int{}, config.MoveIndices...),
+		Format:      config.Format,
+		TeraType:    config.TeraType,
+		ItemID:      config.ItemID,
+		EVs:         copyStats(config.EVs),
+		IVs:         copyStats(config.IVs),
+	}
+}
+
+// newPresetMember 根据配置创建预设成员
+func newPresetMember(config *PokemonConfig) (*PresetMember, error) {
+	pokemon := pokeapi.GetPredefinedPokemon(config.PokemonID)
+	if pokemon == nil {
+		return nil, fmt.Errorf("未找到宝可梦")
+	}
+	return &PresetMember{PokemonName: pokemon.Name, Config: copyConfig(config)}, nil
+}
+
+// configFromBuild 将对战中的宝可梦配置还原为玩家配置（技能与特性按图鉴数据重新定位）
+func configFromBuild(build *entity.PokemonBuild) *PokemonConfig {
+	evs, ivs := build.EVs, build.IVs
+	config := &PokemonConfig{
+		PokemonID:   build.Pokemon.ID,
+		Nature:      build.Nature,
+		TeraType:    build.TeraType,
+		MoveIndices: []int{},
+		EVs:         &evs,
+		IVs:         &ivs,
+	}
+	if build.Item != nil {
+		config.ItemID = build.Item.ID
+	}
+
+	pokemon := pokeapi.GetPredefinedPokemon(build.Pokemon.ID)
+	if pokemon == nil {
+		return config
+	}
+	if build.Ability != nil {
+		for idx, ability := range pokemon.Abilities {
+			if ability.ID == build.Ability.ID {
+				config.AbilitySlot = idx
+			}
+		}
+		if pokemon.HiddenAbility != nil && pokemon.HiddenAbility.ID == build.Ability.ID {
+			config.AbilitySlot = -1
+		}
+	}
+	for _, move := range build.Moves {
+		for idx, learnable := range pokemon.LearnableMoves {
+			if learnable.ID == move.ID {
+				config.MoveIndices = append(config.MoveIndices, idx)
+				break
+			}
+		}
+	}
+	return config
+}
+
+// addPreset 保存新预设（调用方需持有锁）
+func (h *Handler) addPreset(preset *TeamPreset) error {
+	if len(h.presets[preset.UserID]) >= maxPresetsPerUser {
+		return fmt.Errorf("预设数量已达上限（%d个）", maxPresetsPerUser)
+	}
+	h.presets[preset.UserID] = append(h.presets[preset.UserID], preset)
+	return h.persistPresets(preset.UserID)
+}
+
+// SavePreset 将当前配置保存为新的配队预设
+func (h *Handler) SavePreset(userID, name string, config *PokemonConfig) (*TeamPreset, error) {
+	member, err := newPresetMember(config)
+	if err != nil {
+		return nil, err
+	}
+
+	preset := &TeamPreset{
+		ID:      uuid.New().String()[:8],
+		Name:    name,
+		UserID:  userID,
+		Members: []*PresetMember{member},
+	}
+
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	if err := h.addPreset(preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// SaveTeamAsPreset 将玩家在当前对战中报名的整支队伍保存为配队预设
+func (h *Handler) SaveTeamAsPreset(channelID, userID, name string) (*TeamPreset, error) {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("没有进行中的对战")
+	}
+	player := battle.GetPlayer(userID)
+	if player == nil {
+		return nil, fmt.Errorf("你不在对战中")
+	}
+	if len(player.Roster) == 0 {
+		return nil, fmt.Errorf("你还没有选择宝可梦")
+	}
+
+	preset := &TeamPreset{
+		ID:      uuid.New().String()[:8],
+		Name:    name,
+		UserID:  userID,
+		Format:  battle.Config.RulesetID(),
+		Members: make([]*PresetMember, 0, len(player.Roster)),
+	}
+	for _, member := range player.Roster {
+		preset.Members = append(preset.Members, &PresetMember{
+			PokemonName: member.Build.Pokemon.Name,
+			Config:      configFromBuild(member.Build),
+		})
+	}
+
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	if err := h.addPreset(preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// AddToPreset 将当前配置加入已有的预设
+func (h *Handler) AddToPreset(userID, presetID string, config *PokemonConfig) (*TeamPreset, error) {
+	member, err := newPresetMember(config)
+	if err != nil {
+		return nil, err
+	}
+
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	preset := h.findPreset(userID, presetID)
+	if preset == nil {
+		return nil, fmt.Errorf("预设不存在")
+	}
+	if len(preset.Members) >= maxPresetMembers {
+		return nil, fmt.Errorf("预设最多只能有 %d 只宝可梦", maxPresetMembers)
+	}
+	preset.Members = append(preset.Members, member)
+	if err := h.persistPresets(userID); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// DuplicatePreset 复制预设
+func (h *Handler) DuplicatePreset(userID, presetID string) (*TeamPreset, error) {
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	source := h.findPreset(userID, presetID)
+	if source == nil {
+		return nil, fmt.Errorf("预设不存在")
+	}
+
+	preset := &TeamPreset{
+		ID:      uuid.New().String()[:8],
+		Name:    source.Name + " (副本)",
+		UserID:  userID,
+		Format:  source.Format,
+		Members: make([]*PresetMember, 0, len(source.Members)),
+	}
+	for _, member := range source.Members {
+		preset.Members = append(preset.Members, &PresetMember{PokemonName: member.PokemonName, Config: copyConfig(member.Config)})
+	}
+	if err := h.addPreset(preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// MovePresetMember 调整预设成员顺序（delta 为 -1 上移、1 下移）
+func (h *Handler) MovePresetMember(userID, presetID string, index, delta int) error {
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	preset := h.findPreset(userID, presetID)
+	if preset == nil {
+		return fmt.Errorf("预设不存在")
+	}
+	target := index + delta
+	if index < 0 || index >= len(preset.Members) || target < 0 || target >= len(preset.Members) {
+		return fmt.Errorf("无法移动")
+	}
+	preset.Members[index], preset.Members[target] = preset.Members[target], preset.Members[index]
+	return h.persistPresets(userID)
+}
+
+// RemovePresetMember 从预设中移除一只宝可梦
+func (h *Handler) RemovePresetMember(userID, presetID string, index int) error {
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	preset := h.findPreset(userID, presetID)
+	if preset == nil {
+		return fmt.Errorf("预设不存在")
+	}
+	if index < 0 || index >= len(preset.Members) {
+		return fmt.Errorf("成员不存在")
+	}
+	preset.Members = append(preset.Members[:index], preset.Members[index+1:]...)
+	return h.persistPresets(userID)
+}
+
+// CyclePresetFormat 切换预设的适用规则（按 PresetFormats 顺序循环）
+func (h *Handler) CyclePresetFormat(userID, presetID string) (*TeamPreset, error) {
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+	preset := h.findPreset(userID, presetID)
+	if preset == nil {
+		return nil, fmt.Errorf("预设不存在")
+	}
+	next := 0
+	for idx, format := range PresetFormats {
+		if format == preset.Format {
+			next = (idx + 1) % len(PresetFormats)
+			break
+		}
+	}
+	preset.Format = PresetFormats[next]
+	if err := h.persistPresets(userID); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// GetPresets 获取用户所有预设
+func (h *Handler) GetPresets(userID string) []*TeamPreset {
+	h.presetMu.RLock()
+	defer h.presetMu.RUnlock()
+	return h.presets[userID]
+}
+
+// GetPreset 获取指定预设
+func (h *Handler) GetPreset(userID, presetID string) *TeamPreset {
+	h.presetMu.RLock()
+	defer h.presetMu.RUnlock()
+	return h.findPreset(userID, presetID)
+}
+
+// findPreset 查找预设（调用方需持有锁）
+func (h *Handler) findPreset(userID, presetID string) *TeamPreset {
+	for _, p := range h.presets[userID] {
+		if p.ID == presetID {
+			return p
+		}
+	}
+	return nil
+}
+
+// DeletePreset 删除预设
+func (h *Handler) DeletePreset(userID, presetID string) error {
+	h.presetMu.Lock()
+	defer h.presetMu.Unlock()
+
+	presets := h.presets[userID]
+	for i, p := range presets {
+		if p.ID == presetID {
+			h.presets[userID] = append(presets[:i], presets[i+1:]...)
+			return h.persistPresets(userID)
+		}
+	}
+	return fmt.Errorf("预设不存在")
+}
+
+// LoadPresetToConfig 加载预设中的一只宝可梦到当前配置（用于继续编辑）
+func (h *Handler) LoadPresetToConfig(channelID, userID, presetID string, index int) error {
+	preset := h.GetPreset(userID, presetID)
+	if preset == nil {
+		return fmt.Errorf("预设不存在")
+	}
+	if index < 0 || index >= len(preset.Members) {
+		return fmt.Errorf("成员不存在")
+	}
+	h.SetConfig(channelID, userID, copyConfig(preset.Members[index].Config))
+	return nil
+}
+
+// LoadPresetTeam 将预设中的整支队伍依次加入当前对战（按顺序填满剩余空位）
+// 返回成功加入的数量；中途失败时已加入的宝可梦保留在队伍中
+func (h *Handler) LoadPresetTeam(channelID, userID, presetID string, level int) (int, error) {
+	preset := h.GetPreset(userID, presetID)
+	if preset == nil {
+		return 0, fmt.Errorf("预设不存在")
+	}
+	if len(preset.Members) == 0 {
+		return 0, fmt.Errorf("预设中没有宝可梦")
+	}
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return 0, fmt.Errorf("没有进行中的对战")
+	}
+
+	remaining := battle.GetRemainingSlots(userID)
+	if remaining <= 0 {
+		return 0, fmt.Errorf("队伍已满")
+	}
+
+	loaded := 0
+	for _, member := range preset.Members {
+		if loaded >= remaining {
+			break
+		}
+		h.SetConfig(channelID, userID, copyConfig(member.Config))
+		if err := h.SelectPokemon(channelID, userID, member.Config.PokemonID, level); err != nil {
+			h.ClearConfig(channelID, userID)
+			return loaded, fmt.Errorf("%s：%v", member.PokemonName, err)
+		}
+		loaded++
+	}
+	return loaded, nil
+}
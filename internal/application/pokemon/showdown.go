@@ -0,0 +1,456 @@
+package pokemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+	"github.com/user/dcminigames/internal/infrastructure/pokeapi"
+)
+
+// showdownDefaultLevel 未填写等级时使用的等级（Showdown 默认 100，本对战统一为 50 级）
+const showdownDefaultLevel = 50
+
+// showdownStatNames Showdown 能力缩写（HP/攻击/防御/特攻/特防/速度）
+var showdownStatNames = [6]string{"HP", "Atk", "Def", "SpA", "SpD", "Spe"}
+
+// showdownSet Showdown 文本中的一只宝可梦（英文名称，尚未解析）
+type showdownSet struct {
+	Nickname string
+	Species  string
+	Gender   string
+	Item     string
+	Ability  string
+	Level    int
+	Shiny    bool
+	TeraType string
+	Nature   string
+	EVs      *[6]int
+	IVs      *[6]int
+	Moves    []string
+}
+
+// ParseShowdownTeam 解析 Showdown 队伍文本，返回宝可梦配置与提示信息
+// 技能按指定的可学技能范围匹配；无法识别的道具、特性、技能等会被忽略并记录在提示信息中；
+// 无法识别或不能在该范围内使用的宝可梦直接报错
+func (h *Handler) ParseShowdownTeam(text string, format valueobject.LearnsetFormat) ([]*entity.PokemonBuild, []string, error) {
+	sets, err := splitShowdownSets(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sets) == 0 {
+		return nil, nil, fmt.Errorf("没有找到宝可梦，请粘贴 Showdown 格式的队伍")
+	}
+
+	builds := make([]*entity.PokemonBuild, 0, len(sets))
+	warnings := make([]string, 0)
+	for _, set := range sets {
+		build, setWarnings, err := h.buildFromShowdownSet(set, format)
+		if err != nil {
+			return nil, nil, err
+		}
+		builds = append(builds, build)
+		warnings = append(warnings, setWarnings...)
+	}
+	return builds, warnings, nil
+}
+
+// splitShowdownSets 按空行拆分队伍文本并解析每只宝可梦的各行
+func splitShowdownSets(text string) ([]*showdownSet, error) {
+	var sets []*showdownSet
+	var current *showdownSet
+
+	for _, rawLine := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			current = nil
+			continue
+		}
+		// 队伍标题（=== [gen9] Team ===）
+		if strings.HasPrefix(line, "===") {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = parseShowdownHeader(line)
+			sets = append(sets, current)
+			continue
+		}
+		if err := parseShowdownLine(current, line); err != nil {
+			return nil, fmt.Errorf("%s：%v", current.Species, err)
+		}
+	}
+	return sets, nil
+}
+
+// parseShowdownHeader 解析首行：Nickname (Species) (M) @ Item
+func parseShowdownHeader(line string) *showdownSet {
+	set := &showdownSet{Level: showdownDefaultLevel}
+	if idx := strings.LastIndex(line, " @ "); idx >= 0 {
+		set.Item = strings.TrimSpace(line[idx+3:])
+		line = strings.TrimSpace(line[:idx])
+	}
+	if strings.HasSuffix(line, " (M)") || strings.HasSuffix(line, " (F)") {
+		set.Gender = line[len(line)-2 : len(line)-1]
+		line = strings.TrimSpace(line[:len(line)-4])
+	}
+	if strings.HasSuffix(line, ")") {
+		if open := strings.LastIndex(line, " ("); open > 0 {
+			set.Nickname = strings.TrimSpace(line[:open])
+			line = line[open+2 : len(line)-1]
+		}
+	}
+	set.Species = strings.TrimSpace(line)
+	return set
+}
+
+// parseShowdownLine 解析属性行（特性、等级、努力值、性格、技能等）
+func parseShowdownLine(set *showdownSet, line string) error {
+	switch {
+	case strings.HasPrefix(line, "- "):
+		move := strings.TrimSpace(line[2:])
+		// 觉醒力量写作 "Hidden Power [Fire]"，可选技能写作 "Move A / Move B"
+		if idx := strings.Index(move, "["); idx > 0 {
+			move = strings.TrimSpace(move[:idx])
+		}
+		if idx := strings.Index(move, " / "); idx > 0 {
+			move = strings.TrimSpace(move[:idx])
+		}
+		set.Moves = append(set.Moves, move)
+	case strings.HasPrefix(line, "Ability:"):
+		set.Ability = strings.TrimSpace(strings.TrimPrefix(line, "Ability:"))
+	case strings.HasPrefix(line, "Level:"):
+		level, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Level:")))
+		if err != nil || level < 1 || level > 100 {
+			return fmt.Errorf("等级必须在 1-100 之间")
+		}
+		set.Level = level
+	case strings.HasPrefix(line, "Shiny:"):
+		set.Shiny = strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(line, "Shiny:")), "Yes")
+	case strings.HasPrefix(line, "Tera Type:"):
+		set.TeraType = strings.TrimSpace(strings.TrimPrefix(line, "Tera Type:"))
+	case strings.HasPrefix(line, "EVs:"):
+		evs, err := parseShowdownStats(strings.TrimPrefix(line, "EVs:"), 0)
+		if err != nil {
+			return err
+		}
+		set.EVs = &evs
+	case strings.HasPrefix(line, "IVs:"):
+		ivs, err := parseShowdownStats(strings.TrimPrefix(line, "IVs:"), 31)
+		if err != nil {
+			return err
+		}
+		set.IVs = &ivs
+	case strings.HasSuffix(line, " Nature"):
+		set.Nature = strings.TrimSpace(strings.TrimSuffix(line, " Nature"))
+	}
+	// 其他行（亲密度、极巨等级等）对本对战没有影响，直接忽略
+	return nil
+}
+
+// parseShowdownStats 解析 "252 Atk / 4 SpD / 252 Spe"，未写出的项使用默认值
+func parseShowdownStats(text string, defaultValue int) ([6]int, error) {
+	values := [6]int{defaultValue, defaultValue, defaultValue, defaultValue, defaultValue, defaultValue}
+	for _, part := range strings.Split(text, "/") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return values, fmt.Errorf("无法识别的能力值 \"%s\"", strings.TrimSpace(part))
+		}
+		value, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return values, fmt.Errorf("无法识别的能力值 \"%s\"", strings.TrimSpace(part))
+		}
+		found := false
+		for idx, name := range showdownStatNames {
+			if strings.EqualFold(fields[1], name) {
+				values[idx] = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return values, fmt.Errorf("无法识别的能力 \"%s\"", fields[1])
+		}
+	}
+	return values, nil
+}
+
+// statsFromValues 将 HP/攻击/防御/特攻/特防/速度 顺序的数组转换为六维属性
+func statsFromValues(values [6]int) entity.Stats {
+	return entity.Stats{HP: values[0], Atk: values[1], Def: values[2], SpAtk: values[3], SpDef: values[4], Speed: values[5]}
+}
+
+// buildFromShowdownSet 将英文名称映射为中文数据并生成宝可梦配置（可学技能按指定范围列出）
+func (h *Handler) buildFromShowdownSet(set *showdownSet, format valueobject.LearnsetFormat) (*entity.PokemonBuild, []string, error) {
+	warnings := make([]string, 0)
+
+	pokemonID, exact := pokeapi.FindPokemonIDByEnglishName(set.Species)
+	pokemon := pokeapi.GetPokemonInFormat(pokemonID, format)
+	if pokemon == nil {
+		if known := pokeapi.GetPredefinedPokemon(pokemonID); known != nil {
+			return nil, nil, fmt.Errorf("%s 无法在「%s」范围内使用", known.Name, format.DisplayName())
+		}
+		return nil, nil, fmt.Errorf("无法识别的宝可梦：%s", set.Species)
+	}
+	if !exact {
+		warnings = append(warnings, fmt.Sprintf("暂不支持形态 %s，已使用 %s", set.Species, pokemon.Name))
+	}
+
+	build := entity.NewPokemonBuild(pokemon)
+	build.Level = set.Level
+	build.Nickname = set.Nickname
+	build.Shiny = set.Shiny
+
+	switch set.Gender {
+	case "M":
+		build.Gender = entity.GenderMale
+	case "F":
+		build.Gender = entity.GenderFemale
+	}
+	build.AssignRandomGender()
+
+	// 道具：先查携带道具，再查超级石与Z纯晶
+	if set.Item != "" {
+		if item := valueobject.GetItemByEnglishName(set.Item); item != nil {
+			build.Item = item
+		} else if item := pokeapi.FindItemByEnglishName(set.Item); item != nil {
+			build.Item = item
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s：暂不支持道具 %s，已忽略", pokemon.Name, set.Item))
+		}
+	}
+
+	// 特性：只能选择该宝可梦拥有的特性
+	if set.Ability != "" {
+		abilityID := pokeapi.FindAbilityIDByEnglishName(set.Ability)
+		if ability := findPokemonAbility(pokemon, abilityID); ability != nil {
+			build.Ability = ability
+		} else {
+			// 没有普通特性的宝可梦使用隐藏特性
+			if build.Ability == nil {
+				build.Ability = pokemon.HiddenAbility
+			}
+			if build.Ability != nil {
+				warnings = append(warnings, fmt.Sprintf("%s：没有特性 %s，已使用 %s", pokemon.Name, set.Ability, build.Ability.Name))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s：没有特性 %s，已忽略", pokemon.Name, set.Ability))
+			}
+		}
+	}
+
+	if set.Nature != "" {
+		if nature, ok := valueobject.GetNatureByEnglishName(set.Nature); ok {
+			build.Nature = nature
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s：无法识别的性格 %s，已忽略", pokemon.Name, set.Nature))
+		}
+	}
+
+	if set.TeraType != "" {
+		if teraType, ok := valueobject.GetTypeByEnglishName(set.TeraType); ok {
+			build.TeraType = teraType
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s：暂不支持太晶属性 %s，已忽略", pokemon.Name, set.TeraType))
+		}
+	}
+
+	if set.EVs != nil {
+		evs := statsFromValues(*set.EVs)
+		if err := evs.ValidateEVs(); err != nil {
+			return nil, nil, fmt.Errorf("%s：%v", pokemon.Name, err)
+		}
+		build.EVs = evs
+	}
+	if set.IVs != nil {
+		ivs := statsFromValues(*set.IVs)
+		if err := ivs.ValidateIVs(); err != nil {
+			return nil, nil, fmt.Errorf("%s：%v", pokemon.Name, err)
+		}
+		build.IVs = ivs
+	}
+
+	// 技能：只能选择该宝可梦可以学会的技能
+	moves := make([]*entity.Move, 0, 4)
+	for _, name := range set.Moves {
+		if len(moves) >= 4 {
+			break
+		}
+		move := findLearnableMove(pokemon, pokeapi.FindMoveIDByEnglishName(name))
+		if move == nil {
+			warnings = append(warnings, fmt.Sprintf("%s：在「%s」范围内无法学会 %s，已忽略", pokemon.Name, format.DisplayName(), name))
+			continue
+		}
+		moves = append(moves, move)
+	}
+	if len(moves) == 0 {
+		for i := 0; i < 4 && i < len(pokemon.LearnableMoves); i++ {
+			moves = append(moves, pokemon.LearnableMoves[i])
+		}
+		if len(set.Moves) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s：没有可用的技能，已使用默认技能", pokemon.Name))
+		}
+	}
+	build.SetMoves(moves)
+
+	return build, warnings, nil
+}
+
+// findPokemonAbility 在宝可梦的特性（含隐藏特性）中查找
+func findPokemonAbility(pokemon *entity.Pokemon, abilityID int) *valueobject.Ability {
+	if abilityID == 0 {
+		return nil
+	}
+	for i := range pokemon.Abilities {
+		if pokemon.Abilities[i].ID == abilityID {
+			return &pokemon.Abilities[i]
+		}
+	}
+	if pokemon.HiddenAbility != nil && pokemon.HiddenAbility.ID == abilityID {
+		return pokemon.HiddenAbility
+	}
+	return nil
+}
+
+// findLearnableMove 在宝可梦的可学技能中查找
+func findLearnableMove(pokemon *entity.Pokemon, moveID int) *entity.Move {
+	if moveID == 0 {
+		return nil
+	}
+	for _, move := range pokemon.LearnableMoves {
+		if move.ID == moveID {
+			return move
+		}
+	}
+	return nil
+}
+
+// FormatShowdownTeam 将宝可梦配置导出为 Showdown 队伍文本
+func (h *Handler) FormatShowdownTeam(builds []*entity.PokemonBuild) string {
+	sets := make([]string, 0, len(builds))
+	for _, build := range builds {
+		sets = append(sets, formatShowdownSet(build))
+	}
+	return strings.Join(sets, "\n")
+}
+
+// formatShowdownSet 导出一只宝可梦（没有英文名称时保留中文名称）
+func formatShowdownSet(build *entity.PokemonBuild) string {
+	var sb strings.Builder
+
+	species := englishOr(pokeapi.GetPokemonEnglishName(build.Pokemon.ID), build.Pokemon.Name)
+	if build.Nickname != "" && build.Nickname != species {
+		sb.WriteString(fmt.Sprintf("%s (%s)", build.Nickname, species))
+	} else {
+		sb.WriteString(species)
+	}
+	switch build.Gender {
+	case entity.GenderMale:
+		sb.WriteString(" (M)")
+	case entity.GenderFemale:
+		sb.WriteString(" (F)")
+	}
+	if build.Item != nil {
+		sb.WriteString(" @ " + itemEnglishName(build.Item))
+	}
+	sb.WriteString("\n")
+
+	if build.Ability != nil {
+		sb.WriteString("Ability: " + englishOr(pokeapi.GetAbilityEnglishName(build.Ability.ID), build.Ability.Name) + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("Level: %d\n", build.Level))
+	if build.Shiny {
+		sb.WriteString("Shiny: Yes\n")
+	}
+	if teraType := build.TeraType.EnglishName(); teraType != "" {
+		sb.WriteString("Tera Type: " + teraType + "\n")
+	}
+	if evs := formatShowdownStats(build.EVs, 0); evs != "" {
+		sb.WriteString("EVs: " + evs + "\n")
+	}
+	if nature := build.Nature.EnglishName(); nature != "" {
+		sb.WriteString(nature + " Nature\n")
+	}
+	if ivs := formatShowdownStats(build.IVs, 31); ivs != "" {
+		sb.WriteString("IVs: " + ivs + "\n")
+	}
+	for _, move := range build.Moves {
+		sb.WriteString("- " + englishOr(pokeapi.GetMoveEnglishName(move.ID), move.Name) + "\n")
+	}
+	return sb.String()
+}
+
+// formatShowdownStats 格式化为 "252 Atk / 4 SpD / 252 Spe"，省略等于默认值的项
+func formatShowdownStats(stats entity.Stats, defaultValue int) string {
+	parts := make([]string, 0, 6)
+	for idx, value := range stats.Values() {
+		if value != defaultValue {
+			parts = append(parts, fmt.Sprintf("%d %s", value, showdownStatNames[idx]))
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// itemEnglishName 获取道具英文名称（超级石与Z纯晶来自 PokeAPI 数据）
+func itemEnglishName(item *valueobject.Item) string {
+	if item.Category == valueobject.ItemCategoryMega || item.Category == valueobject.ItemCategoryZCrystal {
+		return englishOr(pokeapi.GetItemEnglishName(item.ID), item.Name)
+	}
+	return englishOr(valueobject.ItemEnglishName(item.ID), item.Name)
+}
+
+// englishOr 英文名称为空时使用中文名称
+func englishOr(english, fallback string) string {
+	if english != "" {
+		return english
+	}
+	return fallback
+}
+
+// ImportTeam 导入 Showdown 队伍文本并加入玩家队伍
+func (h *Handler) ImportTeam(channelID, playerID, text string) ([]*entity.PokemonBuild, []string, error) {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("没有进行中的对战")
+	}
+
+	builds, warnings, err := h.ParseShowdownTeam(text, battle.Config.Learnset)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := battle.AddTeamBuilds(playerID, builds, pokeapi.CanLearnMove); err != nil {
+		return nil, nil, err
+	}
+	h.ClearConfig(channelID, playerID)
+
+	// 人机对战进入队伍预览时，AI 根据玩家的队伍选择出战宝可梦
+	if battle.State == entity.BattleStatePreview && battle.IsAIBattle {
+		if err := h.aiChooseBring(battle); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return builds, warnings, h.repo.Save(battle)
+}
+
+// ExportTeam 将玩家在当前对战中报名的队伍导出为 Showdown 文本
+func (h *Handler) ExportTeam(channelID, playerID string) (string, error) {
+	battle, err := h.repo.FindByChannelID(channelID)
+	if err != nil {
+		return "", fmt.Errorf("没有进行中的对战")
+	}
+	player := battle.GetPlayer(playerID)
+	if player == nil {
+		return "", fmt.Errorf("你不在对战中")
+	}
+	if len(player.Roster) == 0 {
+		return "", fmt.Errorf("你还没有选择宝可梦")
+	}
+
+	builds := make([]*entity.PokemonBuild, 0, len(player.Roster))
+	for _, member := range player.Roster {
+		builds = append(builds, member.Build)
+	}
+	return h.FormatShowdownTeam(builds), nil
+}
@@ -5,17 +5,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/user/dcminigames/internal/domain/uno/entity"
+	"github.com/user/dcminigames/internal/domain/uno/repository"
 	"github.com/user/dcminigames/internal/domain/uno/valueobject"
 	"github.com/user/dcminigames/internal/infrastructure/imaging"
-	"github.com/user/dcminigames/internal/infrastructure/persistence/memory"
 )
 
 type Handler struct {
-	repo     *memory.GameRepository
+	repo     repository.GameRepository
 	renderer *imaging.CardRenderer
 }
 
-func NewHandler(repo *memory.GameRepository, renderer *imaging.CardRenderer) *Handler {
+func NewHandler(repo repository.GameRepository, renderer *imaging.CardRenderer) *Handler {
 	return &Handler{repo: repo, renderer: renderer}
 }
 
@@ -24,7 +24,7 @@ const (
 // BattleContext 战斗上下文，用于特性效果处理
 type BattleContext struct {
 	Weather       valueobject.Weather // 当前天气
-	Terrain       string              // 当前场地
+	Terrain       valueobject.Terrain // 当前场地
 	Turn          int                 // 当前回合
 	IsDoubles     bool                // 是否双打
 }
@@ -77,6 +77,7 @@ type DamageModifier struct {
 	Immune        bool    // 是否免疫
 	HealPercent   float64 // 吸收回复比例（如蓄电、储水）
 	TypeOverride  *valueobject.PokeType // 属性覆盖
+	StatBoosts    map[string]int        // 吸收后自身能力变化（如食草、电气引擎）
 }
 
 // NewDamageModifier 创建默认伤害修正
@@ -96,6 +97,7 @@ func NewDamageModifier() *DamageModifier {
 type EntryResult struct {
 	Messages     []string             // 消息
 	WeatherSet   *valueobject.Weather // 设置天气
+	TerrainSet   *valueobject.Terrain // 展开场地
 	StatChanges  map[string]int       // 对手能力变化
 }
 
@@ -107,6 +109,7 @@ type HitResult struct {
 	ContactChance  int            // 触发几率（百分比）
 	RecoilDamage   int            // 反伤伤害
 	StatChanges    map[string]int // 能力变化（对攻击方）
+	DisableMove    bool           // 封印攻击方使用的技能（如诅咒之躯）
 }
 
 // TurnEndResult 回合结束效果结果
@@ -145,13 +148,25 @@ type FormChangeResult struct {
 	NewFormID     int                      // 新形态的宝可梦ID（用于获取新数据）
 	NewFormName   string                   // 新形态名称
 	NewTypes      []valueobject.PokeType   // 新属性（nil表示不变）
-	StatBoosts    map[string]int           // 能力值提升（绝对值，非等级）
+	BaseStats     map[string]int           // 新形态的种族值（hp/atk/def/spatk/spdef/speed，nil表示不变）
 	Messages      []string                 // 形态变化消息
 	SpriteURL     string                   // 新精灵图URL
 	RevertOnExit  bool                     // 退场时是否恢复原形态
 	RevertOnFaint bool                     // 濒死时是否恢复原形态
 }
 
+// FormData 形态数据（形态变化的目标形态，由图鉴提供，见 Registry.SetFormSource）
+type FormData struct {
+	ID        int                    // 形态的宝可梦ID
+	Name      string                 // 形态名称（如"达摩狒狒(达摩模式)"）
+	Types     []valueobject.PokeType // 属性
+	BaseStats map[string]int         // 种族值（hp/atk/def/spatk/spdef/speed）
+	SpriteURL string                 // 精灵图URL
+}
+
+// FormSource 形态数据来源（按宝可梦ID查询，不存在时返回 nil）
+type FormSource func(pokemonID int) *FormData
+
 // Effect 特性效果接口
 type Effect interface {
 	// GetAbilityID 获取对应的特性ID
@@ -191,6 +206,11 @@ type Effect interface {
 	OnFormChange(self Battler, target Battler, ctx *BattleContext) *FormChangeResult
 }
 
+// Redirector 可将单体技能吸引到自己身上的特性（双打中的避雷针、引水）
+type Redirector interface {
+	RedirectsMove(move Move) bool
+}
+
 // BaseEffect 基础效果实现（提供默认空实现）
 type BaseEffect struct {
 	AbilityID int
@@ -341,12 +341,17 @@ func (e *LightningRodEffect) OnDamageCalcDefender(self Battler, attacker Battler
 	if move.GetType() == valueobject.TypeElectric {
 		mod := NewDamageModifier()
 		mod.Immune = true
-		// 注意：特攻提升需要在其他地方处理
+		mod.StatBoosts = map[string]int{"spatk": 1}
 		return mod
 	}
 	return nil
 }
 
+// RedirectsMove 吸引电属性技能
+func (e *LightningRodEffect) RedirectsMove(move Move) bool {
+	return move.GetType() == valueobject.TypeElectric
+}
+
 // VoltAbsorbEffect 蓄电特性
 type VoltAbsorbEffect struct {
 	BaseEffect
@@ -594,12 +599,17 @@ func (e *StormDrainEffect) OnDamageCalcDefender(self Battler, attacker Battler,
 	if move.GetType() == valueobject.TypeWater {
 		mod := NewDamageModifier()
 		mod.Immune = true
-		// 注意：特攻提升需要在其他地方处理
+		mod.StatBoosts = map[string]int{"spatk": 1}
 		return mod
 	}
 	return nil
 }
 
+// RedirectsMove 吸引水属性技能
+func (e *StormDrainEffect) RedirectsMove(move Move) bool {
+	return move.GetType() == valueobject.TypeWater
+}
+
 // SapSipperEffect 食草特性
 type SapSipperEffect struct {
 	BaseEffect
@@ -617,7 +627,7 @@ func (e *SapSipperEffect) OnDamageCalcDefender(self Battler, attacker Battler, m
 	if move.GetType() == valueobject.TypeGrass {
 		mod := NewDamageModifier()
 		mod.Immune = true
-		// 注意：攻击提升需要在其他地方处理
+		mod.StatBoosts = map[string]int{"atk": 1}
 		return mod
 	}
 	return nil
@@ -640,7 +650,7 @@ func (e *MotorDriveEffect) OnDamageCalcDefender(self Battler, attacker Battler,
 	if move.GetType() == valueobject.TypeElectric {
 		mod := NewDamageModifier()
 		mod.Immune = true
-		// 注意：速度提升需要在其他地方处理
+		mod.StatBoosts = map[string]int{"speed": 1}
 		return mod
 	}
 	return nil
@@ -121,10 +121,157 @@ func (e *SnowWarningEffect) GetTriggers() []TriggerType {
 }
 
 func (e *SnowWarningEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
-	hail := valueobject.WeatherHail
+	snow := valueobject.WeatherSnow
 	return &EntryResult{
-		Messages:   []string{"❄️ 降雪开始下冰雹了！"},
-		WeatherSet: &hail,
+		Messages:   []string{"☃️ 降雪使天空下起了雪！"},
+		WeatherSet: &snow,
+	}
+}
+
+// PrimordialSeaEffect 始源之海特性（原始盖欧卡）
+type PrimordialSeaEffect struct {
+	BaseEffect
+}
+
+func (e *PrimordialSeaEffect) GetAbilityID() int {
+	return 189
+}
+
+func (e *PrimordialSeaEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *PrimordialSeaEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	heavyRain := valueobject.WeatherHeavyRain
+	return &EntryResult{
+		Messages:   []string{"⛈️ 始源之海使天空下起了大雨！"},
+		WeatherSet: &heavyRain,
+	}
+}
+
+// DesolateLandEffect 终结之地特性（原始固拉多）
+type DesolateLandEffect struct {
+	BaseEffect
+}
+
+func (e *DesolateLandEffect) GetAbilityID() int {
+	return 190
+}
+
+func (e *DesolateLandEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *DesolateLandEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	harshSun := valueobject.WeatherHarshSun
+	return &EntryResult{
+		Messages:   []string{"🔥 终结之地使日照变得非常强了！"},
+		WeatherSet: &harshSun,
+	}
+}
+
+// DeltaStreamEffect 德尔塔气流特性（超级烈空坐）
+type DeltaStreamEffect struct {
+	BaseEffect
+}
+
+func (e *DeltaStreamEffect) GetAbilityID() int {
+	return 191
+}
+
+func (e *DeltaStreamEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *DeltaStreamEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	strongWinds := valueobject.WeatherStrongWinds
+	return &EntryResult{
+		Messages:   []string{"🌪️ 德尔塔气流吹起了神秘的乱流！"},
+		WeatherSet: &strongWinds,
+	}
+}
+
+// ElectricSurgeEffect 电气制造者特性
+type ElectricSurgeEffect struct {
+	BaseEffect
+}
+
+func (e *ElectricSurgeEffect) GetAbilityID() int {
+	return 226
+}
+
+func (e *ElectricSurgeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *ElectricSurgeEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	electric := valueobject.TerrainElectric
+	return &EntryResult{
+		Messages:   []string{"⚡ 电气制造者使脚下电光飞溅！"},
+		TerrainSet: &electric,
+	}
+}
+
+// PsychicSurgeEffect 精神制造者特性
+type PsychicSurgeEffect struct {
+	BaseEffect
+}
+
+func (e *PsychicSurgeEffect) GetAbilityID() int {
+	return 227
+}
+
+func (e *PsychicSurgeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *PsychicSurgeEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	psychic := valueobject.TerrainPsychic
+	return &EntryResult{
+		Messages:   []string{"🔮 精神制造者使脚下传来了奇妙的感觉！"},
+		TerrainSet: &psychic,
+	}
+}
+
+// MistySurgeEffect 薄雾制造者特性
+type MistySurgeEffect struct {
+	BaseEffect
+}
+
+func (e *MistySurgeEffect) GetAbilityID() int {
+	return 228
+}
+
+func (e *MistySurgeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *MistySurgeEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	misty := valueobject.TerrainMisty
+	return &EntryResult{
+		Messages:   []string{"🌫️ 薄雾制造者使脚下雾气缭绕！"},
+		TerrainSet: &misty,
+	}
+}
+
+// GrassySurgeEffect 青草制造者特性
+type GrassySurgeEffect struct {
+	BaseEffect
+}
+
+func (e *GrassySurgeEffect) GetAbilityID() int {
+	return 229
+}
+
+func (e *GrassySurgeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnEntry}
+}
+
+func (e *GrassySurgeEffect) OnEntry(self Battler, opponent Battler, ctx *BattleContext) *EntryResult {
+	grassy := valueobject.TerrainGrassy
+	return &EntryResult{
+		Messages:   []string{"🌿 青草制造者使脚下青草如茵！"},
+		TerrainSet: &grassy,
 	}
 }
 
@@ -1,13 +1,29 @@
 package ability
 
-import (
-	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
-)
-
 // ============================================
 // 形态变化类特性
 // ============================================
 
+// newFormChange 按目标形态的图鉴数据（属性、种族值、精灵图）创建形态变化结果
+// 图鉴中没有该形态时只改变名称，属性与能力值保持不变
+func newFormChange(formID int, formName string, messages ...string) *FormChangeResult {
+	result := &FormChangeResult{
+		Triggered:   true,
+		NewFormID:   formID,
+		NewFormName: formName,
+		Messages:    messages,
+	}
+	if form := GetRegistry().GetForm(formID); form != nil {
+		if form.Name != "" {
+			result.NewFormName = form.Name
+		}
+		result.NewTypes = form.Types
+		result.BaseStats = form.BaseStats
+		result.SpriteURL = form.SpriteURL
+	}
+	return result
+}
+
 // BattleBondEffect 羁绊变身特性 (甲贺忍蛙专属)
 // 击倒对手后变身为小智版甲贺忍蛙
 type BattleBondEffect struct {
@@ -29,24 +45,14 @@ func (e *BattleBondEffect) OnFormChange(self Battler, target Battler, ctx *Battl
 		return nil
 	}
 
-	return &FormChangeResult{
-		Triggered:   true,
-		NewFormID:   10116, // 小智版甲贺忍蛙的形态ID
-		NewFormName: "甲贺忍蛙(小智版)",
-		NewTypes:    []valueobject.PokeType{valueobject.TypeWater, valueobject.TypeDark},
-		StatBoosts: map[string]int{
-			"atk":   50,
-			"spatk": 50,
-			"speed": 10,
-		},
-		Messages: []string{
-			"🌟 甲贺忍蛙与训练师的羁绊达到了顶点！",
-			"✨ 甲贺忍蛙变身为小智版甲贺忍蛙！",
-		},
-		SpriteURL:     "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/10116.gif",
-		RevertOnExit:  true,
-		RevertOnFaint: true,
-	}
+	// 小智版甲贺忍蛙的形态ID
+	result := newFormChange(10117, "甲贺忍蛙(小智版)",
+		"🌟 甲贺忍蛙与训练师的羁绊达到了顶点！",
+		"✨ 甲贺忍蛙变身为小智版甲贺忍蛙！",
+	)
+	result.RevertOnExit = true
+	result.RevertOnFaint = true
+	return result
 }
 
 // ZenModeEffect 达摩模式特性 (达摩狒狒专属)
@@ -66,21 +72,10 @@ func (e *ZenModeEffect) GetTriggers() []TriggerType {
 func (e *ZenModeEffect) OnFormChange(self Battler, target Battler, ctx *BattleContext) *FormChangeResult {
 	// HP<=50%时变成达摩模式
 	if self.GetHPPercent() <= 50 && !self.HasVolatile("zen_mode") {
-		return &FormChangeResult{
-			Triggered:   true,
-			NewFormID:   10017, // 达摩模式
-			NewFormName: "达摩狒狒(达摩模式)",
-			NewTypes:    []valueobject.PokeType{valueobject.TypeFire, valueobject.TypePsychic},
-			StatBoosts: map[string]int{
-				"atk":   -60,
-				"spatk": 90,
-				"speed": 55,
-			},
-			Messages:      []string{"🧘 达摩狒狒进入了达摩模式！"},
-			SpriteURL:     "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/555-zen.gif",
-			RevertOnExit:  true,
-			RevertOnFaint: true,
-		}
+		result := newFormChange(10017, "达摩狒狒(达摩模式)", "🧘 达摩狒狒进入了达摩模式！") // 达摩模式
+		result.RevertOnExit = true
+		result.RevertOnFaint = true
+		return result
 	}
 	return nil
 }
@@ -102,22 +97,14 @@ func (e *PowerConstructEffect) GetTriggers() []TriggerType {
 func (e *PowerConstructEffect) OnFormChange(self Battler, target Battler, ctx *BattleContext) *FormChangeResult {
 	// HP<=50%时变成完全体形态
 	if self.GetHPPercent() <= 50 && !self.HasVolatile("power_construct_complete") {
-		return &FormChangeResult{
-			Triggered:   true,
-			NewFormID:   10118, // 完全体形态
-			NewFormName: "基格尔德(完全体)",
-			NewTypes:    []valueobject.PokeType{valueobject.TypeDragon, valueobject.TypeGround},
-			StatBoosts: map[string]int{
-				"hp": 108, // 完全体HP大幅提升
-			},
-			Messages: []string{
-				"🐉 基格尔德召集了所有细胞！",
-				"✨ 基格尔德变成了完全体形态！",
-			},
-			SpriteURL:     "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/718-complete.gif",
-			RevertOnExit:  true,
-			RevertOnFaint: false, // 完全体不会因濒死恢复
-		}
+		// 完全体形态（种族值中的HP大幅提升）
+		result := newFormChange(10120, "基格尔德(完全体)",
+			"🐉 基格尔德召集了所有细胞！",
+			"✨ 基格尔德变成了完全体形态！",
+		)
+		result.RevertOnExit = true
+		result.RevertOnFaint = false // 完全体不会因濒死恢复
+		return result
 	}
 	return nil
 }
@@ -142,40 +129,16 @@ func (e *StanceChangeEffect) OnFormChange(self Battler, target Battler, ctx *Bat
 
 // GetBladeFormChange 获取剑形态变化数据
 func (e *StanceChangeEffect) GetBladeFormChange() *FormChangeResult {
-	return &FormChangeResult{
-		Triggered:   true,
-		NewFormID:   10026, // 剑形态
-		NewFormName: "坚盾剑怪(剑形态)",
-		NewTypes:    []valueobject.PokeType{valueobject.TypeSteel, valueobject.TypeGhost},
-		StatBoosts: map[string]int{
-			"atk":   100,
-			"def":   -100,
-			"spatk": 100,
-			"spdef": -100,
-		},
-		Messages:      []string{"⚔️ 坚盾剑怪变成了剑形态！"},
-		SpriteURL:     "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/681-blade.gif",
-		RevertOnExit:  true,
-		RevertOnFaint: true,
-	}
+	result := newFormChange(10026, "坚盾剑怪(剑形态)", "⚔️ 坚盾剑怪变成了剑形态！") // 剑形态
+	result.RevertOnExit = true
+	result.RevertOnFaint = true
+	return result
 }
 
 // GetShieldFormChange 获取盾形态变化数据
 func (e *StanceChangeEffect) GetShieldFormChange() *FormChangeResult {
-	return &FormChangeResult{
-		Triggered:   true,
-		NewFormID:   681, // 盾形态（默认）
-		NewFormName: "坚盾剑怪(盾形态)",
-		NewTypes:    []valueobject.PokeType{valueobject.TypeSteel, valueobject.TypeGhost},
-		StatBoosts: map[string]int{
-			"atk":   -100,
-			"def":   100,
-			"spatk": -100,
-			"spdef": 100,
-		},
-		Messages:      []string{"🛡️ 坚盾剑怪变成了盾形态！"},
-		SpriteURL:     "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/681.gif",
-		RevertOnExit:  true,
-		RevertOnFaint: true,
-	}
+	result := newFormChange(681, "坚盾剑怪(盾形态)", "🛡️ 坚盾剑怪变成了盾形态！") // 盾形态（默认）
+	result.RevertOnExit = true
+	result.RevertOnFaint = true
+	return result
 }
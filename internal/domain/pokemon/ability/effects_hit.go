@@ -49,7 +49,8 @@ func (e *CursedBodyEffect) OnBeingHit(self Battler, attacker Battler, move Move,
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	if r.Intn(100) < 30 {
 		return &HitResult{
-			Messages: []string{"👻 诅咒之躯封印了 " + move.GetName() + "！"},
+			Messages:    []string{"👻 诅咒之躯发动了！"},
+			DisableMove: true,
 		}
 	}
 	return nil
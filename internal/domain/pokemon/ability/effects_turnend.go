@@ -46,7 +46,7 @@ func (e *SwiftSwimEffect) GetTriggers() []TriggerType {
 }
 
 func (e *SwiftSwimEffect) OnSpeedCalc(self Battler, ctx *BattleContext) *SpeedModifier {
-	if ctx != nil && ctx.Weather == valueobject.WeatherRain {
+	if ctx != nil && ctx.Weather.IsRainy() {
 		return &SpeedModifier{Multiplier: 2.0}
 	}
 	return nil
@@ -66,7 +66,7 @@ func (e *ChlorophyllEffect) GetTriggers() []TriggerType {
 }
 
 func (e *ChlorophyllEffect) OnSpeedCalc(self Battler, ctx *BattleContext) *SpeedModifier {
-	if ctx != nil && ctx.Weather == valueobject.WeatherSun {
+	if ctx != nil && ctx.Weather.IsSunny() {
 		return &SpeedModifier{Multiplier: 2.0}
 	}
 	return nil
@@ -106,7 +106,7 @@ func (e *SlushRushEffect) GetTriggers() []TriggerType {
 }
 
 func (e *SlushRushEffect) OnSpeedCalc(self Battler, ctx *BattleContext) *SpeedModifier {
-	if ctx != nil && ctx.Weather == valueobject.WeatherHail {
+	if ctx != nil && ctx.Weather.IsSnowy() {
 		return &SpeedModifier{Multiplier: 2.0}
 	}
 	return nil
@@ -200,7 +200,7 @@ func (e *RainDishEffect) GetTriggers() []TriggerType {
 }
 
 func (e *RainDishEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
-	if ctx != nil && ctx.Weather == valueobject.WeatherRain {
+	if ctx != nil && ctx.Weather.IsRainy() {
 		healAmount := self.GetMaxHP() / 16
 		if healAmount < 1 {
 			healAmount = 1
@@ -227,7 +227,7 @@ func (e *IceBodyEffect) GetTriggers() []TriggerType {
 }
 
 func (e *IceBodyEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
-	if ctx != nil && ctx.Weather == valueobject.WeatherHail {
+	if ctx != nil && ctx.Weather.IsSnowy() {
 		healAmount := self.GetMaxHP() / 16
 		if healAmount < 1 {
 			healAmount = 1
@@ -306,7 +306,7 @@ func (e *SolarPowerEffect) GetTriggers() []TriggerType {
 }
 
 func (e *SolarPowerEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
-	if ctx != nil && ctx.Weather == valueobject.WeatherSun {
+	if ctx != nil && ctx.Weather.IsSunny() {
 		damageAmount := self.GetMaxHP() / 8
 		if damageAmount < 1 {
 			damageAmount = 1
@@ -6,8 +6,9 @@ import (
 
 // Registry 特性效果注册表
 type Registry struct {
-	effects map[int]Effect
-	mu      sync.RWMutex
+	effects    map[int]Effect
+	formSource FormSource // 形态数据来源（形态变化类特性使用）
+	mu         sync.RWMutex
 }
 
 var (
@@ -60,6 +61,24 @@ func (r *Registry) GetAll() []Effect {
 	return result
 }
 
+// SetFormSource 设置形态数据来源（图鉴加载后由应用层设置）
+func (r *Registry) SetFormSource(source FormSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.formSource = source
+}
+
+// GetForm 获取形态数据（未设置来源或形态不存在时返回 nil）
+func (r *Registry) GetForm(pokemonID int) *FormData {
+	r.mu.RLock()
+	source := r.formSource
+	r.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	return source(pokemonID)
+}
+
 // registerAllEffects 注册所有特性效果
 func registerAllEffects(r *Registry) {
 	// ============================================
@@ -70,6 +89,13 @@ func registerAllEffects(r *Registry) {
 	r.Register(&DroughtEffect{})         // 70 日照
 	r.Register(&SandStreamEffect{})      // 45 扬沙
 	r.Register(&SnowWarningEffect{})     // 117 降雪
+	r.Register(&PrimordialSeaEffect{})   // 189 始源之海
+	r.Register(&DesolateLandEffect{})    // 190 终结之地
+	r.Register(&DeltaStreamEffect{})     // 191 德尔塔气流
+	r.Register(&ElectricSurgeEffect{})   // 226 电气制造者
+	r.Register(&PsychicSurgeEffect{})    // 227 精神制造者
+	r.Register(&MistySurgeEffect{})      // 228 薄雾制造者
+	r.Register(&GrassySurgeEffect{})     // 229 青草制造者
 	r.Register(&PressureEffect{})        // 46 压迫感
 	r.Register(&UnnerveEffect{})         // 127 紧张感
 	r.Register(&DownloadEffect{})        // 88 下载
@@ -151,48 +151,67 @@ func (s *Service) TriggerKO(self Battler, target Battler, ctx *BattleContext) *T
 	return effect.OnKO(self, target, ctx)
 }
 
-// CalculateDamageWithAbilities 计算包含特性效果的伤害
-// 返回：最终伤害倍率，是否免疫，消息列表
+// CalculateDamageWithAbilities 合并攻击方与防御方特性的伤害修正
+// 返回合并后的修正（各倍率相乘）与消息列表；防御方免疫时返回的修正 Immune 为 true，
+// 并携带吸收回复比例与能力变化，由调用方替代伤害结算
 func (s *Service) CalculateDamageWithAbilities(
 	attacker Battler,
 	defender Battler,
 	move Move,
 	ctx *BattleContext,
-) (powerMod, atkMod, defMod, damageMod, stabMod float64, immune bool, messages []string) {
-	powerMod = 1.0
-	atkMod = 1.0
-	defMod = 1.0
-	damageMod = 1.0
-	stabMod = 1.0
-	immune = false
+) (mod *DamageModifier, messages []string) {
+	mod = NewDamageModifier()
 	messages = make([]string, 0)
 
+	// 防御方特性（免疫/吸收优先判定）
+	defAbilityMod := s.ApplyDefenderDamageMods(defender, attacker, move, ctx)
+	if defAbilityMod != nil && defAbilityMod.Immune {
+		mod.Immune = true
+		mod.HealPercent = defAbilityMod.HealPercent
+		mod.StatBoosts = defAbilityMod.StatBoosts
+		if defender.GetAbility() != nil {
+			messages = append(messages, "🛡️ "+defender.GetAbility().Name+"使攻击无效！")
+		}
+		return
+	}
+
 	// 攻击方特性
 	atkAbilityMod := s.ApplyAttackerDamageMods(attacker, defender, move, ctx)
 	if atkAbilityMod != nil {
-		powerMod *= atkAbilityMod.PowerMod
-		atkMod *= atkAbilityMod.AttackMod
-		damageMod *= atkAbilityMod.DamageMod
-		stabMod *= atkAbilityMod.STABMod
+		mod.PowerMod *= atkAbilityMod.PowerMod
+		mod.AttackMod *= atkAbilityMod.AttackMod
+		mod.DamageMod *= atkAbilityMod.DamageMod
+		mod.STABMod *= atkAbilityMod.STABMod
+		mod.CritMod *= atkAbilityMod.CritMod
+		if atkAbilityMod.TypeOverride != nil {
+			mod.TypeOverride = atkAbilityMod.TypeOverride
+		}
 	}
 
-	// 防御方特性
-	defAbilityMod := s.ApplyDefenderDamageMods(defender, attacker, move, ctx)
 	if defAbilityMod != nil {
-		if defAbilityMod.Immune {
-			immune = true
-			if defender.GetAbility() != nil {
-				messages = append(messages, "🛡️ "+defender.GetAbility().Name+"使攻击无效！")
-			}
-			return
-		}
-		defMod *= defAbilityMod.DefenseMod
-		damageMod *= defAbilityMod.DamageMod
+		mod.DefenseMod *= defAbilityMod.DefenseMod
+		mod.DamageMod *= defAbilityMod.DamageMod
 	}
 
 	return
 }
 
+// RedirectsMove 检查特性是否会将该技能吸引到自己身上（避雷针、引水）
+func (s *Service) RedirectsMove(self Battler, move Move) bool {
+	ability := self.GetAbility()
+	if ability == nil {
+		return false
+	}
+
+	effect := s.registry.Get(ability.ID)
+	if effect == nil {
+		return false
+	}
+
+	redirector, ok := effect.(Redirector)
+	return ok && redirector.RedirectsMove(move)
+}
+
 // GetEffectiveSpeed 获取包含特性效果的有效速度
 func (s *Service) GetEffectiveSpeed(self Battler, baseSpeed int, ctx *BattleContext) int {
 	speed := baseSpeed
@@ -218,7 +237,7 @@ func (s *Service) GetEffectivePriority(self Battler, move Move, basePriority int
 }
 
 // ProcessEntryAbility 处理出场特性并返回需要应用的效果
-func (s *Service) ProcessEntryAbility(self Battler, opponent Battler, ctx *BattleContext) (messages []string, weather *valueobject.Weather, opponentStatChanges map[string]int) {
+func (s *Service) ProcessEntryAbility(self Battler, opponent Battler, ctx *BattleContext) (messages []string, weather *valueobject.Weather, terrain *valueobject.Terrain, opponentStatChanges map[string]int) {
 	messages = make([]string, 0)
 
 	result := s.TriggerEntry(self, opponent, ctx)
@@ -228,6 +247,7 @@ func (s *Service) ProcessEntryAbility(self Battler, opponent Battler, ctx *Battl
 
 	messages = append(messages, result.Messages...)
 	weather = result.WeatherSet
+	terrain = result.TerrainSet
 	opponentStatChanges = result.StatChanges
 
 	return
@@ -244,8 +264,8 @@ func (s *Service) ProcessTurnEndAbility(self Battler, ctx *BattleContext) (messa
 
 	messages = append(messages, result.Messages...)
 	statBoosts = result.StatBoosts
-	healing = result.Healing
-	damage = result.Damage
+	healing = result.Healing + result.HealAmount
+	damage = result.Damage + result.DamageAmount
 
 	return
 }
@@ -3,9 +3,12 @@ package entity
 import (
 	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/item"
 	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
 )
 
@@ -13,10 +16,12 @@ import (
 type BattleState string
 
 const (
-	BattleStateWaiting  BattleState = "waiting"  // 等待对手
-	BattleStateChoosing BattleState = "choosing" // 选择宝可梦
-	BattleStateBattling BattleState = "battling" // 对战中
-	BattleStateFinished BattleState = "finished" // 已结束
+	BattleStateWaiting   BattleState = "waiting"   // 等待对手
+	BattleStateChoosing  BattleState = "choosing"  // 选择宝可梦
+	BattleStatePreview   BattleState = "preview"   // 队伍预览（选择出战宝可梦）
+	BattleStateBattling  BattleState = "battling"  // 对战中
+	BattleStateReplacing BattleState = "replacing" // 等待替补上场（宝可梦倒下或使用U转等技能后）
+	BattleStateFinished  BattleState = "finished"  // 已结束
 )
 
 // TeamSize 队伍大小（对战模式）
@@ -25,6 +30,7 @@ type TeamSize int
 const (
 	TeamSize1v1 TeamSize = 1 // 单挑模式
 	TeamSize3v3 TeamSize = 3 // 3v3 单打
+	TeamSize4v4 TeamSize = 4 // 4v4（双打常用）
 	TeamSize6v6 TeamSize = 6 // 6v6 单打
 )
 
@@ -35,6 +41,8 @@ func (t TeamSize) GetDisplayName() string {
 		return "单挑 (1v1)"
 	case TeamSize3v3:
 		return "3v3 单打"
+	case TeamSize4v4:
+		return "4v4"
 	case TeamSize6v6:
 		return "6v6 单打"
 	default:
@@ -60,30 +68,55 @@ type Battle struct {
 	Logs           []string
 	CreatedAt      time.Time
 	TeamSize       TeamSize              // 队伍大小
+	Config         *valueobject.BattleConfig // 对战配置（模式、规则、条款）
 	IsAIBattle     bool                  // 是否为人机对战
-	Weather        valueobject.Weather   // 当前天气
-	WeatherTurns   int                   // 天气剩余回合
-	Terrain        string                // 当前场地
+	Weather        valueobject.WeatherState // 当前天气与剩余回合
+	Terrain        valueobject.Terrain   // 当前场地
 	TerrainTurns   int                   // 场地剩余回合
-	AbilityService *ability.Service      // 特性服务
+	TurnQueue      []QueuedAction        // 本回合尚未执行的技能行动（回合中途等待替补时保存）
+	MidTurn        bool                  // 是否在回合中途等待替补（替补上场后继续执行本回合）
+	AbilityService *ability.Service `json:"-"` // 特性服务（恢复对战时重新创建）
+	ItemService    *item.Service    `json:"-"` // 道具服务（恢复对战时重新创建）
 }
 
 // BattlePlayer 对战玩家
 type BattlePlayer struct {
 	ID            string
 	Username      string
-	Pokemon       *Battler   // 当前出战的宝可梦
+	Pokemon       *Battler   // 当前出战的宝可梦（双打时为 0 号位）
 	Team          []*Battler // 宝可梦队伍
 	ActiveIndex   int        // 当前出战宝可梦在队伍中的索引
 	Ready         bool
 	Action        *BattleAction
 	SelectingSlot int // 当前正在选择的队伍槽位 (0-5)
+
+	// 双打
+	Partner       *Battler      // 1 号位出战的宝可梦（单打时为 nil）
+	PartnerIndex  int           // 1 号位宝可梦在队伍中的索引
+	PartnerAction *BattleAction // 1 号位的行动
+
+	// 队伍预览
+	Roster         []*Battler // 报名的全部宝可梦（预览结束后 Team 只保留出战的宝可梦）
+	BringSelection []int      // 选择出战的宝可梦在 Roster 中的索引（第一只为首发）
+	BringConfirmed bool       // 是否已确认出战选择
+
+	// 特殊系统（每队限用一次）
+	UsedTera    bool // 是否已使用太晶化
+	UsedDynamax bool // 是否已使用极巨化
+	UsedMega    bool // 是否已使用超级进化
+	UsedZMove   bool // 是否已使用Z招式
+
+	// 场地状态（陷阱为层数，墙壁与顺风为剩余回合）
+	SideConditions map[SideCondition]int
+
+	// 等待选择替补的场上位置（按选择顺序）
+	PendingReplacements []int
 }
 
 // HasSwitchableTeamMember 检查是否有可换上场的队友
 func (p *BattlePlayer) HasSwitchableTeamMember() bool {
-	for idx, battler := range p.Team {
-		if idx != p.ActiveIndex && battler.IsAlive() {
+	for _, battler := range p.Team {
+		if !p.IsActive(battler) && battler.IsAlive() {
 			return true
 		}
 	}
@@ -111,8 +144,13 @@ func (p *BattlePlayer) HasAlivePokemon() bool {
 // BattleAction 对战行动
 type BattleAction struct {
 	Type        ActionType
-	MoveIndex   int // 技能索引 (ActionMove 时使用)
-	SwitchIndex int // 换人目标索引 (ActionSwitch 时使用)
+	MoveIndex   int  // 技能索引 (ActionMove 时使用)
+	SwitchIndex int  // 换人目标索引 (ActionSwitch 时使用)
+	Slot        int  // 行动的场上位置（双打时 0/1，单打恒为 0）
+	TargetSlot  int  // 目标位置（双打单体技能时使用，对手的 0/1 号位）
+	TargetAlly  bool // 以同伴为目标（双打时使用）
+
+	Gimmick valueobject.GimmickSystem // 本回合使用技能前发动的特殊系统（如太晶化）
 }
 
 // ActionType 行动类型
@@ -131,6 +169,16 @@ func NewBattle(id, channelID string) *Battle {
 
 // NewBattleWithTeamSize 创建指定队伍大小的对战
 func NewBattleWithTeamSize(id, channelID string, teamSize TeamSize) *Battle {
+	return NewBattleWithMode(id, channelID, teamSize, valueobject.ModeSingle)
+}
+
+// NewBattleWithMode 创建指定队伍大小与对战模式的快速对战
+func NewBattleWithMode(id, channelID string, teamSize TeamSize, mode valueobject.BattleMode) *Battle {
+	return NewBattleWithConfig(id, channelID, NewQuickConfig(teamSize, mode))
+}
+
+// NewBattleWithConfig 按对战配置创建对战（队伍大小取实际出战数量）
+func NewBattleWithConfig(id, channelID string, config *valueobject.BattleConfig) *Battle {
 	return &Battle{
 		ID:             id,
 		ChannelID:      channelID,
@@ -138,15 +186,27 @@ func NewBattleWithTeamSize(id, channelID string, teamSize TeamSize) *Battle {
 		State:          BattleStateWaiting,
 		Logs:           make([]string, 0),
 		CreatedAt:      time.Now(),
-		TeamSize:       teamSize,
+		TeamSize:       configTeamSize(config),
+		Config:         config,
 		IsAIBattle:     false,
-		Weather:        valueobject.WeatherNone,
+		Weather:        valueobject.WeatherState{Current: valueobject.WeatherNone},
 		AbilityService: ability.NewService(),
+		ItemService:    item.NewService(),
 	}
 }
 
 // NewAIBattle 创建人机对战
 func NewAIBattle(id, channelID string, teamSize TeamSize) *Battle {
+	return NewAIBattleWithMode(id, channelID, teamSize, valueobject.ModeSingle)
+}
+
+// NewAIBattleWithMode 创建指定对战模式的人机对战
+func NewAIBattleWithMode(id, channelID string, teamSize TeamSize, mode valueobject.BattleMode) *Battle {
+	return NewAIBattleWithConfig(id, channelID, NewQuickConfig(teamSize, mode))
+}
+
+// NewAIBattleWithConfig 按对战配置创建人机对战
+func NewAIBattleWithConfig(id, channelID string, config *valueobject.BattleConfig) *Battle {
 	return &Battle{
 		ID:             id,
 		ChannelID:      channelID,
@@ -154,11 +214,30 @@ func NewAIBattle(id, channelID string, teamSize TeamSize) *Battle {
 		State:          BattleStateWaiting,
 		Logs:           make([]string, 0),
 		CreatedAt:      time.Now(),
-		TeamSize:       teamSize,
+		TeamSize:       configTeamSize(config),
+		Config:         config,
 		IsAIBattle:     true,
-		Weather:        valueobject.WeatherNone,
+		Weather:        valueobject.WeatherState{Current: valueobject.WeatherNone},
 		AbilityService: ability.NewService(),
+		ItemService:    item.NewService(),
+	}
+}
+
+// NewQuickConfig 创建快速对战配置（无规则，指定队伍大小与模式）
+func NewQuickConfig(teamSize TeamSize, mode valueobject.BattleMode) *valueobject.BattleConfig {
+	config := valueobject.QuickBattleConfig()
+	config.Mode = mode
+	config.TeamSize = int(teamSize)
+	config.BringCount = int(teamSize)
+	return config
+}
+
+// configTeamSize 对战配置中每方报名的宝可梦数量（出战数量在队伍预览中选择）
+func configTeamSize(config *valueobject.BattleConfig) TeamSize {
+	if config.TeamSize > 0 {
+		return TeamSize(config.TeamSize)
 	}
+	return TeamSize(config.BringCount)
 }
 
 // IsAIPlayer 检查是否为 AI 玩家
@@ -225,8 +304,13 @@ func (b *Battle) AddPlayer(playerID, username string) error {
 	return nil
 }
 
-// SetPokemon 设置玩家的宝可梦（添加到队伍）
-func (b *Battle) SetPokemon(playerID string, pokemon *Pokemon, level int) error {
+// SetPokemon 设置玩家的宝可梦（添加到队伍，canLearn 用于检查技能是否在可学技能范围内）
+func (b *Battle) SetPokemon(playerID string, pokemon *Pokemon, level int, canLearn MoveLearnChecker) error {
+	return b.AddTeamBuilds(playerID, []*PokemonBuild{newDefaultBuild(pokemon, level)}, canLearn)
+}
+
+// AddTeamBuilds 按配置将宝可梦加入玩家队伍（整队导入时一起检查，任意一只不合规则全部不加入）
+func (b *Battle) AddTeamBuilds(playerID string, newBuilds []*PokemonBuild, canLearn MoveLearnChecker) error {
 	if b.State != BattleStateChoosing {
 		return errors.New("当前不能选择宝可梦")
 	}
@@ -240,21 +324,39 @@ func (b *Battle) SetPokemon(playerID string, pokemon *Pokemon, level int) error
 	if len(player.Team) >= int(b.TeamSize) {
 		return errors.New("队伍已满")
 	}
+	if len(player.Team)+len(newBuilds) > int(b.TeamSize) {
+		return fmt.Errorf("队伍只剩 %d 个空位", int(b.TeamSize)-len(player.Team))
+	}
 
-	// 检查是否已经选择了相同的宝可梦（种族条款）
-	for _, battler := range player.Team {
-		if battler.Pokemon.ID == pokemon.ID {
-			return errors.New("不能选择重复的宝可梦")
+	// 等级上限：平坦规则自动压制等级，其他规则直接拒绝
+	for _, build := range newBuilds {
+		if levelCap := b.Config.LevelCap; levelCap > 0 && build.Level > levelCap && b.Config.Rule == valueobject.RuleFlat {
+			build.Level = levelCap
 		}
 	}
 
-	battler := NewBattler(pokemon, level)
-	player.Team = append(player.Team, battler)
-	player.SelectingSlot++
+	// 按规则检查加入后的队伍（种族条款、道具条款、等级、一击必杀/闪避条款、可学技能范围）
+	builds := make([]*PokemonBuild, 0, len(player.Team)+len(newBuilds))
+	for _, member := range player.Team {
+		builds = append(builds, member.Build)
+	}
+	if err := ValidateTeam(b.Config, append(builds, newBuilds...), canLearn); err != nil {
+		return err
+	}
+
+	for _, build := range newBuilds {
+		battler := NewBattlerFromBuild(build)
+		player.Team = append(player.Team, battler)
+		player.Roster = append(player.Roster, battler)
+		player.SelectingSlot++
 
-	// 第一只宝可梦自动设为当前出战
-	if len(player.Team) == 1 {
-		player.Pokemon = battler
+		// 第一只宝可梦自动设为当前出战，双打时第二只进入 1 号位
+		if len(player.Team) == 1 {
+			player.Pokemon = battler
+		} else if len(player.Team) == 2 && b.IsDoubles() {
+			player.Partner = battler
+			player.PartnerIndex = 1
+		}
 	}
 
 	// 检查队伍是否已满
@@ -264,8 +366,7 @@ func (b *Battle) SetPokemon(playerID string, pokemon *Pokemon, level int) error
 
 	// 检查是否双方都准备好了
 	if b.Player1 != nil && b.Player1.Ready && b.Player2 != nil && b.Player2.Ready {
-		b.State = BattleStateBattling
-		b.Logs = append(b.Logs, "⚔️ 对战开始！")
+		b.startAfterTeamsReady()
 	}
 
 	return nil
@@ -315,7 +416,7 @@ func (p *BattlePlayer) HasAlive() bool {
 // GetNextAlive 获取下一只存活的宝可梦
 func (p *BattlePlayer) GetNextAlive() *Battler {
 	for _, battler := range p.Team {
-		if battler.IsAlive() && battler != p.Pokemon {
+		if battler.IsAlive() && !p.IsActive(battler) {
 			return battler
 		}
 	}
@@ -358,6 +459,9 @@ func (b *Battle) GetOpponent(playerID string) *BattlePlayer {
 
 // SetAction 设置行动
 func (b *Battle) SetAction(playerID string, action *BattleAction) error {
+	if b.State == BattleStateReplacing {
+		return errors.New("等待替补上场中，暂时不能选择行动")
+	}
 	if b.State != BattleStateBattling {
 		return errors.New("对战未开始")
 	}
@@ -367,27 +471,78 @@ func (b *Battle) SetAction(playerID string, action *BattleAction) error {
 		return errors.New("你不在对战中")
 	}
 
-	if action.Type == ActionMove {
-		if action.MoveIndex < 0 || action.MoveIndex >= len(player.Pokemon.Moves) {
+	if action.Slot < 0 || action.Slot >= b.ActiveSlotCount() {
+		return errors.New("无效的场上位置")
+	}
+	battler := player.GetSlot(action.Slot)
+	if action.Type != ActionForfeit && (battler == nil || !battler.IsAlive()) {
+		return errors.New("该位置没有可以行动的宝可梦")
+	}
+
+	switch action.Type {
+	case ActionMove:
+		if action.MoveIndex < 0 || action.MoveIndex >= len(battler.Moves) {
 			return errors.New("无效的技能")
 		}
-		move := player.Pokemon.Moves[action.MoveIndex]
+		move := battler.Moves[action.MoveIndex]
 		if !move.CanUse() {
 			return errors.New("PP不足")
 		}
+		if ok, reason := battler.CanSelectMove(move); !ok {
+			return errors.New(reason)
+		}
+		if ok, reason := b.IsMoveAllowed(move); !ok {
+			return errors.New(reason)
+		}
+		if ok, reason := b.ItemAllowsMove(battler, move); !ok {
+			return errors.New(reason)
+		}
+		if action.Gimmick != "" {
+			if ok, reason := b.CanUseGimmick(player, action.Slot, action.Gimmick); !ok {
+				return errors.New(reason)
+			}
+			if action.Gimmick == valueobject.GimmickZMove && !battler.CanZMove(move) {
+				return errors.New(move.Name + " 的属性与携带的Z纯晶不符")
+			}
+		}
+	case ActionSwitch:
+		// 双打时两个位置不能换上同一只宝可梦
+		if other := player.GetSlotAction(1 - action.Slot); b.IsDoubles() && other != nil &&
+			other.Type == ActionSwitch && other.SwitchIndex == action.SwitchIndex {
+			return errors.New("该宝可梦已被另一个位置选择")
+		}
 	}
 
-	player.Action = action
+	player.setSlotAction(action.Slot, action)
 	return nil
 }
 
 // BothActionsReady 双方都已选择行动
 func (b *Battle) BothActionsReady() bool {
-	return b.Player1 != nil && b.Player1.Action != nil &&
-		b.Player2 != nil && b.Player2.Action != nil
+	if b.Player1 == nil || b.Player2 == nil {
+		return false
+	}
+	if b.IsDoubles() && (hasForfeited(b.Player1) || hasForfeited(b.Player2)) {
+		return true
+	}
+	return b.playerActionsReady(b.Player1) && b.playerActionsReady(b.Player2)
+}
+
+// playerActionsReady 玩家是否已为所有在场的宝可梦选择行动
+func (b *Battle) playerActionsReady(player *BattlePlayer) bool {
+	if !b.IsDoubles() {
+		return player.Action != nil
+	}
+	return b.GetPendingSlot(player) < 0
+}
+
+// hasForfeited 玩家是否选择了认输
+func hasForfeited(player *BattlePlayer) bool {
+	return player.Action != nil && player.Action.Type == ActionForfeit
 }
 
 // ExecuteTurn 执行回合
+// 换人与特殊系统先于技能发动，技能按优先度 > 速度 > 随机的顺序加入行动队列依次执行
 func (b *Battle) ExecuteTurn() []string {
 	if !b.BothActionsReady() {
 		return nil
@@ -399,102 +554,52 @@ func (b *Battle) ExecuteTurn() []string {
 	logs = append(logs, "📍 **回合 "+itoa(b.CurrentTurn)+"**")
 
 	// 检查认输
-	if b.Player1.Action.Type == ActionForfeit {
-		b.Winner = b.Player2
-		b.State = BattleStateFinished
-		logs = append(logs, "🏳️ "+b.Player1.Username+" 认输了！")
-		logs = append(logs, "🏆 "+b.Player2.Username+" 获胜！")
-		b.Logs = append(b.Logs, logs...)
-		return logs
-	}
-	if b.Player2.Action.Type == ActionForfeit {
-		b.Winner = b.Player1
-		b.State = BattleStateFinished
-		logs = append(logs, "🏳️ "+b.Player2.Username+" 认输了！")
-		logs = append(logs, "🏆 "+b.Player1.Username+" 获胜！")
-		b.Logs = append(b.Logs, logs...)
-		return logs
-	}
-
-	// 处理换人（换人优先于攻击）
-	if b.Player1.Action.Type == ActionSwitch {
-		switchLogs := b.executeSwitch(b.Player1)
-		logs = append(logs, switchLogs...)
-	}
-	if b.Player2.Action.Type == ActionSwitch {
-		switchLogs := b.executeSwitch(b.Player2)
-		logs = append(logs, switchLogs...)
-	}
-
-	// 确定行动顺序（优先度 > 速度）
-	p1Priority := 0
-	p2Priority := 0
-	
-	// 获取技能优先度
-	if b.Player1.Action.Type == ActionMove && b.Player1.Action.MoveIndex < len(b.Player1.Pokemon.Moves) {
-		p1Priority = b.Player1.Pokemon.Moves[b.Player1.Action.MoveIndex].Priority
-	}
-	if b.Player2.Action.Type == ActionMove && b.Player2.Action.MoveIndex < len(b.Player2.Pokemon.Moves) {
-		p2Priority = b.Player2.Pokemon.Moves[b.Player2.Action.MoveIndex].Priority
-	}
-	
-	first, second := b.Player1, b.Player2
-	// 先比较优先度，再比较有效速度
-	if p2Priority > p1Priority {
-		first, second = b.Player2, b.Player1
-	} else if p2Priority == p1Priority && b.Player2.Pokemon.GetEffectiveSpeed() > b.Player1.Pokemon.GetEffectiveSpeed() {
-		first, second = b.Player2, b.Player1
-	}
-
-	// 先手行动
-	if first.Action.Type == ActionMove {
-		actionLogs := b.executeAction(first, second)
-		logs = append(logs, actionLogs...)
-	}
-
-	// 检查后手宝可梦是否倒下
-	if !second.Pokemon.IsAlive() {
-		logs = append(logs, "💀 "+second.Pokemon.Pokemon.Name+" 倒下了！")
-		// 检查是否还有存活的宝可梦
-		if !second.HasAlive() {
-			b.Winner = first
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if hasForfeited(player) {
+			winner := b.GetOpponent(player.ID)
+			b.Winner = winner
 			b.State = BattleStateFinished
-			logs = append(logs, "🏆 "+first.Username+" 获胜！")
+			logs = append(logs, "🏳️ "+player.Username+" 认输了！")
+			logs = append(logs, "🏆 "+winner.Username+" 获胜！")
 			b.Logs = append(b.Logs, logs...)
 			b.clearActions()
 			return logs
 		}
-		// 自动换上下一只宝可梦
-		nextPokemon := second.GetNextAlive()
-		if nextPokemon != nil {
-			second.Pokemon = nextPokemon
-			logs = append(logs, "🔄 "+second.Username+" 派出了 "+nextPokemon.Pokemon.Name+"！")
+	}
+
+	// 处理换人（换人优先于攻击）
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for slot := 0; slot < b.ActiveSlotCount(); slot++ {
+			if action := player.GetSlotAction(slot); action != nil && action.Type == ActionSwitch {
+				logs = append(logs, b.executeSwitch(player, slot, action)...)
+			}
 		}
 	}
 
-	// 后手行动（如果还存活）
-	if second.Pokemon.IsAlive() && second.Action.Type == ActionMove {
-		actionLogs := b.executeAction(second, first)
-		logs = append(logs, actionLogs...)
+	// 发动特殊系统（太晶化等在所有技能之前）
+	logs = append(logs, b.executeGimmicks()...)
+
+	b.TurnQueue = b.TurnQueue[:0]
+	for _, entry := range b.resolveTurnOrder() {
+		b.TurnQueue = append(b.TurnQueue, QueuedAction{
+			PlayerID:  entry.player.ID,
+			Slot:      entry.slot,
+			TeamIndex: entry.player.teamIndexOf(entry.battler),
+		})
 	}
+	return b.runTurnQueue(logs)
+}
 
-	// 检查先手宝可梦是否倒下
-	if !first.Pokemon.IsAlive() {
-		logs = append(logs, "💀 "+first.Pokemon.Pokemon.Name+" 倒下了！")
-		// 检查是否还有存活的宝可梦
-		if !first.HasAlive() {
-			b.Winner = second
-			b.State = BattleStateFinished
-			logs = append(logs, "🏆 "+second.Username+" 获胜！")
-			b.Logs = append(b.Logs, logs...)
-			b.clearActions()
-			return logs
-		}
-		// 自动换上下一只宝可梦
-		nextPokemon := first.GetNextAlive()
-		if nextPokemon != nil {
-			first.Pokemon = nextPokemon
-			logs = append(logs, "🔄 "+first.Username+" 派出了 "+nextPokemon.Pokemon.Name+"！")
+// finishTurn 回合结束阶段：清除畏缩、回合结束效果、倒下处理，倒下的宝可梦等待玩家选择替补
+func (b *Battle) finishTurn(logs []string) []string {
+	b.MidTurn = false
+	b.TurnQueue = nil
+
+	// 畏缩与守住只持续一回合
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for _, battler := range player.ActiveBattlers() {
+			battler.Flinched = false
+			battler.Protected = false
 		}
 	}
 
@@ -502,132 +607,456 @@ func (b *Battle) ExecuteTurn() []string {
 	turnEndLogs := b.TriggerTurnEndAbilities()
 	logs = append(logs, turnEndLogs...)
 
+	// 极巨化回合数
+	logs = append(logs, b.tickDynamax()...)
+
+	// 回合结束阶段倒下的宝可梦（异常状态、天气伤害等）
+	logs = append(logs, b.announceFaints()...)
+	if endLogs, finished := b.checkBattleEnd(); finished {
+		logs = append(logs, endLogs...)
+		b.Logs = append(b.Logs, logs...)
+		b.clearActions()
+		return logs
+	}
+
 	b.CurrentTurn++
-	b.Logs = append(b.Logs, logs...)
 	b.clearActions()
+	logs = append(logs, b.requestReplacements()...)
+	b.Logs = append(b.Logs, logs...)
 	return logs
 }
 
-// executeSwitch 执行换人
-func (b *Battle) executeSwitch(player *BattlePlayer) []string {
-	logs := make([]string, 0)
-	if player.Action.SwitchIndex < 0 || player.Action.SwitchIndex >= len(player.Team) {
-		return logs
+// getOpponentPokemon 获取对手当前出战的宝可梦（双打时为第一只存活的）
+func (b *Battle) getOpponentPokemon(player *BattlePlayer) *Battler {
+	var opponent *BattlePlayer
+	if player == b.Player1 {
+		opponent = b.Player2
+	} else if player == b.Player2 {
+		opponent = b.Player1
 	}
-	newPokemon := player.Team[player.Action.SwitchIndex]
-	if !newPokemon.IsAlive() || newPokemon == player.Pokemon {
-		return logs
+	if opponent == nil {
+		return nil
+	}
+	for _, battler := range opponent.ActiveBattlers() {
+		if battler.IsAlive() {
+			return battler
+		}
 	}
-	oldName := player.Pokemon.Pokemon.Name
-	player.Pokemon = newPokemon
-	player.ActiveIndex = player.Action.SwitchIndex
-	logs = append(logs, "🔄 "+player.Username+" 收回了 "+oldName+"，派出了 "+newPokemon.Pokemon.Name+"！")
+	return opponent.Pokemon
+}
 
-	// 触发出场特性
-	var opponent *Battler
-	if player == b.Player1 && b.Player2 != nil {
-		opponent = b.Player2.Pokemon
-	} else if player == b.Player2 && b.Player1 != nil {
-		opponent = b.Player1.Pokemon
+// checkBattleEnd 一方没有存活的宝可梦时结束对战，返回对战是否结束
+func (b *Battle) checkBattleEnd() ([]string, bool) {
+	p1Out := !b.Player1.HasAlive()
+	p2Out := !b.Player2.HasAlive()
+	if !p1Out && !p2Out {
+		return nil, false
 	}
-	if opponent != nil {
-		entryLogs := b.TriggerEntryAbility(newPokemon, opponent)
-		logs = append(logs, entryLogs...)
+
+	b.State = BattleStateFinished
+	b.clearPendingReplacements()
+	switch {
+	case p1Out && p2Out:
+		return []string{"🤝 双方宝可梦同时倒下，平局！"}, true
+	case p1Out:
+		b.Winner = b.Player2
+		return []string{"🏆 " + b.Player2.Username + " 获胜！"}, true
+	default:
+		b.Winner = b.Player1
+		return []string{"🏆 " + b.Player1.Username + " 获胜！"}, true
 	}
+}
 
-	return logs
+// ============================================
+// 行动顺序
+// ============================================
+
+// GetEffectiveSpeed 获取宝可梦在当前场上的实际速度
+// 包含能力等级、麻痹、道具（讲究围巾、黑铁球）、特性（悠游自如、叶绿素等）以及顺风的修正
+func (b *Battle) GetEffectiveSpeed(battler *Battler) int {
+	if battler == nil {
+		return 0
+	}
+	speed := battler.GetEffectiveSpeed()
+	if b.AbilityService != nil {
+		speed = b.AbilityService.GetEffectiveSpeed(battler, speed, b.GetBattleContext())
+	}
+	if b.ItemService != nil {
+		speed = b.ItemService.GetEffectiveSpeed(battler, speed, b.GetBattleContext())
+	}
+	if owner := b.GetOwner(battler); owner != nil && owner.HasSideCondition(SideTailwind) {
+		speed *= 2
+	}
+	return speed
+}
+
+// GetActionPriority 获取玩家本回合行动的优先度（含特性修正，如恶作剧之心、疾风之翼）
+func (b *Battle) GetActionPriority(player *BattlePlayer) int {
+	if player == nil {
+		return 0
+	}
+	return b.getMovePriority(player.Pokemon, player.Action)
+}
+
+// getMovePriority 获取宝可梦本回合行动的优先度
+func (b *Battle) getMovePriority(battler *Battler, action *BattleAction) int {
+	if battler == nil || action == nil || action.Type != ActionMove {
+		return 0
+	}
+	if action.MoveIndex < 0 || action.MoveIndex >= len(battler.Moves) {
+		return 0
+	}
+	move := battler.ResolveMove(battler.Moves[action.MoveIndex])
+	if action.Gimmick == valueobject.GimmickZMove && battler.CanZMove(move) {
+		move = ZMoveFor(move)
+	}
+	return b.effectivePriority(battler, move)
+}
+
+// effectivePriority 获取技能的实际优先度（含特性修正）
+func (b *Battle) effectivePriority(battler *Battler, move *Move) int {
+	priority := move.Priority
+	if b.AbilityService != nil {
+		priority = b.AbilityService.GetEffectivePriority(battler, NewMoveAdapter(move), priority, b.GetBattleContext())
+	}
+	return priority
+}
+
+// turnOrderEntry 行动顺序条目
+type turnOrderEntry struct {
+	player   *BattlePlayer
+	slot     int
+	battler  *Battler
+	priority int
+	speed    int
+	tiebreak int
+}
+
+// resolveTurnOrder 决定本回合所有技能行动的顺序
+// 先比较优先度，再比较实际速度，同速时随机决定
+func (b *Battle) resolveTurnOrder() []turnOrderEntry {
+	entries := make([]turnOrderEntry, 0, 4)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for slot := 0; slot < b.ActiveSlotCount(); slot++ {
+			battler := player.GetSlot(slot)
+			action := player.GetSlotAction(slot)
+			if battler == nil || action == nil || action.Type != ActionMove {
+				continue
+			}
+			entries = append(entries, turnOrderEntry{
+				player:   player,
+				slot:     slot,
+				battler:  battler,
+				priority: b.getMovePriority(battler, action),
+				speed:    b.GetEffectiveSpeed(battler),
+				tiebreak: randInt(1 << 16),
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].priority != entries[j].priority {
+			return entries[i].priority > entries[j].priority
+		}
+		if entries[i].speed != entries[j].speed {
+			return entries[i].speed > entries[j].speed
+		}
+		return entries[i].tiebreak < entries[j].tiebreak
+	})
+	return entries
+}
+
+// executeSwitch 执行换人（slot 为换下的场上位置）
+func (b *Battle) executeSwitch(player *BattlePlayer, slot int, action *BattleAction) []string {
+	if action.SwitchIndex < 0 || action.SwitchIndex >= len(player.Team) {
+		return nil
+	}
+	oldPokemon := player.GetSlot(slot)
+	newPokemon := player.Team[action.SwitchIndex]
+	if oldPokemon == nil || !newPokemon.IsAlive() || player.IsActive(newPokemon) {
+		return nil
+	}
+	return b.sendOut(player, slot, newPokemon)
 }
 
-// executeAction 执行单个行动
-func (b *Battle) executeAction(attacker, defender *BattlePlayer) []string {
+// executeMoveAction 执行宝可梦的技能行动
+// player 为行动方，user 为使用技能的宝可梦，opponent 为对手
+func (b *Battle) executeMoveAction(player *BattlePlayer, user *Battler, action *BattleAction, opponent *BattlePlayer) []string {
 	logs := make([]string, 0)
 
 	// 检查是否需要充能（如破坏光线后的回合）
-	if attacker.Pokemon.MustRecharge {
-		logs = append(logs, "⏳ "+attacker.Pokemon.Pokemon.Name+" 正在充能，无法行动！")
-		attacker.Pokemon.MustRecharge = false
+	if user.MustRecharge {
+		logs = append(logs, "⏳ "+user.Pokemon.Name+" 正在充能，无法行动！")
+		user.MustRecharge = false
+		return logs
+	}
+
+	if action.Type != ActionMove {
+		return logs
+	}
+
+	// 异常状态判定（睡眠、冰冻、麻痹）
+	statusLogs, canMove := b.checkStatusBeforeMove(user)
+	logs = append(logs, statusLogs...)
+	if !canMove {
+		return logs
+	}
+
+	// 畏缩
+	if user.Flinched {
+		logs = append(logs, "😣 "+user.Pokemon.Name+" 畏缩了，无法行动！")
+		return logs
+	}
+
+	move := user.Moves[action.MoveIndex]
+
+	// 临时状态判定（再来一次、挑衅、定身法、无理取闹、混乱、着迷）
+	volatileLogs, move, canMove := b.checkVolatileBeforeMove(user, opponent.ActiveBattlers(), move)
+	logs = append(logs, volatileLogs...)
+	if !canMove {
 		return logs
 	}
 
-	if attacker.Action.Type != ActionMove {
+	// 对战条款（一击必杀条款、闪避条款）
+	if ok, reason := b.IsMoveAllowed(move); !ok {
+		logs = append(logs, "🚫 "+user.Pokemon.Name+" 无法使用 "+move.Name+"！（"+reason+"）")
 		return logs
 	}
 
-	move := attacker.Pokemon.Moves[attacker.Action.MoveIndex]
 	move.Use()
+	b.recordMoveUse(user, move)
+
+	// 极巨化时使用对应的极巨招式
+	move = user.ResolveMove(move)
+
+	// Z招式：借助Z纯晶将技能转换为Z招式（每支队伍一次）
+	if action.Gimmick == valueobject.GimmickZMove && !player.UsedZMove && user.CanZMove(move) {
+		player.UsedZMove = true
+		user.UsedZMove = true
+		move = ZMoveFor(move)
+		logs = append(logs, "⚡ "+user.Pokemon.Name+" 发动了Z力量！")
+		if move.Category == CategoryStatus {
+			logs = append(logs, b.applyZStatusEffect(user, move)...)
+		}
+	}
 
-	logs = append(logs, "▶️ "+attacker.Pokemon.Pokemon.Name+" 使用了 **"+move.Name+"**！")
+	logs = append(logs, "▶️ "+user.Pokemon.Name+" 使用了 **"+move.Name+"**！")
 
-	result := attacker.Pokemon.CalculateDamage(move, defender.Pokemon)
+	// 守住类技能（含极巨防壁）：本回合守住对手的攻击，使用其他技能时连续计数归零
+	if move.IsMaxGuard() || protectMoves[move.ID] {
+		logs = append(logs, b.executeProtect(user)...)
+		return logs
+	}
+	user.ProtectCount = 0
 
-	if !result.Hit {
-		logs = append(logs, "❌ 但是没有命中！")
+	// 原始天气中火/水属性的攻击招式失效
+	if message, nullified := b.weatherNullifiesMove(move); nullified {
+		logs = append(logs, message)
 		return logs
 	}
 
-	if move.Category == CategoryStatus {
-		logs = append(logs, "✨ 效果发动了！")
+	targets, redirectLogs := b.resolveMoveTargets(player, user, action, opponent, move)
+	logs = append(logs, redirectLogs...)
+
+	// 目标已倒下
+	if len(targets) == 0 {
+		logs = append(logs, "❌ 但是没有目标...")
 		return logs
 	}
 
-	// 应用特性伤害修正
-	if b.AbilityService != nil {
+	// 以自身为目标的变化技能不进行命中判定，附加状态也作用于自身（如睡觉）
+	if move.Category == CategoryStatus && move.Target.IsSelfTarget() {
+		logs = append(logs, b.executeStatusMove(user, user, move)...)
+		return logs
+	}
+
+	// 范围技能同时命中多个目标时威力降低
+	spread := len(targets) > 1 && move.Category != CategoryStatus
+	totalDamage := 0
+	for _, target := range targets {
+		if !user.IsAlive() {
+			break
+		}
+		if spread {
+			logs = append(logs, "🎯 对 "+target.Pokemon.Name+"：")
+		}
+		targetLogs, dealt := b.executeMoveOnTarget(user, target, move, spread)
+		logs = append(logs, targetLogs...)
+		totalDamage += dealt
+	}
+
+	// 攻击后道具（如生命宝珠）
+	if move.Category != CategoryStatus {
+		logs = append(logs, b.triggerAfterAttackItem(user, move, totalDamage)...)
+	}
+
+	// 极巨招式追加效果（对目标无效时不发动）
+	if move.IsMax && user.IsAlive() && valueobject.GetEffectiveness(move.Type, targets[0].Types) > 0 && !targets[0].Protected {
+		logs = append(logs, b.applyMaxMoveEffect(player, user, targets[0], move)...)
+	}
+
+	// 检查技能是否需要充能（如破坏光线）
+	if move.RechargeRequired {
+		user.MustRecharge = true
+	}
+
+	// U转、伏特替换等命中后回到队伍，由玩家选择替补（转换为极巨招式或Z招式时不换人）
+	if pivotMoves[move.ID] && !move.IsMax && !move.IsZ && totalDamage > 0 {
+		b.requestPivot(player, user)
+	}
+
+	return logs
+}
+
+// executeMoveOnTarget 对单个目标结算技能（命中、伤害、追加效果、受击特性与道具）
+// spread 为 true 时伤害乘以范围技能修正；返回日志与造成的伤害
+func (b *Battle) executeMoveOnTarget(attacker, defender *Battler, move *Move, spread bool) ([]string, int) {
+	logs := make([]string, 0)
+
+	// 守住（极巨防壁），作用于场地的技能不受影响
+	if defender.Protected && defender != attacker && move.Target != TargetOpponentsField && move.Target != TargetEntireField {
+		logs = append(logs, "🛡️ "+defender.Pokemon.Name+" 守住了攻击！")
+		return logs, 0
+	}
+
+	// 精神场地保护着地的宝可梦不受先制技能影响
+	if b.terrainBlocksPriority(attacker, defender, move) {
+		logs = append(logs, "🔮 "+defender.Pokemon.Name+" 受到了精神场地的保护！")
+		return logs, 0
+	}
+
+	// 根据体重计算威力的技能对极巨化的宝可梦无效
+	if defender.IsDynamaxed && move.IsWeightBased() {
+		logs = append(logs, "❌ 但是失败了！")
+		return logs, 0
+	}
+
+	// 特性伤害修正（免疫/吸收类特性在命中判定前生效）
+	var damageMod *ability.DamageModifier
+	if b.AbilityService != nil && move.Category != CategoryStatus {
 		ctx := b.GetBattleContext()
 		moveAdapter := NewMoveAdapter(move)
-		_, _, _, _, _, immune, abilityMsgs := b.AbilityService.CalculateDamageWithAbilities(
-			attacker.Pokemon, defender.Pokemon, moveAdapter, ctx)
+		mod, abilityMsgs := b.AbilityService.CalculateDamageWithAbilities(
+			attacker, defender, moveAdapter, ctx)
 		logs = append(logs, abilityMsgs...)
-		if immune {
-			return logs
+		if mod.Immune {
+			logs = append(logs, b.applyAbsorb(defender, mod)...)
+			return logs, 0
 		}
+		damageMod = mod
+	}
+
+	// 道具伤害修正（如讲究头带、突击背心；气球使地面招式无效）
+	damageMod, itemMsgs, itemImmune := b.mergeItemDamageMods(attacker, defender, move, damageMod)
+	logs = append(logs, itemMsgs...)
+	if itemImmune {
+		return logs, 0
+	}
+	damageMod = b.applyTerrainPower(attacker, defender, move, damageMod)
+	damageMod = b.applyWeatherModifiers(defender, move, damageMod)
+	if spread {
+		if damageMod == nil {
+			damageMod = ability.NewDamageModifier()
+		}
+		damageMod.DamageMod *= SpreadDamageModifier
+	}
+
+	result := b.applyScreens(attacker, defender, move, b.applyStrongWinds(defender, move, attacker.CalculateDamage(move, defender, damageMod)))
+
+	if !result.Hit {
+		logs = append(logs, "❌ 但是没有命中！")
+		return logs, 0
 	}
 
-	if result.Critical {
-		logs = append(logs, "💥 会心一击！")
+	if move.Category == CategoryStatus {
+		logs = append(logs, b.executeStatusMove(attacker, defender, move)...)
+		return logs, 0
+	}
+
+	if result.Effectiveness == 0 {
+		logs = append(logs, "⚫ 没有效果...")
+		return logs, 0
+	}
+	if b.strongWindsWeaken(defender, move) {
+		logs = append(logs, "🌪️ 神秘的乱流减弱了攻击！")
 	}
 
-	defender.Pokemon.TakeDamageWithItem(result.Damage)
+	// 连续攻击（替身存在时由替身承受伤害）
+	hits := move.GetHitCount()
+	totalDamage := 0
+	hitSubstitute := false
+	for hit := 1; hit <= hits; hit++ {
+		if hit > 1 {
+			if !defender.IsAlive() {
+				hits = hit - 1
+				break
+			}
+			result = b.applyScreens(attacker, defender, move, b.applyStrongWinds(defender, move, attacker.CalculateHitDamage(move, defender, damageMod)))
+		}
+		if result.Critical {
+			logs = append(logs, "💥 会心一击！")
+		}
+		if defender.HasVolatileStatus(VolatileSubstitute) {
+			dealt, subLogs := b.damageSubstitute(defender, result.Damage)
+			totalDamage += dealt
+			hitSubstitute = true
+			logs = append(logs, subLogs...)
+			continue
+		}
+		dealt, itemLogs := b.takeDamageWithItem(defender, result.Damage)
+		totalDamage += dealt
+		logs = append(logs, itemLogs...)
+	}
+	result.Damage = totalDamage
 
 	// 属性克制提示
 	if result.Effectiveness > 1 {
 		logs = append(logs, "💥 效果拔群！")
-	} else if result.Effectiveness < 1 && result.Effectiveness > 0 {
+	} else if result.Effectiveness < 1 {
 		logs = append(logs, "🛡️ 效果不佳...")
-	} else if result.Effectiveness == 0 {
-		logs = append(logs, "⚫ 没有效果...")
-		return logs
 	}
 
-	logs = append(logs, "💔 造成了 **"+itoa(result.Damage)+"** 点伤害！")
-	logs = append(logs, "❤️ "+defender.Pokemon.Pokemon.Name+" HP: "+itoa(defender.Pokemon.CurrentHP)+"/"+itoa(defender.Pokemon.MaxHP))
+	if hits > 1 {
+		logs = append(logs, "🔁 命中了 "+itoa(hits)+" 次！")
+	}
+	if !hitSubstitute {
+		logs = append(logs, "💔 造成了 **"+itoa(result.Damage)+"** 点伤害！")
+		logs = append(logs, "❤️ "+defender.Pokemon.Name+" HP: "+itoa(defender.CurrentHP)+"/"+itoa(defender.MaxHP))
+	}
 
-	// 触发受击特���（如静电、粗糙皮肤等）
-	if b.AbilityService != nil && defender.Pokemon.IsAlive() {
+	// 技能追加效果（吸取、反作用、能力变化、异常状态、畏缩）
+	logs = append(logs, b.applyMoveSecondaryEffects(attacker, defender, move, result.Damage, hitSubstitute)...)
+	logs = append(logs, b.applyHazardRemoval(attacker, move)...)
+
+	// 触发受击特性（如静电、粗糙皮肤等）
+	if b.AbilityService != nil && defender.IsAlive() && !hitSubstitute {
 		ctx := b.GetBattleContext()
 		moveAdapter := NewMoveAdapter(move)
-		hitResult := b.AbilityService.TriggerBeingHit(defender.Pokemon, attacker.Pokemon, moveAdapter, result.Damage, ctx)
+		hitResult := b.AbilityService.TriggerBeingHit(defender, attacker, moveAdapter, result.Damage, ctx)
 		if hitResult != nil {
 			logs = append(logs, hitResult.Messages...)
 			// 处理接触效果（如麻痹、中毒）
 			if hitResult.ContactEffect != "" && hitResult.ContactChance > 0 {
 				if randInt(100) < hitResult.ContactChance {
-					if attacker.Pokemon.GetStatus() == "" {
-						attacker.Pokemon.SetStatus(hitResult.ContactEffect)
-						logs = append(logs, "⚡ "+attacker.Pokemon.Pokemon.Name+" 陷入了"+hitResult.ContactEffect+"状态！")
-					}
+					ailmentLogs, _ := b.inflictAilment(attacker, defender, hitResult.ContactEffect, false)
+					logs = append(logs, ailmentLogs...)
 				}
 			}
+			// 处理技能封印（如诅咒之躯）
+			if hitResult.DisableMove && attacker.IsAlive() {
+				disableLogs, _ := b.ApplyVolatile(attacker, defender, VolatileDisable, false)
+				logs = append(logs, disableLogs...)
+			}
 			// 处理反伤（如粗糙皮肤、铁刺）
 			if hitResult.RecoilDamage > 0 {
-				attacker.Pokemon.TakeDamage(hitResult.RecoilDamage)
-				logs = append(logs, "💥 "+attacker.Pokemon.Pokemon.Name+" 受到了反伤！")
+				attacker.TakeDamage(hitResult.RecoilDamage)
+				logs = append(logs, "💥 "+attacker.Pokemon.Name+" 受到了反伤！")
 			}
 			// 处理能力变化（如黏滑降速）
 			if hitResult.StatChanges != nil {
 				for stat, stages := range hitResult.StatChanges {
-					if newStage, changed := attacker.Pokemon.ModifyStat(stat, stages); changed {
+					if newStage, changed := attacker.ModifyStat(stat, stages); changed {
 						if stages < 0 {
-							logs = append(logs, "📉 "+attacker.Pokemon.Pokemon.Name+" 的"+getStatName(stat)+"下降了！(现在: "+itoa(newStage)+"级)")
+							logs = append(logs, "📉 "+attacker.Pokemon.Name+" 的"+getStatName(stat)+"下降了！(现在: "+itoa(newStage)+"级)")
 						}
 					}
 				}
@@ -635,44 +1064,65 @@ func (b *Battle) executeAction(attacker, defender *BattlePlayer) []string {
 		}
 	}
 
+	// 触发受击道具（如凸凸头盔、气球），随后检查双方的HP阈值道具
+	if !hitSubstitute && result.Damage > 0 {
+		logs = append(logs, b.triggerBeingHitItem(defender, attacker, move, result.Damage)...)
+	}
+	logs = append(logs, b.checkHPThresholdItem(defender)...)
+	logs = append(logs, b.checkHPThresholdItem(attacker)...)
+
 	// 检查击倒触发特性（如自信过剩、异兽提升）
-	if b.AbilityService != nil && !defender.Pokemon.IsAlive() {
+	if b.AbilityService != nil && !defender.IsAlive() {
 		ctx := b.GetBattleContext()
-		koResult := b.AbilityService.TriggerKO(attacker.Pokemon, defender.Pokemon, ctx)
+		koResult := b.AbilityService.TriggerKO(attacker, defender, ctx)
 		if koResult != nil {
 			logs = append(logs, koResult.Messages...)
 			if koResult.StatBoosts != nil {
 				for stat, stages := range koResult.StatBoosts {
-					if newStage, changed := attacker.Pokemon.ModifyStat(stat, stages); changed {
-						logs = append(logs, "📈 "+attacker.Pokemon.Pokemon.Name+" 的"+getStatName(stat)+"提升了！(现在: "+itoa(newStage)+"级)")
+					if newStage, changed := attacker.ModifyStat(stat, stages); changed {
+						logs = append(logs, "📈 "+attacker.Pokemon.Name+" 的"+getStatName(stat)+"提升了！(现在: "+itoa(newStage)+"级)")
 					}
 				}
 			}
 		}
 	}
 
-	// 检查技能是否需要充能（如破坏光线）
-	if move.RechargeRequired {
-		attacker.Pokemon.MustRecharge = true
-	}
+	return logs, result.Damage
+}
 
+// applyAbsorb 处理吸收类特性（蓄电、储水、食草、电气引擎等）：回复HP或提升能力
+func (b *Battle) applyAbsorb(holder *Battler, mod *ability.DamageModifier) []string {
+	logs := make([]string, 0)
+	if mod.HealPercent > 0 && holder.CurrentHP < holder.MaxHP {
+		amount := int(float64(holder.MaxHP) * mod.HealPercent / 100)
+		if amount < 1 {
+			amount = 1
+		}
+		healed := holder.Heal(amount)
+		logs = append(logs, "💚 "+holder.Pokemon.Name+" 回复了 "+itoa(healed)+" HP！")
+	}
+	for stat, stages := range mod.StatBoosts {
+		if _, changed := holder.ModifyStat(stat, stages); changed {
+			logs = append(logs, "📈 "+holder.Pokemon.Name+" 的"+getStatName(stat)+"提升了！")
+		}
+	}
 	return logs
 }
 
 // clearActions 清除行动
 func (b *Battle) clearActions() {
-	if b.Player1 != nil {
-		b.Player1.Action = nil
-	}
-	if b.Player2 != nil {
-		b.Player2.Action = nil
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player != nil {
+			player.Action = nil
+			player.PartnerAction = nil
+		}
 	}
 }
 
 // IsPlayerTurn 检查是否轮到该玩家
 func (b *Battle) IsPlayerTurn(playerID string) bool {
 	player := b.GetPlayer(playerID)
-	return player != nil && player.Action == nil
+	return b.State == BattleStateBattling && player != nil && b.GetPendingSlot(player) >= 0
 }
 
 // GetBattleStatus 获取对战状态描述
@@ -698,6 +1148,20 @@ func (b *Battle) GetBattleStatus() string {
 		}
 		return status
 	}
+	if b.State == BattleStatePreview {
+		status := "队伍预览阶段：请从 " + itoa(int(b.TeamSize)) + " 只中选择 " + itoa(b.BringCount()) + " 只出战\n"
+		for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+			if player.BringConfirmed {
+				status += "✅ " + player.Username + " 已确认\n"
+			} else {
+				status += "⏳ " + player.Username + " 选择中...\n"
+			}
+		}
+		return status
+	}
+	if b.State == BattleStateReplacing {
+		return joinStrings(b.replacementPrompts(), "\n")
+	}
 	if b.State == BattleStateFinished {
 		return "对战已结束"
 	}
@@ -711,29 +1175,34 @@ func (b *Battle) GetBattleStatus() string {
 // GetBattleContext 获取战斗上下文（用于特性系统）
 func (b *Battle) GetBattleContext() *ability.BattleContext {
 	return &ability.BattleContext{
-		Weather:   b.Weather,
+		Weather:   b.Weather.Current,
 		Terrain:   b.Terrain,
 		Turn:      b.CurrentTurn,
-		IsDoubles: false,
+		IsDoubles: b.IsDoubles(),
 	}
 }
 
 // TriggerEntryAbility 触发出场特性
 func (b *Battle) TriggerEntryAbility(self *Battler, opponent *Battler) []string {
-	logs := make([]string, 0)
+	// 携带宝珠的固拉多/盖欧卡先原始回归，再发动原始形态的特性
+	logs := b.tryPrimalReversion(self)
 	if b.AbilityService == nil || self.Ability == nil {
 		return logs
 	}
 
 	ctx := b.GetBattleContext()
-	messages, weather, statChanges := b.AbilityService.ProcessEntryAbility(self, opponent, ctx)
+	messages, weather, terrain, statChanges := b.AbilityService.ProcessEntryAbility(self, opponent, ctx)
 
 	logs = append(logs, messages...)
 
-	// 设置天气
-	if weather != nil {
-		b.Weather = *weather
-		b.WeatherTurns = 5
+	// 设置天气（原始天气无法被普通天气覆盖）
+	if weather != nil && !b.SetWeather(*weather, self) && b.Weather.Current.IsPrimal() && !weather.IsPrimal() {
+		logs = append(logs, b.Weather.Current.Icon()+" 但是"+string(b.Weather.Current)+"没有受到影响！")
+	}
+
+	// 展开场地（如电气制造者）
+	if terrain != nil {
+		b.SetTerrain(*terrain)
 	}
 
 	// 应用对手能力变化
@@ -752,126 +1221,85 @@ func (b *Battle) TriggerEntryAbility(self *Battler, opponent *Battler) []string
 	return logs
 }
 
-// TriggerTurnEndAbilities 触发回合结束特性
+// TriggerTurnEndAbilities 回合结束阶段：天气 → 青草场地回复 → 特性 → 道具 → 异常状态伤害 → 场地与场地状态回合数
 func (b *Battle) TriggerTurnEndAbilities() []string {
 	logs := make([]string, 0)
-	if b.AbilityService == nil {
-		return logs
-	}
-
-	ctx := b.GetBattleContext()
 
-	// 处理天气伤害/回复
-	if b.Weather != valueobject.WeatherNone {
+	// 处理天气伤害与天气回合数
+	if b.Weather.IsActive() {
 		logs = append(logs, b.processWeatherEffects()...)
-		b.WeatherTurns--
-		if b.WeatherTurns <= 0 {
-			logs = append(logs, "☀️ 天气恢复正常了。")
-			b.Weather = valueobject.WeatherNone
-		}
+		logs = append(logs, b.tickWeather()...)
 	}
 
-	// 玩家1的回合结束特性
-	if b.Player1 != nil && b.Player1.Pokemon != nil && b.Player1.Pokemon.IsAlive() {
-		messages, statBoosts, healing, damage := b.AbilityService.ProcessTurnEndAbility(b.Player1.Pokemon, ctx)
-		logs = append(logs, messages...)
-		if healing > 0 {
-			b.Player1.Pokemon.Heal(healing)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
 		}
-		if damage > 0 {
-			b.Player1.Pokemon.TakeDamage(damage)
-		}
-		if statBoosts != nil {
-			for stat, stages := range statBoosts {
-				b.Player1.Pokemon.ModifyStat(stat, stages)
+		for _, battler := range player.ActiveBattlers() {
+			if !battler.IsAlive() {
+				continue
 			}
+			logs = append(logs, b.processTerrainHealing(battler)...)
+			abilityLogs, negatePoison := b.processTurnEndAbility(battler)
+			logs = append(logs, abilityLogs...)
+			logs = append(logs, b.processTurnEndItem(battler)...)
+			logs = append(logs, b.processStatusResidual(battler, negatePoison)...)
+			logs = append(logs, b.processVolatileTurnEnd(battler)...)
 		}
 	}
 
-	// 玩家2的回合结束特性
-	if b.Player2 != nil && b.Player2.Pokemon != nil && b.Player2.Pokemon.IsAlive() {
-		messages, statBoosts, healing, damage := b.AbilityService.ProcessTurnEndAbility(b.Player2.Pokemon, ctx)
-		logs = append(logs, messages...)
-		if healing > 0 {
-			b.Player2.Pokemon.Heal(healing)
-		}
-		if damage > 0 {
-			b.Player2.Pokemon.TakeDamage(damage)
-		}
-		if statBoosts != nil {
-			for stat, stages := range statBoosts {
-				b.Player2.Pokemon.ModifyStat(stat, stages)
-			}
-		}
-	}
+	// 场地、墙壁与顺风回合数
+	logs = append(logs, b.tickTerrain()...)
+	logs = append(logs, b.tickSideConditions()...)
 
 	return logs
 }
 
-// processWeatherEffects 处理天气效果
-func (b *Battle) processWeatherEffects() []string {
+// processTurnEndAbility 处理单只宝可梦的回合结束特性
+// 返回日志以及是否免除中毒伤害（如毒疗）
+func (b *Battle) processTurnEndAbility(battler *Battler) ([]string, bool) {
 	logs := make([]string, 0)
+	if b.AbilityService == nil || !battler.IsAlive() {
+		return logs, false
+	}
 
-	processPokemon := func(pokemon *Battler) {
-		if pokemon == nil || !pokemon.IsAlive() {
-			return
-		}
-
-		// 检查是否免疫天气伤害
-		immune := false
-		for _, t := range pokemon.Types {
-			switch b.Weather {
-			case valueobject.WeatherSand:
-				if t == valueobject.TypeRock || t == valueobject.TypeGround || t == valueobject.TypeSteel {
-					immune = true
-				}
-			case valueobject.WeatherHail:
-				if t == valueobject.TypeIce {
-					immune = true
-				}
-			}
-		}
+	result := b.AbilityService.TriggerTurnEnd(battler, b.GetBattleContext())
+	if result == nil {
+		return logs, false
+	}
 
-		if !immune {
-			switch b.Weather {
-			case valueobject.WeatherSand:
-				damage := pokemon.MaxHP / 16
-				if damage < 1 {
-					damage = 1
-				}
-				pokemon.TakeDamage(damage)
-				logs = append(logs, "🏜️ "+pokemon.Pokemon.Name+" 受到了沙暴伤害！")
-			case valueobject.WeatherHail:
-				damage := pokemon.MaxHP / 16
-				if damage < 1 {
-					damage = 1
-				}
-				pokemon.TakeDamage(damage)
-				logs = append(logs, "🌨️ "+pokemon.Pokemon.Name+" 受到了冰雹伤害！")
-			}
+	// 治愈类特性（如蜕皮）按几率触发，未触发时不输出消息
+	if result.CureStatus {
+		if battler.Status == StatusNone || (result.CureChance > 0 && randInt(100) >= result.CureChance) {
+			return logs, result.NegatePoison
 		}
+		b.CureStatus(battler)
 	}
 
-	if b.Player1 != nil {
-		processPokemon(b.Player1.Pokemon)
+	logs = append(logs, result.Messages...)
+	if healing := result.Healing + result.HealAmount; healing > 0 {
+		battler.Heal(healing)
+	}
+	if damage := result.Damage + result.DamageAmount; damage > 0 {
+		battler.TakeDamage(damage)
 	}
-	if b.Player2 != nil {
-		processPokemon(b.Player2.Pokemon)
+	for stat, stages := range result.StatBoosts {
+		battler.ModifyStat(stat, stages)
 	}
 
-	return logs
+	return logs, result.NegatePoison
 }
 
 // getStatName 获取能力名称
 func getStatName(stat string) string {
 	names := map[string]string{
-		"attack":    "攻击",
-		"defense":   "防御",
-		"spattack":  "特攻",
-		"spdefense": "特防",
-		"speed":     "速度",
-		"accuracy":  "命中",
-		"evasion":   "闪避",
+		"atk":      "攻击",
+		"def":      "防御",
+		"spatk":    "特攻",
+		"spdef":    "特防",
+		"speed":    "速度",
+		"accuracy": "命中",
+		"evasion":  "闪避",
 	}
 	if name, ok := names[stat]; ok {
 		return name
@@ -884,5 +1312,5 @@ func randInt(max int) int {
 	if max <= 0 {
 		return 0
 	}
-	return int(time.Now().UnixNano() % int64(max))
+	return rand.Intn(max)
 }
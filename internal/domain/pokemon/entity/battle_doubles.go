@@ -0,0 +1,231 @@
+package entity
+
+import "github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+
+// ============================================
+// 双打对战（场上位置、技能目标、范围技能、吸引类特性）
+// ============================================
+
+// SpreadDamageModifier 范围技能同时命中多个目标时的伤害修正
+const SpreadDamageModifier = 0.75
+
+// IsDoubles 是否为双打对战
+func (b *Battle) IsDoubles() bool {
+	return b.Config != nil && b.Config.Mode == valueobject.ModeDouble
+}
+
+// ActiveSlotCount 每方在场宝可梦的数量
+func (b *Battle) ActiveSlotCount() int {
+	if b.IsDoubles() {
+		return 2
+	}
+	return 1
+}
+
+// GetDisplayName 获取对战模式显示名称
+func (b *Battle) GetDisplayName() string {
+	if b.NeedsTeamPreview() {
+		return itoa(int(b.TeamSize)) + "选" + itoa(b.BringCount()) + " " + b.Config.Mode.DisplayName()
+	}
+	if b.IsDoubles() {
+		return itoa(int(b.TeamSize)) + "v" + itoa(int(b.TeamSize)) + " " + b.Config.Mode.DisplayName()
+	}
+	return b.TeamSize.GetDisplayName()
+}
+
+// GetSlot 获取指定场上位置的宝可梦
+func (p *BattlePlayer) GetSlot(slot int) *Battler {
+	switch slot {
+	case 0:
+		return p.Pokemon
+	case 1:
+		return p.Partner
+	}
+	return nil
+}
+
+// setSlot 将宝可梦放到指定场上位置
+func (p *BattlePlayer) setSlot(slot int, battler *Battler) {
+	index := -1
+	for i, member := range p.Team {
+		if member == battler {
+			index = i
+			break
+		}
+	}
+	if slot == 1 {
+		p.Partner = battler
+		p.PartnerIndex = index
+		return
+	}
+	p.Pokemon = battler
+	p.ActiveIndex = index
+}
+
+// GetSlotAction 获取指定场上位置的行动
+func (p *BattlePlayer) GetSlotAction(slot int) *BattleAction {
+	switch slot {
+	case 0:
+		return p.Action
+	case 1:
+		return p.PartnerAction
+	}
+	return nil
+}
+
+// SetSlotAction 设置指定场上位置的行动（不做校验，供 AI 使用）
+func (p *BattlePlayer) SetSlotAction(slot int, action *BattleAction) {
+	p.setSlotAction(slot, action)
+}
+
+// setSlotAction 设置指定场上位置的行动
+func (p *BattlePlayer) setSlotAction(slot int, action *BattleAction) {
+	if slot == 1 {
+		p.PartnerAction = action
+		return
+	}
+	p.Action = action
+}
+
+// ActiveBattlers 获取在场的宝可梦（按位置顺序，可能包含已倒下的）
+func (p *BattlePlayer) ActiveBattlers() []*Battler {
+	active := make([]*Battler, 0, 2)
+	if p.Pokemon != nil {
+		active = append(active, p.Pokemon)
+	}
+	if p.Partner != nil {
+		active = append(active, p.Partner)
+	}
+	return active
+}
+
+// IsActive 宝可梦是否在场
+func (p *BattlePlayer) IsActive(battler *Battler) bool {
+	return battler != nil && (battler == p.Pokemon || battler == p.Partner)
+}
+
+// allyOf 获取同伴（不存在或已倒下时返回 nil）
+func (p *BattlePlayer) allyOf(battler *Battler) *Battler {
+	var ally *Battler
+	switch battler {
+	case p.Pokemon:
+		ally = p.Partner
+	case p.Partner:
+		ally = p.Pokemon
+	}
+	if ally == nil || !ally.IsAlive() {
+		return nil
+	}
+	return ally
+}
+
+// GetPendingSlot 获取玩家下一个需要选择行动的场上位置，全部选择完毕时返回 -1
+func (b *Battle) GetPendingSlot(player *BattlePlayer) int {
+	if player == nil {
+		return -1
+	}
+	for slot := 0; slot < b.ActiveSlotCount(); slot++ {
+		battler := player.GetSlot(slot)
+		if battler != nil && battler.IsAlive() && player.GetSlotAction(slot) == nil {
+			return slot
+		}
+	}
+	return -1
+}
+
+// MoveNeedsTarget 技能在当前模式下是否需要玩家选择目标
+func (b *Battle) MoveNeedsTarget(move *Move) bool {
+	if !b.IsDoubles() {
+		return false
+	}
+	return move.Target.IsSingleTarget()
+}
+
+// resolveMoveTargets 根据技能目标类型与玩家选择确定实际目标
+// 单体技能的目标倒下时改为另一只对手；电/水属性单体技能会被避雷针/引水吸引
+func (b *Battle) resolveMoveTargets(player *BattlePlayer, user *Battler, action *BattleAction, opponent *BattlePlayer, move *Move) ([]*Battler, []string) {
+	logs := make([]string, 0)
+	foes := make([]*Battler, 0, 2)
+	for _, battler := range opponent.ActiveBattlers() {
+		if battler.IsAlive() {
+			foes = append(foes, battler)
+		}
+	}
+	ally := player.allyOf(user)
+
+	switch move.Target {
+	case TargetAllOpponents:
+		return foes, logs
+	case TargetAllOtherPokemon, TargetAllPokemon:
+		if ally != nil {
+			return append(foes, ally), logs
+		}
+		return foes, logs
+	case TargetRandomOpponent:
+		if len(foes) == 0 {
+			return nil, logs
+		}
+		return []*Battler{foes[randInt(len(foes))]}, logs
+	case TargetAlly, TargetAllAllies:
+		if ally == nil {
+			return nil, logs
+		}
+		return []*Battler{ally}, logs
+	case TargetOpponentsField, TargetEntireField:
+		if len(foes) == 0 {
+			return nil, logs
+		}
+		return foes[:1], logs
+	}
+
+	if move.Target.IsSelfTarget() {
+		return []*Battler{user}, logs
+	}
+
+	// 单体技能
+	var target *Battler
+	if action.TargetAlly && ally != nil {
+		target = ally
+	} else {
+		chosen := opponent.GetSlot(action.TargetSlot)
+		if chosen != nil && chosen.IsAlive() {
+			target = chosen
+		} else if len(foes) > 0 {
+			target = foes[0]
+		}
+	}
+	if target == nil {
+		return nil, logs
+	}
+
+	if redirector := b.findRedirector(user, target, player, opponent, move); redirector != nil {
+		target = redirector
+		abilityName := ""
+		if redirector.Ability != nil {
+			abilityName = redirector.Ability.Name
+		}
+		logs = append(logs, "🧲 "+redirector.Pokemon.Name+" 的"+abilityName+"吸引了技能！")
+	}
+	return []*Battler{target}, logs
+}
+
+// findRedirector 寻找会吸引该技能的宝可梦（避雷针、引水），原目标本身具有该特性时不转移
+func (b *Battle) findRedirector(user, target *Battler, player, opponent *BattlePlayer, move *Move) *Battler {
+	if b.AbilityService == nil || !b.IsDoubles() {
+		return nil
+	}
+	moveAdapter := NewMoveAdapter(move)
+	if b.AbilityService.RedirectsMove(target, moveAdapter) {
+		return nil
+	}
+	candidates := append(opponent.ActiveBattlers(), player.ActiveBattlers()...)
+	for _, battler := range candidates {
+		if battler == user || battler == target || !battler.IsAlive() {
+			continue
+		}
+		if b.AbilityService.RedirectsMove(battler, moveAdapter) {
+			return battler
+		}
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+package entity
+
+import (
+	"sort"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 特殊系统（太晶化、极巨化、超级进化、Z招式）
+// ============================================
+
+// GimmickAllowed 本场对战是否允许使用指定的特殊系统
+func (b *Battle) GimmickAllowed(g valueobject.GimmickSystem) bool {
+	return b.Config != nil && b.Config.AllowsGimmick(g)
+}
+
+// CanUseGimmick 检查玩家指定位置的宝可梦本回合能否发动特殊系统
+func (b *Battle) CanUseGimmick(player *BattlePlayer, slot int, g valueobject.GimmickSystem) (bool, string) {
+	if !b.GimmickAllowed(g) {
+		return false, "本场对战不能使用" + g.DisplayName()
+	}
+	battler := player.GetSlot(slot)
+	if battler == nil {
+		return false, "该位置没有宝可梦"
+	}
+	// 同一回合两个位置不能同时发动同一种特殊系统（每队限一次）
+	if other := player.GetSlotAction(1 - slot); b.IsDoubles() && other != nil && other.Gimmick == g {
+		return false, "本回合同伴已选择" + g.DisplayName()
+	}
+
+	switch g {
+	case valueobject.GimmickTerastal:
+		if player.UsedTera {
+			return false, "每支队伍只能太晶化一次"
+		}
+		if battler.IsTerastalized {
+			return false, battler.Pokemon.Name + " 已经太晶化了"
+		}
+		if battler.IsDynamaxed {
+			return false, "极巨化的宝可梦不能太晶化"
+		}
+		if battler.TeraType == "" {
+			return false, battler.Pokemon.Name + " 没有太晶属性"
+		}
+		return true, ""
+	case valueobject.GimmickDynamax:
+		if player.UsedDynamax {
+			return false, "每支队伍只能极巨化一次"
+		}
+		if battler.IsDynamaxed {
+			return false, battler.Pokemon.Name + " 已经极巨化了"
+		}
+		if battler.IsTerastalized {
+			return false, "太晶化的宝可梦不能极巨化"
+		}
+		if battler.IsMega {
+			return false, "超级进化的宝可梦不能极巨化"
+		}
+		if battler.IsPrimal {
+			return false, "原始回归的宝可梦不能极巨化"
+		}
+		return true, ""
+	case valueobject.GimmickMegaEvo:
+		if player.UsedMega {
+			return false, "每支队伍只能超级进化一次"
+		}
+		if battler.IsMega {
+			return false, battler.Pokemon.Name + " 已经超级进化了"
+		}
+		if battler.IsDynamaxed || battler.IsTerastalized {
+			return false, battler.Pokemon.Name + " 现在不能超级进化"
+		}
+		if battler.AvailableMegaForm() == nil {
+			return false, battler.Pokemon.Name + " 没有携带对应的超级石"
+		}
+		return true, ""
+	case valueobject.GimmickZMove:
+		if player.UsedZMove {
+			return false, "每支队伍只能使用一次Z招式"
+		}
+		if battler.IsDynamaxed {
+			return false, "极巨化的宝可梦不能使用Z招式"
+		}
+		if _, ok := battler.ZCrystalType(); !ok {
+			return false, battler.Pokemon.Name + " 没有携带Z纯晶"
+		}
+		for _, move := range battler.Moves {
+			if move.CanUse() && battler.CanZMove(move) {
+				return true, ""
+			}
+		}
+		return false, battler.Pokemon.Name + " 没有与Z纯晶属性相同的技能"
+	}
+	return false, "无效的特殊系统"
+}
+
+// executeGimmicks 回合开始时按速度顺序发动双方选择的特殊系统
+func (b *Battle) executeGimmicks() []string {
+	type gimmickEntry struct {
+		player  *BattlePlayer
+		battler *Battler
+		gimmick valueobject.GimmickSystem
+	}
+	entries := make([]gimmickEntry, 0)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for slot := 0; slot < b.ActiveSlotCount(); slot++ {
+			battler := player.GetSlot(slot)
+			action := player.GetSlotAction(slot)
+			if battler == nil || !battler.IsAlive() || action == nil || action.Type != ActionMove || action.Gimmick == "" {
+				continue
+			}
+			entries = append(entries, gimmickEntry{player: player, battler: battler, gimmick: action.Gimmick})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return b.GetEffectiveSpeed(entries[i].battler) > b.GetEffectiveSpeed(entries[j].battler)
+	})
+
+	logs := make([]string, 0)
+	for _, entry := range entries {
+		switch entry.gimmick {
+		case valueobject.GimmickTerastal:
+			if entry.player.UsedTera || entry.battler.IsTerastalized {
+				continue
+			}
+			entry.player.UsedTera = true
+			entry.battler.Terastallize()
+			logs = append(logs, "💎 "+entry.battler.Pokemon.Name+" 太晶化了！变成了 "+string(entry.battler.TeraType)+" 属性！")
+		case valueobject.GimmickDynamax:
+			if entry.player.UsedDynamax || entry.battler.IsDynamaxed {
+				continue
+			}
+			entry.player.UsedDynamax = true
+			entry.battler.Dynamax()
+			logs = append(logs, "🔴 "+entry.battler.Pokemon.Name+" 极巨化了！HP: "+itoa(entry.battler.CurrentHP)+"/"+itoa(entry.battler.MaxHP))
+		case valueobject.GimmickMegaEvo:
+			form := entry.battler.AvailableMegaForm()
+			if entry.player.UsedMega || entry.battler.IsMega || form == nil {
+				continue
+			}
+			entry.player.UsedMega = true
+			oldName := entry.battler.Pokemon.Name
+			entry.battler.MegaEvolve(form)
+			if form.StoneID == 0 {
+				logs = append(logs, "🧬 "+entry.player.Username+" 的强烈心愿传达到了 "+oldName+"！")
+			} else {
+				logs = append(logs, "🧬 "+oldName+" 的"+entry.battler.Item.Name+"与钥石产生了反应！")
+			}
+			logs = append(logs, "🧬 "+oldName+" 超级进化成了 "+form.Name+"！")
+			// 超级进化后的特性立即生效（如威吓、日照）
+			if opponent := b.getOpponentPokemon(entry.player); opponent != nil {
+				logs = append(logs, b.TriggerEntryAbility(entry.battler, opponent)...)
+			}
+		}
+	}
+	return logs
+}
+
+// tickDynamax 回合结束时极巨化回合数减少，结束时恢复原本的HP
+func (b *Battle) tickDynamax() []string {
+	logs := make([]string, 0)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for _, battler := range player.ActiveBattlers() {
+			if !battler.IsDynamaxed || !battler.IsAlive() {
+				continue
+			}
+			battler.DynamaxTurns--
+			if battler.DynamaxTurns <= 0 {
+				battler.EndDynamax()
+				logs = append(logs, "🔴 "+battler.Pokemon.Name+" 的极巨化结束了！HP: "+itoa(battler.CurrentHP)+"/"+itoa(battler.MaxHP))
+			}
+		}
+	}
+	return logs
+}
+
+// applyMaxMoveEffect 极巨招式命中后的追加效果（天气、场地、能力变化）
+func (b *Battle) applyMaxMoveEffect(player *BattlePlayer, user, target *Battler, move *Move) []string {
+	logs := make([]string, 0)
+	effect, ok := maxMoveEffects[move.Type]
+	if !ok {
+		return logs
+	}
+
+	switch {
+	case effect.Weather != "":
+		// 原始天气无法被普通天气覆盖
+		if b.SetWeather(effect.Weather, user) {
+			logs = append(logs, effect.Weather.StartMessage())
+		}
+	case effect.Terrain != valueobject.TerrainNone:
+		if b.SetTerrain(effect.Terrain) {
+			logs = append(logs, effect.Terrain.StartMessage())
+		}
+	case effect.SelfStat != "":
+		for _, battler := range player.ActiveBattlers() {
+			if battler.IsAlive() {
+				statLogs, _ := b.applyStatChanges(battler, map[string]int{effect.SelfStat: 1})
+				logs = append(logs, statLogs...)
+			}
+		}
+	case effect.FoeStat != "":
+		if opponent := b.GetOwner(target); opponent != nil && opponent != player {
+			for _, battler := range opponent.ActiveBattlers() {
+				if battler.IsAlive() {
+					statLogs, _ := b.applyStatChanges(battler, map[string]int{effect.FoeStat: -1})
+					logs = append(logs, statLogs...)
+				}
+			}
+		}
+	}
+	return logs
+}
+
+// ResolveMove 获取宝可梦实际使用的技能（极巨化时转换为极巨招式）
+func (b *Battler) ResolveMove(move *Move) *Move {
+	if b.IsDynamaxed {
+		return MaxMoveFor(move)
+	}
+	return move
+}
+
+// Dynamax 极巨化：最大HP与当前HP翻倍，持续 3 回合
+func (b *Battler) Dynamax() {
+	b.IsDynamaxed = true
+	b.DynamaxTurns = DynamaxTurns
+	b.MaxHP *= 2
+	b.CurrentHP *= 2
+}
+
+// EndDynamax 极巨化结束：按比例恢复原本的HP
+func (b *Battler) EndDynamax() {
+	if !b.IsDynamaxed {
+		return
+	}
+	b.IsDynamaxed = false
+	b.DynamaxTurns = 0
+	maxHP := b.MaxHP / 2
+	hp := (b.CurrentHP*maxHP + b.MaxHP - 1) / b.MaxHP
+	b.MaxHP = maxHP
+	b.CurrentHP = hp
+}
+
+// AvailableMegaForm 获取可发动的超级进化形态，没有时返回 nil
+// 通常需要携带对应的超级石；烈空坐不需要超级石，学会画龙点睛且未携带Z纯晶即可超级进化
+func (b *Battler) AvailableMegaForm() *MegaForm {
+	for _, form := range b.Pokemon.MegaForms {
+		if form.Primal {
+			continue
+		}
+		if form.StoneID != 0 {
+			if b.Item != nil && !b.ItemConsumed && b.Item.Category == valueobject.ItemCategoryMega && b.Item.ID == form.StoneID {
+				return form
+			}
+			continue
+		}
+		if _, holdsZCrystal := b.ZCrystalType(); form.MoveID != 0 && !holdsZCrystal && b.knowsMove(form.MoveID) {
+			return form
+		}
+	}
+	return nil
+}
+
+// knowsMove 是否学会指定技能
+func (b *Battler) knowsMove(moveID int) bool {
+	for _, move := range b.Moves {
+		if move.ID == moveID {
+			return true
+		}
+	}
+	return false
+}
+
+// formPokemon 获取宝可梦变为超级进化或原始回归形态后的数据（不修改原宝可梦）
+func formPokemon(pokemon *Pokemon, form *MegaForm) *Pokemon {
+	changed := *pokemon
+	changed.ID = form.PokemonID
+	changed.Name = form.Name
+	changed.Types = append([]valueobject.PokeType{}, form.Types...)
+	changed.SetBaseStats(form.BaseStats.HP, form.BaseStats.Atk, form.BaseStats.Def,
+		form.BaseStats.SpAtk, form.BaseStats.SpDef, form.BaseStats.Speed)
+	changed.SpriteURL = form.SpriteURL
+	return &changed
+}
+
+// MegaEvolve 超级进化：属性、种族值与特性变为超级进化形态，HP保持不变
+func (b *Battler) MegaEvolve(form *MegaForm) {
+	b.transformInto(form)
+	b.IsMega = true
+}
+
+// transformInto 变为超级进化或原始回归形态：属性、种族值与特性随之改变，HP保持不变
+func (b *Battler) transformInto(form *MegaForm) {
+	b.Pokemon = formPokemon(b.Pokemon, form)
+
+	hp := b.CurrentHP
+	b.calculateStats()
+	if hp < b.MaxHP {
+		b.CurrentHP = hp
+	}
+	b.Types = append([]valueobject.PokeType{}, form.Types...)
+	if form.Ability != nil {
+		b.Ability = form.Ability
+	}
+}
+
+// ZCrystalType 获取携带的Z纯晶对应的属性
+func (b *Battler) ZCrystalType() (valueobject.PokeType, bool) {
+	if b.Item == nil || b.ItemConsumed {
+		return "", false
+	}
+	return valueobject.ZCrystalType(b.Item)
+}
+
+// CanZMove 技能能否借助携带的Z纯晶变为Z招式（属性需与Z纯晶一致）
+func (b *Battler) CanZMove(move *Move) bool {
+	crystalType, ok := b.ZCrystalType()
+	return ok && !move.IsMax && move.Type == crystalType
+}
+
+// applyZStatusEffect 变化技能Z招式的追加效果（在技能效果之前发动）
+func (b *Battle) applyZStatusEffect(user *Battler, move *Move) []string {
+	effect := zStatusEffectFor(move)
+	switch {
+	case effect.Heal:
+		if user.CurrentHP >= user.MaxHP {
+			return nil
+		}
+		healed := user.Heal(user.MaxHP)
+		return []string{"💚 " + user.Pokemon.Name + " 回复了 " + itoa(healed) + " HP！"}
+	case effect.Crit:
+		logs, _ := b.ApplyVolatile(user, user, VolatileFocusEnergy, false)
+		return logs
+	case len(effect.Stats) > 0:
+		logs, _ := b.applyStatChanges(user, effect.Stats)
+		return logs
+	}
+	if user.resetLoweredStats() {
+		return []string{"✨ " + user.Pokemon.Name + " 下降的能力复原了！"}
+	}
+	return nil
+}
+
+// resetLoweredStats 将下降的能力等级恢复为 0，返回是否有能力被复原
+func (b *Battler) resetLoweredStats() bool {
+	reset := false
+	for _, stage := range []*int{&b.StatStages.Atk, &b.StatStages.Def, &b.StatStages.SpAtk, &b.StatStages.SpDef,
+		&b.StatStages.Speed, &b.StatStages.Accuracy, &b.StatStages.Evasion} {
+		if *stage < 0 {
+			*stage = 0
+			reset = true
+		}
+	}
+	return reset
+}
+
+// Terastallize 太晶化：防御属性变为太晶属性，原属性保留同属性加成
+func (b *Battler) Terastallize() {
+	b.PreTeraTypes = append([]valueobject.PokeType{}, b.Types...)
+	b.Types = []valueobject.PokeType{b.TeraType}
+	b.IsTerastalized = true
+}
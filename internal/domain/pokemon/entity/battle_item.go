@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+)
+
+// ============================================
+// 携带道具（效果由 item.Service 提供，这里负责结算）
+// ============================================
+
+// ItemAllowsMove 检查携带道具是否允许选择该技能（讲究系列锁定、突击背心）
+func (b *Battle) ItemAllowsMove(battler *Battler, move *Move) (bool, string) {
+	if b.ItemService == nil || battler == nil {
+		return true, ""
+	}
+	// 极巨化期间不受讲究系列限制；锁定的技能用尽PP后也不再限制
+	if battler.ChoiceLock != nil && !battler.IsDynamaxed && b.ItemService.IsChoiceItem(battler) &&
+		move != battler.ChoiceLock && battler.ChoiceLock.CanUse() {
+		return false, "受" + battler.Item.Name + "影响只能使用 " + battler.ChoiceLock.Name
+	}
+	if result := b.ItemService.CheckMoveSelect(battler, NewMoveAdapter(move)); result != nil && result.Blocked {
+		return false, result.Reason
+	}
+	return true, ""
+}
+
+// recordMoveUse 记录技能使用（节拍器连续次数、讲究系列锁定）
+func (b *Battle) recordMoveUse(user *Battler, move *Move) {
+	if user.LastMove == move {
+		user.LastMoveTurns++
+	} else {
+		user.LastMoveTurns = 1
+	}
+	user.LastMove = move
+
+	if user.ChoiceLock == nil && !user.IsDynamaxed && b.ItemService != nil && b.ItemService.IsChoiceItem(user) {
+		user.ChoiceLock = move
+	}
+}
+
+// mergeItemDamageMods 将道具伤害修正合并到特性修正中
+// 返回合并后的修正、消息以及是否因道具免疫（如气球）
+func (b *Battle) mergeItemDamageMods(attacker, defender *Battler, move *Move, mod *ability.DamageModifier) (*ability.DamageModifier, []string, bool) {
+	if b.ItemService == nil || move.Category == CategoryStatus {
+		return mod, nil, false
+	}
+	itemMod, messages := b.ItemService.CalculateDamageWithItems(attacker, defender, NewMoveAdapter(move), b.GetBattleContext())
+	if itemMod.Immune {
+		return mod, messages, true
+	}
+	if mod == nil {
+		mod = ability.NewDamageModifier()
+	}
+	mod.PowerMod *= itemMod.PowerMod
+	mod.AttackMod *= itemMod.AttackMod
+	mod.DefenseMod *= itemMod.DefenseMod
+	mod.DamageMod *= itemMod.DamageMod
+	return mod, messages, false
+}
+
+// takeDamageWithItem 受到伤害（含气势披带等致命伤害道具）
+func (b *Battle) takeDamageWithItem(battler *Battler, damage int) (int, []string) {
+	logs := make([]string, 0)
+	if b.ItemService != nil {
+		result := b.ItemService.CheckLethalDamage(battler, damage, b.GetBattleContext())
+		if result != nil && result.Survive {
+			damage = battler.CurrentHP - 1
+			logs = append(logs, result.Message)
+			if result.Consume {
+				battler.ConsumeItem()
+			}
+		}
+	}
+	return battler.TakeDamage(damage), logs
+}
+
+// triggerBeingHitItem 触发受击道具（如凸凸头盔、气球）
+func (b *Battle) triggerBeingHitItem(defender, attacker *Battler, move *Move, damage int) []string {
+	logs := make([]string, 0)
+	if b.ItemService == nil {
+		return logs
+	}
+	result := b.ItemService.TriggerBeingHit(defender, attacker, NewMoveAdapter(move), damage, b.GetBattleContext())
+	if result == nil {
+		return logs
+	}
+	logs = append(logs, result.Messages...)
+	if result.RecoilDamage > 0 && attacker.IsAlive() {
+		attacker.TakeDamage(result.RecoilDamage)
+	}
+	if result.Consume {
+		defender.ConsumeItem()
+	}
+	return logs
+}
+
+// triggerAfterAttackItem 触发攻击后道具（如生命宝珠反伤）
+func (b *Battle) triggerAfterAttackItem(user *Battler, move *Move, damage int) []string {
+	logs := make([]string, 0)
+	if b.ItemService == nil || !user.IsAlive() {
+		return logs
+	}
+	result := b.ItemService.TriggerAfterAttack(user, NewMoveAdapter(move), damage, b.GetBattleContext())
+	if result == nil {
+		return logs
+	}
+	logs = append(logs, result.Messages...)
+	if result.RecoilDamage > 0 {
+		user.TakeDamage(result.RecoilDamage)
+	}
+	if result.Consume {
+		user.ConsumeItem()
+	}
+	return logs
+}
+
+// checkHPThresholdItem HP变化后检查阈值道具（如文柚果）
+func (b *Battle) checkHPThresholdItem(battler *Battler) []string {
+	logs := make([]string, 0)
+	if b.ItemService == nil || battler == nil {
+		return logs
+	}
+	result := b.ItemService.CheckHPThreshold(battler, b.GetBattleContext())
+	if result == nil {
+		return logs
+	}
+	logs = append(logs, result.Messages...)
+	if result.HealAmount > 0 {
+		battler.Heal(result.HealAmount)
+	}
+	if result.Consume {
+		battler.ConsumeItem()
+	}
+	return logs
+}
+
+// processTurnEndItem 处理单只宝可梦的回合结束道具（如吃剩的东西、黑色污泥）
+func (b *Battle) processTurnEndItem(battler *Battler) []string {
+	logs := make([]string, 0)
+	if b.ItemService == nil || !battler.IsAlive() {
+		return logs
+	}
+	result := b.ItemService.TriggerTurnEnd(battler, b.GetBattleContext())
+	if result != nil {
+		logs = append(logs, result.Messages...)
+		if result.HealAmount > 0 {
+			battler.Heal(result.HealAmount)
+		}
+		if result.DamageAmount > 0 {
+			battler.TakeDamage(result.DamageAmount)
+		}
+		if result.Consume {
+			battler.ConsumeItem()
+		}
+	}
+	return append(logs, b.checkHPThresholdItem(battler)...)
+}
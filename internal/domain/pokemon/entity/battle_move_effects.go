@@ -0,0 +1,294 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 技能追加效果（能力变化、异常状态、回复、吸取、反作用、畏缩）
+// ============================================
+
+// statOrder 能力变化的输出顺序
+var statOrder = []string{"atk", "def", "spatk", "spdef", "speed", "accuracy", "evasion"}
+
+// protectMoves 守住类技能（按技能ID，与极巨防壁共用连续使用计数）
+var protectMoves = map[int]bool{
+	182: true, // 守住
+	197: true, // 看穿
+}
+
+// maxProtectCount 连续使用计数上限（成功率最低为 1/729）
+const maxProtectCount = 6
+
+// executeProtect 使用守住类技能：连续成功使用时成功率每次降为 1/3，失败时计数归零
+func (b *Battle) executeProtect(user *Battler) []string {
+	chance := 1
+	for i := 0; i < user.ProtectCount && i < maxProtectCount; i++ {
+		chance *= 3
+	}
+	if randInt(chance) != 0 {
+		user.ProtectCount = 0
+		return []string{"❌ 但是失败了！"}
+	}
+	user.Protected = true
+	user.ProtectCount++
+	return []string{"🛡️ " + user.Pokemon.Name + " 摆出了防守的架势！"}
+}
+
+// executeStatusMove 执行变化技能的效果
+func (b *Battle) executeStatusMove(attacker, defender *Battler, move *Move) []string {
+	logs := make([]string, 0)
+
+	// 临时状态类技能（挑衅、再来一次、替身、聚气）
+	if status, ok := volatileMoveEffects[move.ID]; ok {
+		target := defender
+		if move.Target.IsSelfTarget() {
+			target = attacker
+		}
+		volatileLogs, applied := b.ApplyVolatile(target, attacker, status, true)
+		logs = append(logs, volatileLogs...)
+		if !applied && len(logs) == 0 {
+			logs = append(logs, "❌ 但是失败了！")
+		}
+		return logs
+	}
+
+	// 场地状态类技能（隐形岩、撒菱、反射壁、顺风等）
+	if condition, ok := sideConditionMoves[move.ID]; ok {
+		return append(logs, b.executeSideConditionMove(attacker, condition)...)
+	}
+
+	// 展开场地的技能（电气场地、青草场地等）
+	if terrain, ok := terrainMoves[move.ID]; ok {
+		return append(logs, b.executeTerrainMove(terrain)...)
+	}
+
+	// 改变天气的技能（大晴天、求雨、沙暴等）
+	if weather, ok := weatherMoves[move.ID]; ok {
+		return append(logs, b.executeWeatherMove(attacker, weather)...)
+	}
+
+	// 清除浓雾：清除陷阱与墙壁不受替身影响
+	if move.ID == moveDefog {
+		logs = append(logs, b.applyHazardRemoval(attacker, move)...)
+	}
+
+	// 替身阻挡对手的变化技能
+	targetsDefender := !move.Target.IsSelfTarget() && defender != attacker
+	if targetsDefender && defender.HasVolatileStatus(VolatileSubstitute) && !move.StatChangeTargetsSelf() {
+		if len(logs) == 0 {
+			logs = append(logs, "❌ 但是失败了！")
+		}
+		return logs
+	}
+
+	meta := move.Meta
+	if meta == nil {
+		logs = append(logs, "✨ 效果发动了！")
+		return logs
+	}
+
+	applied := false
+
+	// 能力变化
+	if len(meta.StatChanges) > 0 {
+		target := defender
+		if move.StatChangeTargetsSelf() {
+			target = attacker
+		}
+		statLogs, changed := b.applyStatChanges(target, meta.StatChanges)
+		logs = append(logs, statLogs...)
+		applied = applied || changed
+	}
+
+	// 异常状态 / 临时状态
+	if meta.Ailment != "" {
+		if meta.AilmentChance == 0 || randInt(100) < meta.AilmentChance {
+			ailmentLogs, inflicted := b.inflictAilment(defender, attacker, meta.Ailment, true)
+			logs = append(logs, ailmentLogs...)
+			applied = applied || inflicted
+		}
+	}
+
+	// 回复
+	if meta.Healing > 0 {
+		if attacker.CurrentHP >= attacker.MaxHP {
+			logs = append(logs, "💚 "+attacker.Pokemon.Name+" 的HP已经满了！")
+		} else {
+			healed := attacker.Heal(attacker.MaxHP * meta.Healing / 100)
+			logs = append(logs, "💚 "+attacker.Pokemon.Name+" 回复了 "+itoa(healed)+" HP！")
+			applied = true
+		}
+	} else if meta.Healing < 0 {
+		lost := attacker.TakeDamage(attacker.MaxHP * -meta.Healing / 100)
+		logs = append(logs, "💔 "+attacker.Pokemon.Name+" 失去了 "+itoa(lost)+" HP！")
+		applied = true
+	}
+
+	if !applied && len(logs) == 0 {
+		if meta.Category == MetaUnique || meta.Category == MetaWholeFieldEffect || meta.Category == MetaFieldEffect {
+			logs = append(logs, "✨ 效果发动了！")
+		} else {
+			logs = append(logs, "❌ 但是失败了！")
+		}
+	}
+	return logs
+}
+
+// applyMoveSecondaryEffects 处理攻击技能命中后的追加效果
+// damageDealt 为本次技能实际造成的总伤害；hitSubstitute 为 true 时不对目标施加追加效果
+func (b *Battle) applyMoveSecondaryEffects(attacker, defender *Battler, move *Move, damageDealt int, hitSubstitute bool) []string {
+	logs := make([]string, 0)
+	meta := move.Meta
+	if meta == nil {
+		return logs
+	}
+
+	// 吸取 / 反作用伤害
+	if meta.Drain > 0 && damageDealt > 0 && attacker.IsAlive() && attacker.CurrentHP < attacker.MaxHP {
+		amount := damageDealt * meta.Drain / 100
+		if amount < 1 {
+			amount = 1
+		}
+		healed := attacker.Heal(amount)
+		logs = append(logs, "💚 "+attacker.Pokemon.Name+" 吸取了 "+itoa(healed)+" HP！")
+	} else if meta.Drain < 0 && damageDealt > 0 && attacker.IsAlive() {
+		amount := damageDealt * -meta.Drain / 100
+		if amount < 1 {
+			amount = 1
+		}
+		attacker.TakeDamage(amount)
+		logs = append(logs, "💥 "+attacker.Pokemon.Name+" 受到了 "+itoa(amount)+" 点反作用伤害！")
+	}
+
+	// 能力变化（降低目标能力 / 改变自身能力）
+	if len(meta.StatChanges) > 0 && (meta.StatChance == 0 || randInt(100) < meta.StatChance) {
+		target := defender
+		if move.StatChangeTargetsSelf() {
+			target = attacker
+		}
+		if target.IsAlive() && (target == attacker || !hitSubstitute) {
+			statLogs, _ := b.applyStatChanges(target, meta.StatChanges)
+			logs = append(logs, statLogs...)
+		}
+	}
+
+	if !defender.IsAlive() || hitSubstitute {
+		return logs
+	}
+
+	// 火属性攻击使冰冻的目标解冻
+	if defender.Status == StatusFreeze && move.Type == valueobject.TypeFire {
+		b.CureStatus(defender)
+		logs = append(logs, "🔥 "+defender.Pokemon.Name+" 的冰融化了！")
+	}
+
+	// 追加异常状态
+	if meta.Ailment != "" && meta.AilmentChance > 0 && randInt(100) < meta.AilmentChance {
+		ailmentLogs, _ := b.inflictAilment(defender, attacker, meta.Ailment, false)
+		logs = append(logs, ailmentLogs...)
+	}
+
+	// 畏缩（只对本回合尚未行动的目标有效，回合结束时清除）
+	if meta.FlinchChance > 0 && randInt(100) < meta.FlinchChance {
+		immune := false
+		if b.AbilityService != nil {
+			result := b.AbilityService.CheckStatusImmunity(defender, "畏缩", b.GetBattleContext())
+			immune = result != nil && result.Immune
+		}
+		// 极巨化的宝可梦不会畏缩
+		if !immune && !defender.IsDynamaxed {
+			defender.Flinched = true
+		}
+	}
+
+	return logs
+}
+
+// applyStatChanges 应用能力等级变化并生成日志
+func (b *Battle) applyStatChanges(target *Battler, changes map[string]int) ([]string, bool) {
+	logs := make([]string, 0)
+	changedAny := false
+	for _, stat := range statOrder {
+		stages, ok := changes[stat]
+		if !ok || stages == 0 {
+			continue
+		}
+		delta, changed := target.ModifyStat(stat, stages)
+		if !changed {
+			if stages > 0 {
+				logs = append(logs, "⚠️ "+target.Pokemon.Name+" 的"+getStatName(stat)+"已经无法再提升了！")
+			} else {
+				logs = append(logs, "⚠️ "+target.Pokemon.Name+" 的"+getStatName(stat)+"已经无法再降低了！")
+			}
+			continue
+		}
+		changedAny = true
+		logs = append(logs, getStatChangeLog(target.Pokemon.Name, stat, delta))
+	}
+	return logs, changedAny
+}
+
+// inflictAilment 对目标施加异常状态或临时状态
+// source 为施加者，announce 为 true 时输出失败原因（用于变化技能）
+func (b *Battle) inflictAilment(target, source *Battler, ailment string, announce bool) ([]string, bool) {
+	if isMajorStatus(ailment) {
+		if StatusCondition(ailment) == StatusSleep && b.sleepClauseBlocks(target, source) {
+			if announce {
+				return []string{"💤 睡眠条款：对方已有宝可梦处于睡眠状态，无法再催眠！"}, false
+			}
+			return nil, false
+		}
+		logs, applied := b.ApplyStatus(target, StatusCondition(ailment), announce)
+		if applied && target.Status == StatusSleep && source != nil && source != target {
+			target.SleepInflicted = true
+		}
+		return logs, applied
+	}
+	return b.ApplyVolatile(target, source, VolatileStatus(ailment), announce)
+}
+
+// isMajorStatus 是否为主要异常状态（同一时间只能存在一个）
+func isMajorStatus(status string) bool {
+	switch StatusCondition(status) {
+	case StatusPoison, StatusBadPoison, StatusBurn, StatusParalyze, StatusSleep, StatusFreeze:
+		return true
+	}
+	return false
+}
+
+// getStatusEmoji 获取异常状态图标
+func getStatusEmoji(status string) string {
+	switch StatusCondition(status) {
+	case StatusPoison, StatusBadPoison:
+		return "☠️"
+	case StatusBurn:
+		return "🔥"
+	case StatusParalyze:
+		return "⚡"
+	case StatusSleep:
+		return "💤"
+	case StatusFreeze:
+		return "🧊"
+	}
+	return "💫"
+}
+
+// getStatChangeLog 生成能力变化日志
+func getStatChangeLog(name, stat string, delta int) string {
+	statName := getStatName(stat)
+	switch {
+	case delta >= 3:
+		return "📈 " + name + " 的" + statName + "巨幅提升了！"
+	case delta == 2:
+		return "📈 " + name + " 的" + statName + "大幅提升了！"
+	case delta == 1:
+		return "📈 " + name + " 的" + statName + "提升了！"
+	case delta == -1:
+		return "📉 " + name + " 的" + statName + "下降了！"
+	case delta == -2:
+		return "📉 " + name + " 的" + statName + "大幅下降了！"
+	default:
+		return "📉 " + name + " 的" + statName + "巨幅下降了！"
+	}
+}
@@ -0,0 +1,358 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/item"
+)
+
+// 对战的持久化（JSON 序列化）
+// 对战中有多处指针共享：场上宝可梦指向队伍成员、队伍指向报名成员、胜者指向玩家、
+// 着迷对象指向对手的宝可梦、技能锁定等指向技能副本。
+// 序列化时将这些引用保存为索引，恢复时重新关联，保证恢复后的对战与保存前行为一致。
+// 宝可梦的图鉴数据（种族值、可学技能等）只保存宝可梦ID，恢复后由 ResolvePokemon 从图鉴重新加载。
+
+// 与实体字段相同但不带方法的类型（避免序列化时递归调用 MarshalJSON）
+type (
+	battleFields  Battle
+	playerFields  BattlePlayer
+	battlerFields Battler
+	buildFields   PokemonBuild
+)
+
+// battleSnapshot 对战的序列化结构
+// 外层字段与实体字段同名，序列化时覆盖实体中的指针字段
+type battleSnapshot struct {
+	battleFields
+	Player1 *playerSnapshot `json:",omitempty"`
+	Player2 *playerSnapshot `json:",omitempty"`
+	Winner  string          `json:",omitempty"` // 胜者玩家ID
+}
+
+// playerSnapshot 玩家的序列化结构
+// 玩家的宝可梦统一保存在 Battlers 中，其余字段保存为 Battlers 中的索引（-1 表示空）
+type playerSnapshot struct {
+	playerFields
+	Battlers []*battlerSnapshot
+	Roster   []int
+	Team     []int
+	Pokemon  int
+	Partner  int
+}
+
+// battlerSnapshot 对战宝可梦的序列化结构
+type battlerSnapshot struct {
+	battlerFields
+	Build        *buildSnapshot `json:",omitempty"`
+	Pokemon      *pokemonRef    `json:",omitempty"` // 与 Build.Pokemon 不同时才保存（如超级进化后的形态）
+	AttractedTo  *battlerRef    `json:",omitempty"`
+	DisabledMove *moveRef       `json:",omitempty"`
+	EncoreMove   *moveRef       `json:",omitempty"`
+	LastMove     *moveRef       `json:",omitempty"`
+	ChoiceLock   *moveRef       `json:",omitempty"`
+	ChargingMove *moveRef       `json:",omitempty"`
+}
+
+// buildSnapshot 宝可梦配置的序列化结构（宝可梦只保存ID）
+type buildSnapshot struct {
+	buildFields
+	Pokemon *pokemonRef `json:",omitempty"`
+}
+
+// pokemonRef 宝可梦引用（宝可梦或形态的ID，恢复时从图鉴重新加载）
+type pokemonRef struct {
+	ID int
+}
+
+// PokemonLookup 按宝可梦（形态）ID 获取图鉴数据，不存在时返回 nil
+type PokemonLookup func(id int) *Pokemon
+
+// battlerRef 对战宝可梦引用（玩家ID + 该玩家 Battlers 中的索引）
+type battlerRef struct {
+	PlayerID string
+	Index    int
+}
+
+// moveRef 技能引用：技能在技能副本中时保存索引，否则（如Z招式、极巨招式）保存技能本身
+type moveRef struct {
+	Index int
+	Move  *Move `json:",omitempty"`
+}
+
+// MarshalJSON 序列化对战（用于持久化）
+func (b *Battle) MarshalJSON() ([]byte, error) {
+	refs := make(map[*Battler]battlerRef)
+	battlers := make(map[*BattlePlayer][]*Battler)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		battlers[player] = player.allBattlers()
+		for idx, battler := range battlers[player] {
+			refs[battler] = battlerRef{PlayerID: player.ID, Index: idx}
+		}
+	}
+
+	snapshot := battleSnapshot{battleFields: battleFields(*b)}
+	if b.Player1 != nil {
+		snapshot.Player1 = newPlayerSnapshot(b.Player1, battlers[b.Player1], refs)
+	}
+	if b.Player2 != nil {
+		snapshot.Player2 = newPlayerSnapshot(b.Player2, battlers[b.Player2], refs)
+	}
+	if b.Winner != nil {
+		snapshot.Winner = b.Winner.ID
+	}
+	return json.Marshal(snapshot)
+}
+
+// UnmarshalJSON 从持久化数据恢复对战，并重新创建特性与道具服务
+func (b *Battle) UnmarshalJSON(data []byte) error {
+	var snapshot battleSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	*b = Battle(snapshot.battleFields)
+
+	battlers := make(map[string][]*Battler)
+	if snapshot.Player1 != nil {
+		b.Player1 = snapshot.Player1.restore(battlers)
+	}
+	if snapshot.Player2 != nil {
+		b.Player2 = snapshot.Player2.restore(battlers)
+	}
+
+	// 着迷对象可能指向对手的宝可梦，需在双方都恢复后再关联
+	for _, player := range []*playerSnapshot{snapshot.Player1, snapshot.Player2} {
+		if player == nil {
+			continue
+		}
+		for idx, battler := range player.Battlers {
+			ref := battler.AttractedTo
+			if ref == nil {
+				continue
+			}
+			if targets := battlers[ref.PlayerID]; ref.Index >= 0 && ref.Index < len(targets) {
+				battlers[player.ID][idx].AttractedTo = targets[ref.Index]
+			}
+		}
+	}
+
+	if snapshot.Winner != "" {
+		b.Winner = b.GetPlayer(snapshot.Winner)
+	}
+	b.AbilityService = ability.NewService()
+	b.ItemService = item.NewService()
+	return nil
+}
+
+// ResolvePokemon 从图鉴重新加载恢复后对战中的宝可梦数据（持久化时只保存宝可梦ID）
+func (b *Battle) ResolvePokemon(lookup PokemonLookup) error {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		for _, battler := range player.allBattlers() {
+			if err := battler.resolvePokemon(lookup); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolvePokemon 重新加载宝可梦数据，超级进化等形态变化后的宝可梦按形态ID重新变化
+func (b *Battler) resolvePokemon(lookup PokemonLookup) error {
+	if b.Build == nil || b.Build.Pokemon == nil {
+		if b.Pokemon == nil {
+			return nil
+		}
+		pokemon := lookup(b.Pokemon.ID)
+		if pokemon == nil {
+			return fmt.Errorf("图鉴中没有宝可梦 #%d", b.Pokemon.ID)
+		}
+		b.Pokemon = pokemon
+		return nil
+	}
+
+	base := lookup(b.Build.Pokemon.ID)
+	if base == nil {
+		return fmt.Errorf("图鉴中没有宝可梦 #%d", b.Build.Pokemon.ID)
+	}
+	changed := b.Pokemon != nil && b.Pokemon != b.Build.Pokemon
+	formID := 0
+	if changed {
+		formID = b.Pokemon.ID
+	}
+	b.Build.Pokemon = base
+	if !changed {
+		b.Pokemon = base
+		return nil
+	}
+	for _, form := range base.MegaForms {
+		if form.PokemonID == formID {
+			b.Pokemon = formPokemon(base, form)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s 没有形态 #%d", base.Name, formID)
+}
+
+// allBattlers 获取玩家持有的全部宝可梦（去重，报名成员在前）
+func (p *BattlePlayer) allBattlers() []*Battler {
+	var battlers []*Battler
+	seen := make(map[*Battler]bool)
+	add := func(battler *Battler) {
+		if battler != nil && !seen[battler] {
+			seen[battler] = true
+			battlers = append(battlers, battler)
+		}
+	}
+	for _, battler := range p.Roster {
+		add(battler)
+	}
+	for _, battler := range p.Team {
+		add(battler)
+	}
+	add(p.Pokemon)
+	add(p.Partner)
+	return battlers
+}
+
+// newPlayerSnapshot 创建玩家的序列化结构
+func newPlayerSnapshot(p *BattlePlayer, battlers []*Battler, refs map[*Battler]battlerRef) *playerSnapshot {
+	index := func(battler *Battler) int {
+		if ref, ok := refs[battler]; ok {
+			return ref.Index
+		}
+		return -1
+	}
+
+	snapshot := &playerSnapshot{
+		playerFields: playerFields(*p),
+		Battlers:     make([]*battlerSnapshot, 0, len(battlers)),
+		Pokemon:      index(p.Pokemon),
+		Partner:      index(p.Partner),
+	}
+	for _, battler := range battlers {
+		snapshot.Battlers = append(snapshot.Battlers, newBattlerSnapshot(battler, refs))
+	}
+	for _, battler := range p.Roster {
+		snapshot.Roster = append(snapshot.Roster, index(battler))
+	}
+	for _, battler := range p.Team {
+		snapshot.Team = append(snapshot.Team, index(battler))
+	}
+	return snapshot
+}
+
+// restore 恢复玩家，并记录玩家的全部宝可梦（用于关联着迷对象）
+func (s *playerSnapshot) restore(battlers map[string][]*Battler) *BattlePlayer {
+	player := BattlePlayer(s.playerFields)
+
+	list := make([]*Battler, 0, len(s.Battlers))
+	for _, battler := range s.Battlers {
+		list = append(list, battler.restore())
+	}
+	battlers[player.ID] = list
+
+	lookup := func(index int) *Battler {
+		if index >= 0 && index < len(list) {
+			return list[index]
+		}
+		return nil
+	}
+	for _, index := range s.Roster {
+		if battler := lookup(index); battler != nil {
+			player.Roster = append(player.Roster, battler)
+		}
+	}
+	for _, index := range s.Team {
+		if battler := lookup(index); battler != nil {
+			player.Team = append(player.Team, battler)
+		}
+	}
+	player.Pokemon = lookup(s.Pokemon)
+	player.Partner = lookup(s.Partner)
+	return &player
+}
+
+// newBattlerSnapshot 创建对战宝可梦的序列化结构
+func newBattlerSnapshot(b *Battler, refs map[*Battler]battlerRef) *battlerSnapshot {
+	snapshot := &battlerSnapshot{
+		battlerFields: battlerFields(*b),
+		DisabledMove:  b.moveRef(b.DisabledMove),
+		EncoreMove:    b.moveRef(b.EncoreMove),
+		LastMove:      b.moveRef(b.LastMove),
+		ChoiceLock:    b.moveRef(b.ChoiceLock),
+		ChargingMove:  b.moveRef(b.ChargingMove),
+	}
+	if b.Build != nil {
+		snapshot.Build = &buildSnapshot{buildFields: buildFields(*b.Build)}
+		if b.Build.Pokemon != nil {
+			snapshot.Build.Pokemon = &pokemonRef{ID: b.Build.Pokemon.ID}
+		}
+	}
+	if b.Pokemon != nil && (b.Build == nil || b.Pokemon != b.Build.Pokemon) {
+		snapshot.Pokemon = &pokemonRef{ID: b.Pokemon.ID}
+	}
+	if ref, ok := refs[b.AttractedTo]; ok && b.AttractedTo != nil {
+		snapshot.AttractedTo = &ref
+	}
+	return snapshot
+}
+
+// restore 恢复对战宝可梦（着迷对象由对战统一关联，宝可梦暂时只有ID，由 ResolvePokemon 补全）
+func (s *battlerSnapshot) restore() *Battler {
+	battler := Battler(s.battlerFields)
+	if s.Build != nil {
+		build := PokemonBuild(s.Build.buildFields)
+		if s.Build.Pokemon != nil {
+			build.Pokemon = &Pokemon{ID: s.Build.Pokemon.ID}
+		}
+		battler.Build = &build
+	}
+	if s.Pokemon != nil {
+		battler.Pokemon = &Pokemon{ID: s.Pokemon.ID}
+	} else if battler.Build != nil {
+		battler.Pokemon = battler.Build.Pokemon
+	}
+	if battler.VolatileTurns == nil {
+		battler.VolatileTurns = make(map[VolatileStatus]int)
+	}
+	battler.DisabledMove = battler.resolveMoveRef(s.DisabledMove)
+	battler.EncoreMove = battler.resolveMoveRef(s.EncoreMove)
+	battler.LastMove = battler.resolveMoveRef(s.LastMove)
+	battler.ChoiceLock = battler.resolveMoveRef(s.ChoiceLock)
+	battler.ChargingMove = battler.resolveMoveRef(s.ChargingMove)
+	return &battler
+}
+
+// moveRef 创建技能引用
+func (b *Battler) moveRef(move *Move) *moveRef {
+	if move == nil {
+		return nil
+	}
+	for idx, m := range b.Moves {
+		if m == move {
+			return &moveRef{Index: idx}
+		}
+	}
+	return &moveRef{Index: -1, Move: move}
+}
+
+// resolveMoveRef 将技能引用还原为技能副本中的技能
+func (b *Battler) resolveMoveRef(ref *moveRef) *Move {
+	if ref == nil {
+		return nil
+	}
+	if ref.Move != nil {
+		return ref.Move
+	}
+	if ref.Index >= 0 && ref.Index < len(b.Moves) {
+		return b.Moves[ref.Index]
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ============================================
+// 队伍预览（从报名的队伍中选择出战宝可梦与首发）
+// ============================================
+
+// BringCount 每方实际出战的宝可梦数量
+func (b *Battle) BringCount() int {
+	if b.Config != nil && b.Config.BringCount > 0 && b.Config.BringCount < int(b.TeamSize) {
+		return b.Config.BringCount
+	}
+	return int(b.TeamSize)
+}
+
+// NeedsTeamPreview 是否需要队伍预览（出战数量少于报名数量）
+func (b *Battle) NeedsTeamPreview() bool {
+	return b.BringCount() < int(b.TeamSize)
+}
+
+// startAfterTeamsReady 双方队伍报名完毕：需要预览时进入预览阶段，否则直接开始对战
+func (b *Battle) startAfterTeamsReady() {
+	if b.NeedsTeamPreview() {
+		b.State = BattleStatePreview
+		b.Logs = append(b.Logs, "👀 队伍预览：双方请选择出战的宝可梦！")
+		return
+	}
+	b.State = BattleStateBattling
+	b.Logs = append(b.Logs, "⚔️ 对战开始！")
+	b.Logs = append(b.Logs, b.triggerLeadEntries()...)
+}
+
+// ToggleBring 在队伍预览中选择/取消一只出战宝可梦（按选择顺序排列，第一只为首发）
+func (b *Battle) ToggleBring(playerID string, index int) error {
+	player, err := b.previewPlayer(playerID)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(player.Roster) {
+		return errors.New("无效的宝可梦")
+	}
+
+	for i, selected := range player.BringSelection {
+		if selected == index {
+			player.BringSelection = append(player.BringSelection[:i], player.BringSelection[i+1:]...)
+			return nil
+		}
+	}
+	if len(player.BringSelection) >= b.BringCount() {
+		return fmt.Errorf("最多只能选择 %d 只宝可梦出战", b.BringCount())
+	}
+	player.BringSelection = append(player.BringSelection, index)
+	return nil
+}
+
+// ResetBring 清空队伍预览中的选择
+func (b *Battle) ResetBring(playerID string) error {
+	player, err := b.previewPlayer(playerID)
+	if err != nil {
+		return err
+	}
+	player.BringSelection = nil
+	return nil
+}
+
+// ConfirmBring 确认出战选择，双方都确认后同时公开并开始对战
+func (b *Battle) ConfirmBring(playerID string) error {
+	player, err := b.previewPlayer(playerID)
+	if err != nil {
+		return err
+	}
+	if len(player.BringSelection) != b.BringCount() {
+		return fmt.Errorf("请选择 %d 只宝可梦出战（已选 %d 只）", b.BringCount(), len(player.BringSelection))
+	}
+	player.BringConfirmed = true
+
+	if b.Player1.BringConfirmed && b.Player2.BringConfirmed {
+		b.revealTeams()
+	}
+	return nil
+}
+
+// SetBring 直接设置出战选择并确认（供 AI 使用）
+func (b *Battle) SetBring(playerID string, indices []int) error {
+	if err := b.ResetBring(playerID); err != nil {
+		return err
+	}
+	for _, index := range indices {
+		if err := b.ToggleBring(playerID, index); err != nil {
+			return err
+		}
+	}
+	return b.ConfirmBring(playerID)
+}
+
+// previewPlayer 获取处于队伍预览阶段且尚未确认的玩家
+func (b *Battle) previewPlayer(playerID string) (*BattlePlayer, error) {
+	if b.State != BattleStatePreview {
+		return nil, errors.New("当前不是队伍预览阶段")
+	}
+	player := b.GetPlayer(playerID)
+	if player == nil {
+		return nil, errors.New("你不在对战中")
+	}
+	if player.BringConfirmed {
+		return nil, errors.New("你已确认出战宝可梦，等待对手...")
+	}
+	return player, nil
+}
+
+// revealTeams 双方同时公开出战宝可梦，按选择顺序组成队伍并派出首发
+func (b *Battle) revealTeams() {
+	b.Logs = append(b.Logs, "🎬 双方选择完毕！")
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		team := make([]*Battler, 0, len(player.BringSelection))
+		for _, index := range player.BringSelection {
+			team = append(team, player.Roster[index])
+		}
+		player.Team = team
+		player.Partner = nil
+		player.setSlot(0, team[0])
+		if b.IsDoubles() && len(team) > 1 {
+			player.setSlot(1, team[1])
+		}
+
+		var leads []string
+		for _, battler := range player.ActiveBattlers() {
+			leads = append(leads, battler.Pokemon.Name)
+		}
+		b.Logs = append(b.Logs, "🔄 "+player.Username+" 派出了 "+joinStrings(leads, "、")+"！")
+	}
+	b.State = BattleStateBattling
+	b.Logs = append(b.Logs, "⚔️ 对战开始！")
+	b.Logs = append(b.Logs, b.triggerLeadEntries()...)
+}
+
+// triggerLeadEntries 对战开始时按速度顺序发动首发宝可梦的出场效果（原始回归、出场特性）
+func (b *Battle) triggerLeadEntries() []string {
+	leads := make([]*Battler, 0, 4)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		leads = append(leads, player.ActiveBattlers()...)
+	}
+	sort.SliceStable(leads, func(i, j int) bool {
+		return b.GetEffectiveSpeed(leads[i]) > b.GetEffectiveSpeed(leads[j])
+	})
+
+	logs := make([]string, 0)
+	for _, lead := range leads {
+		owner := b.GetOwner(lead)
+		if owner == nil {
+			continue
+		}
+		if opponent := b.getOpponentPokemon(owner); opponent != nil {
+			logs = append(logs, b.TriggerEntryAbility(lead, opponent)...)
+		}
+	}
+	return logs
+}
@@ -0,0 +1,323 @@
+package entity
+
+import "errors"
+
+// ============================================
+// 替补上场（宝可梦倒下后、U转等技能命中后由玩家选择替补）
+// 倒下的宝可梦留在场上直到回合结束，回合结束时双方选择替补；
+// U转等技能在回合中途暂停回合，替补上场后继续执行剩余的行动
+// ============================================
+
+// pivotMoves 命中后使用者回到队伍、换上替补的技能（按技能ID）
+var pivotMoves = map[int]bool{
+	369: true, // 急速折返
+	521: true, // 伏特替换
+	812: true, // 快速折返
+}
+
+// QueuedAction 行动队列中的技能行动
+type QueuedAction struct {
+	PlayerID  string
+	Slot      int // 行动的场上位置
+	TeamIndex int // 行动的宝可梦在队伍中的索引（被换下后不再行动）
+}
+
+// teamIndexOf 宝可梦在队伍中的索引，不在队伍中时返回 -1
+func (p *BattlePlayer) teamIndexOf(battler *Battler) int {
+	for i, member := range p.Team {
+		if member == battler {
+			return i
+		}
+	}
+	return -1
+}
+
+// slotOf 宝可梦所在的场上位置，不在场时返回 -1
+func (p *BattlePlayer) slotOf(battler *Battler) int {
+	switch {
+	case battler == nil:
+		return -1
+	case battler == p.Pokemon:
+		return 0
+	case battler == p.Partner:
+		return 1
+	}
+	return -1
+}
+
+// benchCount 可以替换上场的队员数量
+func (p *BattlePlayer) benchCount() int {
+	count := 0
+	for _, battler := range p.Team {
+		if battler.IsAlive() && !p.IsActive(battler) {
+			count++
+		}
+	}
+	return count
+}
+
+// isPendingReplacement 场上位置是否正在等待替补
+func (p *BattlePlayer) isPendingReplacement(slot int) bool {
+	for _, pending := range p.PendingReplacements {
+		if pending == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// GetReplacementSlot 获取玩家下一个需要选择替补的场上位置，不需要时返回 -1
+func (p *BattlePlayer) GetReplacementSlot() int {
+	if len(p.PendingReplacements) == 0 {
+		return -1
+	}
+	return p.PendingReplacements[0]
+}
+
+// NeedsReplacement 玩家是否需要选择替补
+func (b *Battle) NeedsReplacement(playerID string) bool {
+	player := b.GetPlayer(playerID)
+	return b.State == BattleStateReplacing && player != nil && len(player.PendingReplacements) > 0
+}
+
+// hasPendingReplacements 是否有玩家需要选择替补
+func (b *Battle) hasPendingReplacements() bool {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player != nil && len(player.PendingReplacements) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// clearPendingReplacements 清除所有等待中的替补请求（对战结束时）
+func (b *Battle) clearPendingReplacements() {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player != nil {
+			player.PendingReplacements = nil
+		}
+	}
+}
+
+// replacementPrompts 等待选择替补的提示
+func (b *Battle) replacementPrompts() []string {
+	logs := make([]string, 0)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player != nil && len(player.PendingReplacements) > 0 {
+			logs = append(logs, "⏳ 等待 "+player.Username+" 选择替补...")
+		}
+	}
+	return logs
+}
+
+// runTurnQueue 按顺序执行行动队列中的技能行动
+// U转等技能需要选择替补时暂停回合（保留剩余的行动），全部执行完毕后进入回合结束阶段
+func (b *Battle) runTurnQueue(logs []string) []string {
+	for len(b.TurnQueue) > 0 {
+		entry := b.TurnQueue[0]
+		b.TurnQueue = b.TurnQueue[1:]
+
+		// 已倒下或被换下的宝可梦不再行动
+		player := b.GetPlayer(entry.PlayerID)
+		if player == nil {
+			continue
+		}
+		battler := player.GetSlot(entry.Slot)
+		action := player.GetSlotAction(entry.Slot)
+		if battler == nil || action == nil || !battler.IsAlive() || player.teamIndexOf(battler) != entry.TeamIndex {
+			continue
+		}
+		logs = append(logs, b.executeMoveAction(player, battler, action, b.GetOpponent(player.ID))...)
+
+		logs = append(logs, b.announceFaints()...)
+		if endLogs, finished := b.checkBattleEnd(); finished {
+			logs = append(logs, endLogs...)
+			b.TurnQueue = nil
+			b.Logs = append(b.Logs, logs...)
+			b.clearActions()
+			return logs
+		}
+
+		if b.hasPendingReplacements() {
+			b.State = BattleStateReplacing
+			b.MidTurn = true
+			logs = append(logs, b.replacementPrompts()...)
+			b.Logs = append(b.Logs, logs...)
+			return logs
+		}
+	}
+	return b.finishTurn(logs)
+}
+
+// announceFaints 宣告新倒下的在场宝可梦（维持原始天气的宝可梦倒下时天气立即结束）
+func (b *Battle) announceFaints() []string {
+	logs := make([]string, 0)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for _, battler := range player.ActiveBattlers() {
+			if battler.IsAlive() || battler.Fainted {
+				continue
+			}
+			battler.Fainted = true
+			battler.EndDynamax()
+			logs = append(logs, "💀 "+battler.Pokemon.Name+" 倒下了！")
+		}
+	}
+	return append(logs, b.checkPrimalWeather()...)
+}
+
+// requestPivot U转等技能命中后，使用者回到队伍，由玩家选择替补（没有替补时不换人）
+func (b *Battle) requestPivot(player *BattlePlayer, user *Battler) {
+	slot := player.slotOf(user)
+	if slot < 0 || !user.IsAlive() || !player.HasSwitchableTeamMember() || player.isPendingReplacement(slot) {
+		return
+	}
+	if opponent := b.GetOpponent(player.ID); opponent == nil || !opponent.HasAlive() {
+		return
+	}
+	player.PendingReplacements = append(player.PendingReplacements, slot)
+}
+
+// requestReplacements 回合结束时为倒下的在场宝可梦请求替补，需要选择替补时进入等待替补状态
+func (b *Battle) requestReplacements() []string {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		b.requestReplacementsFor(player)
+	}
+	if !b.hasPendingReplacements() {
+		return nil
+	}
+	b.State = BattleStateReplacing
+	return b.replacementPrompts()
+}
+
+// requestReplacementsFor 为玩家倒下的在场宝可梦请求替补
+// 双打时替补不足则空出位置，0 号位空出时由 1 号位的宝可梦补上
+func (b *Battle) requestReplacementsFor(player *BattlePlayer) {
+	bench := player.benchCount() - len(player.PendingReplacements)
+	for slot := 0; slot < b.ActiveSlotCount(); slot++ {
+		battler := player.GetSlot(slot)
+		if battler == nil || battler.IsAlive() || player.isPendingReplacement(slot) || bench <= 0 {
+			continue
+		}
+		player.PendingReplacements = append(player.PendingReplacements, slot)
+		bench--
+	}
+
+	if !b.IsDoubles() {
+		return
+	}
+	if player.Partner != nil && !player.Partner.IsAlive() && !player.isPendingReplacement(1) {
+		player.Partner, player.PartnerAction = nil, nil
+	}
+	if player.Pokemon != nil && !player.Pokemon.IsAlive() && !player.isPendingReplacement(0) && player.Partner != nil && !player.isPendingReplacement(1) {
+		player.Pokemon, player.ActiveIndex, player.Action = player.Partner, player.PartnerIndex, player.PartnerAction
+		player.Partner, player.PartnerAction = nil, nil
+	}
+}
+
+// sendOut 将替补派到场上位置（原来的宝可梦被收回或已倒下），替补受到入场陷阱的影响后发动出场特性
+func (b *Battle) sendOut(player *BattlePlayer, slot int, next *Battler) []string {
+	logs := make([]string, 0)
+	old := player.GetSlot(slot)
+	if old != nil && old.IsAlive() {
+		// 剧毒计数在退场时重置，临时状态与能力变化清除
+		if old.Status == StatusBadPoison {
+			old.StatusTurns = 0
+		}
+		old.ClearVolatiles()
+		old.EndDynamax()
+		player.setSlot(slot, next)
+		logs = append(logs, "🔄 "+player.Username+" 收回了 "+old.Pokemon.Name+"，派出了 "+next.Pokemon.Name+"！")
+	} else {
+		player.setSlot(slot, next)
+		logs = append(logs, "🔄 "+player.Username+" 派出了 "+next.Pokemon.Name+"！")
+	}
+	logs = append(logs, b.checkPrimalWeather()...)
+
+	// 入场陷阱（因陷阱倒下时不再触发出场特性）
+	logs = append(logs, b.applyEntryHazards(player, next)...)
+	if !next.IsAlive() {
+		return logs
+	}
+
+	// 触发出场特性
+	if opponent := b.getOpponentPokemon(player); opponent != nil {
+		logs = append(logs, b.TriggerEntryAbility(next, opponent)...)
+	}
+	return logs
+}
+
+// ChooseReplacement 选择替补上场（teamIndex 为队伍中的索引）
+// 所有替补选择完毕后，回合中途的换人继续执行本回合剩余的行动
+func (b *Battle) ChooseReplacement(playerID string, teamIndex int) ([]string, error) {
+	if b.State != BattleStateReplacing {
+		return nil, errors.New("当前不需要选择替补")
+	}
+	player := b.GetPlayer(playerID)
+	if player == nil {
+		return nil, errors.New("你不在对战中")
+	}
+	slot := player.GetReplacementSlot()
+	if slot < 0 {
+		return nil, errors.New("你不需要选择替补，等待对手...")
+	}
+	if teamIndex < 0 || teamIndex >= len(player.Team) {
+		return nil, errors.New("无效的宝可梦")
+	}
+	next := player.Team[teamIndex]
+	if !next.IsAlive() {
+		return nil, errors.New("该宝可梦已经倒下")
+	}
+	if player.IsActive(next) {
+		return nil, errors.New("该宝可梦已在场上")
+	}
+
+	player.PendingReplacements = player.PendingReplacements[1:]
+	logs := b.sendOut(player, slot, next)
+
+	// 替补因入场陷阱倒下（回合中途倒下的在回合结束时再选择替补）
+	if !next.IsAlive() {
+		logs = append(logs, b.announceFaints()...)
+		if endLogs, finished := b.checkBattleEnd(); finished {
+			logs = append(logs, endLogs...)
+			b.TurnQueue = nil
+			b.Logs = append(b.Logs, logs...)
+			b.clearActions()
+			return logs, nil
+		}
+		if !b.MidTurn {
+			b.requestReplacementsFor(player)
+		}
+	}
+
+	if b.hasPendingReplacements() {
+		b.Logs = append(b.Logs, logs...)
+		return logs, nil
+	}
+
+	b.State = BattleStateBattling
+	if b.MidTurn {
+		return b.runTurnQueue(logs), nil
+	}
+	b.Logs = append(b.Logs, logs...)
+	return logs, nil
+}
+
+// Forfeit 认输（等待替补时无法选择行动，直接结束对战）
+func (b *Battle) Forfeit(playerID string) ([]string, error) {
+	player := b.GetPlayer(playerID)
+	if player == nil {
+		return nil, errors.New("你不在对战中")
+	}
+	if b.State == BattleStateFinished {
+		return nil, errors.New("对战已结束")
+	}
+	winner := b.GetOpponent(playerID)
+	b.Winner = winner
+	b.State = BattleStateFinished
+	b.TurnQueue = nil
+	b.clearPendingReplacements()
+	b.clearActions()
+	logs := []string{"🏳️ " + player.Username + " 认输了！", "🏆 " + winner.Username + " 获胜！"}
+	b.Logs = append(b.Logs, logs...)
+	return logs, nil
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 对战规则与条款（队伍检查、对战中的条款判定）
+// ============================================
+
+// MoveLearnChecker 判断宝可梦在可学技能范围内能否学会技能（由图鉴数据提供）
+type MoveLearnChecker func(pokemonID, moveID int, format valueobject.LearnsetFormat) bool
+
+// ValidateTeam 按对战配置检查队伍是否合法，不合法时返回原因
+// canLearn 不为 nil 时检查技能是否在对战的可学技能范围内
+func ValidateTeam(config *valueobject.BattleConfig, builds []*PokemonBuild, canLearn MoveLearnChecker) error {
+	if config == nil {
+		return nil
+	}
+	if config.TeamSize > 0 && len(builds) > config.TeamSize {
+		return fmt.Errorf("队伍最多只能有 %d 只宝可梦", config.TeamSize)
+	}
+
+	species := make(map[int]bool)
+	items := make(map[int]bool)
+	for _, build := range builds {
+		name := build.Pokemon.Name
+		if config.LevelCap > 0 && build.Level > config.LevelCap {
+			return fmt.Errorf("%s 的等级 Lv.%d 超过了上限 Lv.%d", name, build.Level, config.LevelCap)
+		}
+		if len(build.Moves) > 4 {
+			return fmt.Errorf("%s 最多只能携带 4 个技能", name)
+		}
+		if config.SpeciesClause {
+			if species[build.Pokemon.DexNumber()] {
+				return fmt.Errorf("种族条款：队伍中不能有重复的宝可梦（%s）", name)
+			}
+			species[build.Pokemon.DexNumber()] = true
+		}
+		if config.ItemClause && build.Item != nil {
+			if items[build.Item.ID] {
+				return fmt.Errorf("道具条款：队伍中不能有重复的道具（%s）", build.Item.Name)
+			}
+			items[build.Item.ID] = true
+		}
+		for _, move := range build.Moves {
+			if ok, reason := moveAllowedByClauses(config, move); !ok {
+				return fmt.Errorf("%s 的技能 %s 不可用：%s", name, move.Name, reason)
+			}
+			if canLearn != nil && !canLearn(build.Pokemon.ID, move.ID, config.Learnset) {
+				return fmt.Errorf("%s 在「%s」范围内无法学会 %s", name, config.Learnset.DisplayName(), move.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// moveAllowedByClauses 检查技能是否被条款禁止
+func moveAllowedByClauses(config *valueobject.BattleConfig, move *Move) (bool, string) {
+	if config == nil {
+		return true, ""
+	}
+	if config.OHKOClause && move.IsOHKO() {
+		return false, "一击必杀条款禁止使用一击必杀技能"
+	}
+	if config.EvasionClause && move.RaisesEvasion() {
+		return false, "闪避条款禁止使用提升闪避率的技能"
+	}
+	return true, ""
+}
+
+// IsMoveAllowed 检查技能在本场对战的规则下能否使用
+func (b *Battle) IsMoveAllowed(move *Move) (bool, string) {
+	return moveAllowedByClauses(b.Config, move)
+}
+
+// HeldItemTaken 检查道具条款下该道具是否已被玩家队伍中的其他宝可梦携带
+// editing 为正在重新配置的队伍成员（可为 nil），其自身携带的道具不算被占用
+func (b *Battle) HeldItemTaken(playerID string, itemID int, editing *PokemonBuild) bool {
+	if b.Config == nil || !b.Config.ItemClause || itemID <= 0 {
+		return false
+	}
+	player := b.GetPlayer(playerID)
+	if player == nil {
+		return false
+	}
+	for _, member := range player.Team {
+		if member.Build == editing {
+			continue
+		}
+		if member.Build.Item != nil && member.Build.Item.ID == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOwner 获取宝可梦所属的玩家
+func (b *Battle) GetOwner(battler *Battler) *BattlePlayer {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		for _, member := range player.Team {
+			if member == battler {
+				return player
+			}
+		}
+	}
+	return nil
+}
+
+// sleepClauseBlocks 睡眠条款：对手已有宝可梦被己方催眠时，不能再让其他宝可梦睡着
+// 自己使用睡觉等技能造成的睡眠不计入
+func (b *Battle) sleepClauseBlocks(target, source *Battler) bool {
+	if b.Config == nil || !b.Config.SleepClause || source == nil || source == target {
+		return false
+	}
+	owner := b.GetOwner(target)
+	if owner == nil || owner == b.GetOwner(source) {
+		return false
+	}
+	for _, member := range owner.Team {
+		if member != target && member.IsAlive() && member.Status == StatusSleep && member.SleepInflicted {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRuleSummary 获取规则摘要（用于对战面板显示）
+func (b *Battle) GetRuleSummary() string {
+	config := b.Config
+	if config == nil {
+		return ""
+	}
+	summary := config.Rule.DisplayName()
+	if config.LevelCap > 0 {
+		summary += fmt.Sprintf(" · Lv.%d", config.LevelCap)
+	}
+	if clauses := config.ClauseNames(); len(clauses) > 0 {
+		summary += " · " + joinStrings(clauses, "/")
+	}
+	if config.Gimmick != "" && config.Gimmick != valueobject.GimmickNone {
+		summary += " · " + config.Gimmick.DisplayName()
+	}
+	return summary
+}
@@ -0,0 +1,340 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 场地状态（隐形岩、撒菱、毒菱、反射壁、光墙、顺风）
+// ============================================
+
+// SideCondition 作用于一方场地的状态
+type SideCondition string
+
+const (
+	SideStealthRock SideCondition = "隐形岩"
+	SideSpikes      SideCondition = "撒菱"
+	SideToxicSpikes SideCondition = "毒菱"
+	SideReflect     SideCondition = "反射壁"
+	SideLightScreen SideCondition = "光墙"
+	SideTailwind    SideCondition = "顺风"
+)
+
+// sideConditionOrder 场地状态的显示顺序
+var sideConditionOrder = []SideCondition{
+	SideStealthRock, SideSpikes, SideToxicSpikes, SideReflect, SideLightScreen, SideTailwind,
+}
+
+// sideConditionMoves 设置场地状态的技能（按技能ID）
+var sideConditionMoves = map[int]SideCondition{
+	446: SideStealthRock, // 隐形岩
+	191: SideSpikes,      // 撒菱
+	390: SideToxicSpikes, // 毒菱
+	115: SideReflect,     // 反射壁
+	113: SideLightScreen, // 光墙
+	366: SideTailwind,    // 顺风
+}
+
+// 清除陷阱的技能
+const (
+	moveRapidSpin = 229 // 高速旋转：清除己方场地的陷阱
+	moveDefog     = 432 // 清除浓雾：清除双方场地的陷阱与对方的墙壁
+)
+
+// IsHazard 是否为入场陷阱（按层数叠加，持续到被清除）
+func (c SideCondition) IsHazard() bool {
+	return c == SideStealthRock || c == SideSpikes || c == SideToxicSpikes
+}
+
+// IsScreen 是否为减伤墙壁
+func (c SideCondition) IsScreen() bool {
+	return c == SideReflect || c == SideLightScreen
+}
+
+// MaxLayers 陷阱可叠加的最大层数
+func (c SideCondition) MaxLayers() int {
+	switch c {
+	case SideSpikes:
+		return 3
+	case SideToxicSpikes:
+		return 2
+	}
+	return 1
+}
+
+// Duration 持续回合（包括使用的回合，陷阱返回 0）
+func (c SideCondition) Duration() int {
+	switch c {
+	case SideReflect, SideLightScreen:
+		return 5
+	case SideTailwind:
+		return 4
+	}
+	return 0
+}
+
+// Icon 场地状态图标
+func (c SideCondition) Icon() string {
+	icons := map[SideCondition]string{
+		SideStealthRock: "🪨",
+		SideSpikes:      "📌",
+		SideToxicSpikes: "☠️",
+		SideReflect:     "🧱",
+		SideLightScreen: "✨",
+		SideTailwind:    "🍃",
+	}
+	return icons[c]
+}
+
+// GetSideCondition 获取场地状态的层数（陷阱）或剩余回合（其他），0 表示不存在
+func (p *BattlePlayer) GetSideCondition(condition SideCondition) int {
+	return p.SideConditions[condition]
+}
+
+// HasSideCondition 一方场地是否存在该状态
+func (p *BattlePlayer) HasSideCondition(condition SideCondition) bool {
+	return p.GetSideCondition(condition) > 0
+}
+
+// addSideCondition 添加场地状态（陷阱叠加一层，其他设置持续回合），已达上限时返回 false
+func (p *BattlePlayer) addSideCondition(condition SideCondition) bool {
+	if p.SideConditions == nil {
+		p.SideConditions = make(map[SideCondition]int)
+	}
+	current := p.SideConditions[condition]
+	if condition.IsHazard() {
+		if current >= condition.MaxLayers() {
+			return false
+		}
+		p.SideConditions[condition] = current + 1
+		return true
+	}
+	if current > 0 {
+		return false
+	}
+	p.SideConditions[condition] = condition.Duration()
+	return true
+}
+
+// removeSideConditions 移除满足条件的场地状态，返回被移除的状态（按显示顺序）
+func (p *BattlePlayer) removeSideConditions(match func(SideCondition) bool) []SideCondition {
+	removed := make([]SideCondition, 0)
+	for _, condition := range sideConditionOrder {
+		if p.HasSideCondition(condition) && match(condition) {
+			delete(p.SideConditions, condition)
+			removed = append(removed, condition)
+		}
+	}
+	return removed
+}
+
+// GetSideConditionSummary 场地状态摘要（如"🪨隐形岩 📌撒菱×2 🧱反射壁(3回合)"），没有时返回空字符串
+func (p *BattlePlayer) GetSideConditionSummary() string {
+	parts := make([]string, 0, len(p.SideConditions))
+	for _, condition := range sideConditionOrder {
+		value := p.GetSideCondition(condition)
+		if value <= 0 {
+			continue
+		}
+		part := condition.Icon() + string(condition)
+		if condition.IsHazard() {
+			if condition.MaxLayers() > 1 {
+				part += "×" + itoa(value)
+			}
+		} else {
+			part += "(" + itoa(value) + "回合)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// IsGrounded 是否着地（飞行属性、飘浮特性与气球使宝可梦不受地面上的效果影响）
+func (b *Battler) IsGrounded() bool {
+	if b.HasType(valueobject.TypeFlying) || b.hasAbility(valueobject.AbilityLevitate) {
+		return false
+	}
+	return !b.holdsItem(valueobject.ItemAirBalloon)
+}
+
+// executeSideConditionMove 执行设置场地状态的技能（陷阱设置在对方场地，墙壁与顺风设置在己方场地）
+func (b *Battle) executeSideConditionMove(attacker *Battler, condition SideCondition) []string {
+	owner := b.GetOwner(attacker)
+	if owner == nil {
+		return []string{"❌ 但是失败了！"}
+	}
+	side := owner
+	if condition.IsHazard() {
+		side = b.GetOpponent(owner.ID)
+	}
+	if side == nil || !side.addSideCondition(condition) {
+		return []string{"❌ 但是失败了！"}
+	}
+
+	switch condition {
+	case SideStealthRock:
+		return []string{"🪨 " + side.Username + " 的场地周围漂浮着尖锐的岩石！"}
+	case SideSpikes:
+		return []string{"📌 " + side.Username + " 的脚下散落着撒菱！"}
+	case SideToxicSpikes:
+		return []string{"☠️ " + side.Username + " 的脚下散落着毒菱！"}
+	case SideReflect:
+		return []string{"🧱 反射壁使 " + side.Username + " 一方受到的物理攻击减弱了！"}
+	case SideLightScreen:
+		return []string{"✨ 光墙使 " + side.Username + " 一方受到的特殊攻击减弱了！"}
+	case SideTailwind:
+		return []string{"🍃 " + side.Username + " 的背后吹起了顺风！"}
+	}
+	return nil
+}
+
+// applyEntryHazards 宝可梦出场时受到己方场地上陷阱的影响（厚底靴免疫，魔法防守不受伤害）
+func (b *Battle) applyEntryHazards(player *BattlePlayer, battler *Battler) []string {
+	logs := make([]string, 0)
+	if battler == nil || !battler.IsAlive() || battler.holdsItem(valueobject.ItemHeavyDutyBoots) {
+		return logs
+	}
+	name := battler.Pokemon.Name
+	takesDamage := !battler.hasAbility(valueobject.AbilityMagicGuard)
+
+	// 隐形岩：按岩石属性的克制倍率造成 1/8 最大HP的伤害
+	if player.HasSideCondition(SideStealthRock) && takesDamage {
+		effectiveness := valueobject.GetEffectiveness(valueobject.TypeRock, battler.Types)
+		if damage := int(float64(battler.MaxHP) * effectiveness / 8); damage > 0 {
+			battler.TakeDamage(damage)
+			logs = append(logs, "🪨 尖锐的岩石扎进了 "+name+" 的身体！")
+		}
+	}
+
+	if !battler.IsGrounded() {
+		return logs
+	}
+
+	// 撒菱：1/2/3 层分别造成 1/8、1/6、1/4 最大HP的伤害
+	if layers := player.GetSideCondition(SideSpikes); layers > 0 && battler.IsAlive() && takesDamage {
+		divisors := []int{8, 6, 4}
+		damage := battler.MaxHP / divisors[layers-1]
+		if damage < 1 {
+			damage = 1
+		}
+		battler.TakeDamage(damage)
+		logs = append(logs, "📌 "+name+" 受到了撒菱的伤害！")
+	}
+
+	// 毒菱：着地的毒属性宝可梦出场时吸收毒菱，否则 1 层中毒、2 层剧毒
+	if layers := player.GetSideCondition(SideToxicSpikes); layers > 0 && battler.IsAlive() {
+		if battler.HasType(valueobject.TypePoison) {
+			delete(player.SideConditions, SideToxicSpikes)
+			logs = append(logs, "☠️ "+name+" 吸收了毒菱！")
+			return logs
+		}
+		status := StatusPoison
+		if layers >= 2 {
+			status = StatusBadPoison
+		}
+		statusLogs, _ := b.ApplyStatus(battler, status, false)
+		logs = append(logs, statusLogs...)
+	}
+
+	return logs
+}
+
+// applyScreens 反射壁/光墙减弱对方的物理/特殊攻击（单打减半，双打为 2732/4096，会心一击时无效）
+func (b *Battle) applyScreens(attacker, defender *Battler, move *Move, result DamageResult) DamageResult {
+	if result.Critical || result.Damage <= 0 || move.Category == CategoryStatus {
+		return result
+	}
+	side := b.GetOwner(defender)
+	if side == nil || side == b.GetOwner(attacker) {
+		return result
+	}
+	screen := SideReflect
+	if move.Category == CategorySpecial {
+		screen = SideLightScreen
+	}
+	if !side.HasSideCondition(screen) {
+		return result
+	}
+
+	modifier := 0.5
+	if b.IsDoubles() {
+		modifier = 2732.0 / 4096.0
+	}
+	result.Damage = int(float64(result.Damage) * modifier)
+	if result.Damage < 1 {
+		result.Damage = 1
+	}
+	return result
+}
+
+// applyHazardRemoval 清除陷阱的技能效果（高速旋转命中后、清除浓雾使用时）
+func (b *Battle) applyHazardRemoval(attacker *Battler, move *Move) []string {
+	logs := make([]string, 0)
+	owner := b.GetOwner(attacker)
+	if owner == nil {
+		return logs
+	}
+	isHazard := func(c SideCondition) bool { return c.IsHazard() }
+
+	switch move.ID {
+	case moveRapidSpin:
+		if !attacker.IsAlive() {
+			return logs
+		}
+		if removed := owner.removeSideConditions(isHazard); len(removed) > 0 {
+			logs = append(logs, "🌀 "+attacker.Pokemon.Name+" 清除了 "+joinSideConditions(removed)+"！")
+		}
+		if attacker.HasVolatileStatus(VolatileLeechSeed) {
+			attacker.RemoveVolatileStatus(VolatileLeechSeed)
+			logs = append(logs, "🌀 "+attacker.Pokemon.Name+" 摆脱了寄生种子！")
+		}
+	case moveDefog:
+		removed := owner.removeSideConditions(isHazard)
+		if opponent := b.GetOpponent(owner.ID); opponent != nil {
+			removed = append(removed, opponent.removeSideConditions(func(c SideCondition) bool {
+				return c.IsHazard() || c.IsScreen()
+			})...)
+		}
+		if len(removed) > 0 {
+			logs = append(logs, "🌫️ "+attacker.Pokemon.Name+" 吹散了 "+joinSideConditions(removed)+"！")
+		}
+	}
+	return logs
+}
+
+// tickSideConditions 回合结束时减少墙壁与顺风的剩余回合
+func (b *Battle) tickSideConditions() []string {
+	logs := make([]string, 0)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		for _, condition := range sideConditionOrder {
+			if condition.IsHazard() || !player.HasSideCondition(condition) {
+				continue
+			}
+			player.SideConditions[condition]--
+			if player.SideConditions[condition] > 0 {
+				continue
+			}
+			delete(player.SideConditions, condition)
+			if condition == SideTailwind {
+				logs = append(logs, "🍃 "+player.Username+" 的顺风停止了。")
+			} else {
+				logs = append(logs, condition.Icon()+" "+player.Username+" 一方的"+string(condition)+"消失了。")
+			}
+		}
+	}
+	return logs
+}
+
+// joinSideConditions 连接场地状态名称（如"隐形岩、撒菱"）
+func joinSideConditions(conditions []SideCondition) string {
+	names := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		names = append(names, string(condition))
+	}
+	return strings.Join(names, "、")
+}
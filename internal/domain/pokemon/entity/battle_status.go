@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 异常状态系统（施加检查、行动前判定、回合结束伤害）
+// ============================================
+
+// statusTypeImmunities 属性对异常状态的免疫
+var statusTypeImmunities = map[StatusCondition][]valueobject.PokeType{
+	StatusBurn:      {valueobject.TypeFire},
+	StatusParalyze:  {valueobject.TypeElectric},
+	StatusFreeze:    {valueobject.TypeIce},
+	StatusPoison:    {valueobject.TypePoison, valueobject.TypeSteel},
+	StatusBadPoison: {valueobject.TypePoison, valueobject.TypeSteel},
+}
+
+// CanApplyStatus 检查能否对目标施加主要异常状态
+// 依次检查：已有异常状态、属性免疫、天气、场地、特性免疫；不能施加时返回原因
+func (b *Battle) CanApplyStatus(target *Battler, status StatusCondition) (bool, string) {
+	if target == nil || !target.IsAlive() {
+		return false, ""
+	}
+	if target.Status != StatusNone {
+		return false, "❌ " + target.Pokemon.Name + " 已经处于" + string(target.Status) + "状态了！"
+	}
+
+	for _, t := range statusTypeImmunities[status] {
+		if target.HasType(t) {
+			return false, "🛡️ " + target.Pokemon.Name + " 不会陷入" + string(status) + "状态！"
+		}
+	}
+
+	if status == StatusFreeze && b.Weather.IsSunny() {
+		return false, ""
+	}
+
+	if reason, blocked := b.terrainStatusBlock(target, status); blocked {
+		return false, reason
+	}
+
+	if b.AbilityService != nil {
+		result := b.AbilityService.CheckStatusImmunity(target, string(status), b.GetBattleContext())
+		if result != nil && result.Immune {
+			return false, result.Message
+		}
+	}
+
+	return true, ""
+}
+
+// ApplyStatus 对目标施加主要异常状态
+// announce 为 true 时输出失败原因（用于变化技能），追加效果失败时静默
+func (b *Battle) ApplyStatus(target *Battler, status StatusCondition, announce bool) ([]string, bool) {
+	logs := make([]string, 0)
+	ok, reason := b.CanApplyStatus(target, status)
+	if !ok {
+		if announce && reason != "" {
+			logs = append(logs, reason)
+		}
+		return logs, false
+	}
+
+	target.Status = status
+	target.StatusTurns = 0
+	target.SleepInflicted = false
+	if status == StatusSleep {
+		// 睡眠持续 1-3 回合
+		target.StatusTurns = 1 + randInt(3)
+	}
+	logs = append(logs, getStatusEmoji(string(status))+" "+target.Pokemon.Name+" 陷入了"+string(status)+"状态！")
+	return logs, true
+}
+
+// CureStatus 治愈主要异常状态
+func (b *Battle) CureStatus(target *Battler) {
+	target.Status = StatusNone
+	target.StatusTurns = 0
+	target.SleepInflicted = false
+}
+
+// checkStatusBeforeMove 行动前的异常状态判定（睡眠、冰冻、麻痹）
+// 返回日志以及本回合能否行动
+func (b *Battle) checkStatusBeforeMove(battler *Battler) ([]string, bool) {
+	logs := make([]string, 0)
+	name := battler.Pokemon.Name
+
+	switch battler.Status {
+	case StatusSleep:
+		if battler.StatusTurns <= 0 {
+			b.CureStatus(battler)
+			logs = append(logs, "🌅 "+name+" 醒过来了！")
+			return logs, true
+		}
+		battler.StatusTurns--
+		logs = append(logs, "💤 "+name+" 正在呼呼大睡...")
+		return logs, false
+
+	case StatusFreeze:
+		if randInt(100) < 20 {
+			b.CureStatus(battler)
+			logs = append(logs, "🔥 "+name+" 的冰融化了！")
+			return logs, true
+		}
+		logs = append(logs, "🧊 "+name+" 被冻住了，无法行动！")
+		return logs, false
+
+	case StatusParalyze:
+		if randInt(100) < 25 {
+			logs = append(logs, "⚡ "+name+" 身体麻痹，无法行动！")
+			return logs, false
+		}
+	}
+
+	return logs, true
+}
+
+// processStatusResidual 回合结束时的异常状态伤害（中毒 1/8、剧毒 n/16、灼伤 1/16）
+// negatePoison 为 true 时跳过中毒伤害（如毒疗）
+func (b *Battle) processStatusResidual(battler *Battler, negatePoison bool) []string {
+	logs := make([]string, 0)
+	if battler == nil || !battler.IsAlive() {
+		return logs
+	}
+	name := battler.Pokemon.Name
+
+	var damage int
+	switch battler.Status {
+	case StatusPoison:
+		if negatePoison {
+			return logs
+		}
+		damage = battler.MaxHP / 8
+		logs = append(logs, "☠️ "+name+" 受到了毒的伤害！")
+	case StatusBadPoison:
+		// 剧毒计数在毒疗下也会累积
+		battler.StatusTurns++
+		if negatePoison {
+			return logs
+		}
+		damage = battler.MaxHP * battler.StatusTurns / 16
+		logs = append(logs, "☠️ "+name+" 受到了剧毒的伤害！")
+	case StatusBurn:
+		damage = battler.MaxHP / 16
+		logs = append(logs, "🔥 "+name+" 受到了灼伤的伤害！")
+	default:
+		return logs
+	}
+
+	if damage < 1 {
+		damage = 1
+	}
+	battler.TakeDamage(damage)
+	return logs
+}
@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 场地系统（电气场地、青草场地、精神场地、薄雾场地）
+// 场地只影响着地的宝可梦（飞行属性、飘浮特性、气球除外）
+// ============================================
+
+// terrainMoves 展开场地的技能（按技能ID）
+var terrainMoves = map[int]valueobject.Terrain{
+	604: valueobject.TerrainElectric, // 电气场地
+	580: valueobject.TerrainGrassy,   // 青草场地
+	678: valueobject.TerrainPsychic,  // 精神场地
+	581: valueobject.TerrainMisty,    // 薄雾场地
+}
+
+// grassyWeakenedMoves 青草场地中威力减半的技能（按技能ID，目标着地时）
+var grassyWeakenedMoves = map[int]bool{
+	89:  true, // 地震
+	222: true, // 震级
+	523: true, // 重踏
+}
+
+// SetTerrain 展开场地（持续 5 回合），场地未改变时返回 false
+func (b *Battle) SetTerrain(terrain valueobject.Terrain) bool {
+	if terrain == valueobject.TerrainNone || b.Terrain == terrain {
+		return false
+	}
+	b.Terrain = terrain
+	b.TerrainTurns = valueobject.TerrainTurns
+	return true
+}
+
+// GetTerrainSummary 场地摘要（如"⚡电气场地(3回合)"），没有场地时返回空字符串
+func (b *Battle) GetTerrainSummary() string {
+	if b.Terrain == valueobject.TerrainNone {
+		return ""
+	}
+	return b.Terrain.Icon() + string(b.Terrain) + "(" + itoa(b.TerrainTurns) + "回合)"
+}
+
+// executeTerrainMove 执行展开场地的技能
+func (b *Battle) executeTerrainMove(terrain valueobject.Terrain) []string {
+	if !b.SetTerrain(terrain) {
+		return []string{"❌ 但是失败了！"}
+	}
+	return []string{terrain.StartMessage()}
+}
+
+// applyTerrainPower 场地对技能威力的修正
+// 电气/青草/精神场地强化着地的宝可梦使用的对应属性招式；薄雾场地减弱对着地目标的龙属性招式；
+// 青草场地减弱对着地目标的地震、震级、重踏
+func (b *Battle) applyTerrainPower(attacker, defender *Battler, move *Move, mod *ability.DamageModifier) *ability.DamageModifier {
+	if b.Terrain == valueobject.TerrainNone || move.Category == CategoryStatus {
+		return mod
+	}
+
+	modifier := 1.0
+	if boosted, ok := b.Terrain.BoostedType(); ok && move.Type == boosted && attacker.IsGrounded() {
+		modifier *= valueobject.TerrainPowerModifier
+	}
+	if defender.IsGrounded() {
+		if b.Terrain == valueobject.TerrainMisty && move.Type == valueobject.TypeDragon {
+			modifier *= 0.5
+		}
+		if b.Terrain == valueobject.TerrainGrassy && grassyWeakenedMoves[move.ID] {
+			modifier *= 0.5
+		}
+	}
+	if modifier == 1.0 {
+		return mod
+	}
+
+	if mod == nil {
+		mod = ability.NewDamageModifier()
+	}
+	mod.PowerMod *= modifier
+	return mod
+}
+
+// terrainStatusBlock 场地阻止着地的宝可梦陷入异常状态（电气场地防止睡眠，薄雾场地防止所有异常状态），返回提示
+func (b *Battle) terrainStatusBlock(target *Battler, status StatusCondition) (string, bool) {
+	if !target.IsGrounded() {
+		return "", false
+	}
+	switch {
+	case b.Terrain == valueobject.TerrainElectric && status == StatusSleep:
+		return "⚡ " + target.Pokemon.Name + " 受到了电气场地的保护！", true
+	case b.Terrain == valueobject.TerrainMisty:
+		return "🌫️ " + target.Pokemon.Name + " 受到了薄雾场地的保护！", true
+	}
+	return "", false
+}
+
+// terrainBlocksPriority 精神场地中，着地的宝可梦不会受到对手先制技能的影响
+func (b *Battle) terrainBlocksPriority(attacker, defender *Battler, move *Move) bool {
+	if b.Terrain != valueobject.TerrainPsychic || attacker == defender || !defender.IsGrounded() {
+		return false
+	}
+	if owner := b.GetOwner(defender); owner == nil || owner == b.GetOwner(attacker) {
+		return false
+	}
+	return b.effectivePriority(attacker, move) > 0
+}
+
+// processTerrainHealing 青草场地在回合结束时为着地的宝可梦回复 1/16 最大HP
+func (b *Battle) processTerrainHealing(battler *Battler) []string {
+	if b.Terrain != valueobject.TerrainGrassy || !battler.IsGrounded() || battler.CurrentHP >= battler.MaxHP {
+		return nil
+	}
+	amount := battler.MaxHP / 16
+	if amount < 1 {
+		amount = 1
+	}
+	healed := battler.Heal(amount)
+	return []string{"🌿 " + battler.Pokemon.Name + " 受到青草场地的滋润，回复了 " + itoa(healed) + " HP！"}
+}
+
+// tickTerrain 回合结束时减少场地的剩余回合
+func (b *Battle) tickTerrain() []string {
+	if b.Terrain == valueobject.TerrainNone {
+		return nil
+	}
+	b.TerrainTurns--
+	if b.TerrainTurns > 0 {
+		return nil
+	}
+	message := b.Terrain.EndMessage()
+	b.Terrain = valueobject.TerrainNone
+	b.TerrainTurns = 0
+	return []string{message}
+}
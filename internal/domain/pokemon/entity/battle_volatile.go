@@ -0,0 +1,359 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 临时状态系统（混乱、着迷、挑衅、寄生种子、替身等）
+// ============================================
+
+// volatileMoveEffects 没有 move_meta 状态数据的临时状态技能（按技能ID）
+var volatileMoveEffects = map[int]VolatileStatus{
+	116: VolatileFocusEnergy, // 聚气
+	164: VolatileSubstitute,  // 替身
+	227: VolatileEncore,      // 再来一次
+	269: VolatileTaunt,       // 挑衅
+}
+
+// battleVolatiles 退场时需要清除的临时状态
+var battleVolatiles = map[VolatileStatus]bool{
+	VolatileConfusion:   true,
+	VolatileAttraction:  true,
+	VolatileTaunt:       true,
+	VolatileTorment:     true,
+	VolatileDisable:     true,
+	VolatileEncore:      true,
+	VolatileLeechSeed:   true,
+	VolatileSubstitute:  true,
+	VolatileFocusEnergy: true,
+}
+
+// turnEndVolatiles 回合结束时递减持续回合的临时状态（混乱在行动时递减）
+var turnEndVolatiles = []VolatileStatus{VolatileTaunt, VolatileEncore, VolatileDisable}
+
+// volatileDuration 临时状态持续回合（0 表示持续到退场或被解除）
+func volatileDuration(status VolatileStatus) int {
+	switch status {
+	case VolatileConfusion:
+		return 2 + randInt(4) // 2-5 回合
+	case VolatileTaunt:
+		return 3
+	case VolatileDisable:
+		return 4
+	case VolatileEncore:
+		return 3
+	}
+	return 0
+}
+
+// HasVolatileStatus 检查是否有临时状态
+func (b *Battler) HasVolatileStatus(status VolatileStatus) bool {
+	return b.HasVolatile(string(status))
+}
+
+// RemoveVolatileStatus 移除临时状态及其附加数据
+func (b *Battler) RemoveVolatileStatus(status VolatileStatus) {
+	b.RemoveVolatile(string(status))
+	delete(b.VolatileTurns, status)
+	switch status {
+	case VolatileAttraction:
+		b.AttractedTo = nil
+	case VolatileDisable:
+		b.DisabledMove = nil
+	case VolatileEncore:
+		b.EncoreMove = nil
+	case VolatileSubstitute:
+		b.SubstituteHP = 0
+	case VolatileLeechSeed:
+		b.LeechSeedBy, b.LeechSeedSlot = "", 0
+	}
+}
+
+// ClearVolatiles 退场时清除临时状态与能力等级变化
+func (b *Battler) ClearVolatiles() {
+	kept := make([]VolatileStatus, 0, len(b.Volatile))
+	for _, v := range b.Volatile {
+		if !battleVolatiles[v] {
+			kept = append(kept, v)
+		}
+	}
+	b.Volatile = kept
+	b.VolatileTurns = make(map[VolatileStatus]int)
+	b.AttractedTo = nil
+	b.DisabledMove = nil
+	b.EncoreMove = nil
+	b.SubstituteHP = 0
+	b.LeechSeedBy, b.LeechSeedSlot = "", 0
+	b.StatStages = StatStages{}
+	b.LastMove = nil
+	b.LastMoveTurns = 0
+	b.ChoiceLock = nil
+	b.ProtectCount = 0
+	b.Flinched = false
+}
+
+// CanSelectMove 检查临时状态是否允许选择该技能（挑衅、定身法、无理取闹、再来一次）
+func (b *Battler) CanSelectMove(move *Move) (bool, string) {
+	if b.HasVolatileStatus(VolatileEncore) && b.EncoreMove != nil && move != b.EncoreMove {
+		return false, "再来一次状态下只能使用 " + b.EncoreMove.Name
+	}
+	if b.HasVolatileStatus(VolatileTaunt) && move.Category == CategoryStatus {
+		return false, "挑衅状态下无法使用变化技能"
+	}
+	if b.HasVolatileStatus(VolatileDisable) && move == b.DisabledMove {
+		return false, move.Name + " 被定身法封住了"
+	}
+	if b.HasVolatileStatus(VolatileTorment) && move == b.LastMove {
+		return false, "无理取闹状态下不能连续使用同一技能"
+	}
+	return true, ""
+}
+
+// ApplyVolatile 对目标施加临时状态
+// source 为施加者（用于着迷、替身等判定），announce 为 true 时输出失败原因
+func (b *Battle) ApplyVolatile(target, source *Battler, status VolatileStatus, announce bool) ([]string, bool) {
+	logs := make([]string, 0)
+	if target == nil || !target.IsAlive() {
+		return logs, false
+	}
+	name := target.Pokemon.Name
+
+	fail := func(reason string) ([]string, bool) {
+		if announce {
+			logs = append(logs, reason)
+		}
+		return logs, false
+	}
+
+	if target.HasVolatileStatus(status) {
+		return fail("❌ " + name + " 已经处于" + string(status) + "状态了！")
+	}
+
+	// 各状态的施加条件
+	switch status {
+	case VolatileConfusion:
+		if b.Terrain == valueobject.TerrainMisty && target.IsGrounded() {
+			return fail("🌫️ " + name + " 受到了薄雾场地的保护！")
+		}
+	case VolatileLeechSeed:
+		if target.HasType(valueobject.TypeGrass) {
+			return fail("🛡️ " + name + " 不会被种下种子！")
+		}
+	case VolatileAttraction:
+		if source == nil || !isOppositeGender(target.Build.Gender, source.Build.Gender) {
+			return fail("❌ 但是失败了！")
+		}
+	case VolatileEncore, VolatileDisable:
+		if target.LastMove == nil || !target.LastMove.CanUse() {
+			return fail("❌ 但是失败了！")
+		}
+	case VolatileSubstitute:
+		cost := target.MaxHP / 4
+		if cost < 1 || target.CurrentHP <= cost {
+			return fail("❌ " + name + " 的体力不够制造替身了！")
+		}
+	}
+
+	if b.AbilityService != nil {
+		result := b.AbilityService.CheckStatusImmunity(target, string(status), b.GetBattleContext())
+		if result != nil && result.Immune {
+			return fail(result.Message)
+		}
+	}
+
+	target.AddVolatile(string(status))
+	if turns := volatileDuration(status); turns > 0 {
+		if target.VolatileTurns == nil {
+			target.VolatileTurns = make(map[VolatileStatus]int)
+		}
+		// 施加的回合结束时也会递减，多计一回合
+		for _, ticked := range turnEndVolatiles {
+			if status == ticked {
+				turns++
+			}
+		}
+		target.VolatileTurns[status] = turns
+	}
+
+	switch status {
+	case VolatileConfusion:
+		logs = append(logs, "💫 "+name+" 混乱了！")
+	case VolatileAttraction:
+		target.AttractedTo = source
+		logs = append(logs, "💕 "+name+" 着迷了！")
+	case VolatileTaunt:
+		logs = append(logs, "😤 "+name+" 中了挑衅！")
+	case VolatileTorment:
+		logs = append(logs, "😠 "+name+" 被无理取闹了！")
+	case VolatileDisable:
+		target.DisabledMove = target.LastMove
+		logs = append(logs, "🚫 "+name+" 的 "+target.LastMove.Name+" 被封住了！")
+	case VolatileEncore:
+		target.EncoreMove = target.LastMove
+		logs = append(logs, "🔁 "+name+" 受到了再来一次！")
+	case VolatileLeechSeed:
+		if owner := b.GetOwner(source); owner != nil {
+			target.LeechSeedBy, target.LeechSeedSlot = owner.ID, owner.slotOf(source)
+		}
+		logs = append(logs, "🌱 "+name+" 被种下了种子！")
+	case VolatileSubstitute:
+		cost := target.MaxHP / 4
+		target.TakeDamage(cost)
+		target.SubstituteHP = cost
+		logs = append(logs, "🧸 "+name+" 制造了替身！")
+	case VolatileFocusEnergy:
+		logs = append(logs, "🔥 "+name+" 变得跃跃欲试了！")
+	default:
+		logs = append(logs, "💫 "+name+" 陷入了"+string(status)+"状态！")
+	}
+	return logs, true
+}
+
+// checkVolatileBeforeMove 行动前的临时状态判定
+// opponents 为对手在场的宝可梦；返回日志、实际使用的技能（再来一次可能替换）以及能否行动
+func (b *Battle) checkVolatileBeforeMove(battler *Battler, opponents []*Battler, move *Move) ([]string, *Move, bool) {
+	logs := make([]string, 0)
+	name := battler.Pokemon.Name
+
+	// 再来一次：强制使用被锁定的技能
+	if battler.HasVolatileStatus(VolatileEncore) {
+		if battler.EncoreMove == nil || !battler.EncoreMove.CanUse() {
+			battler.RemoveVolatileStatus(VolatileEncore)
+			logs = append(logs, "🔁 "+name+" 的再来一次状态解除了！")
+		} else {
+			move = battler.EncoreMove
+		}
+	}
+
+	if ok, reason := battler.CanSelectMove(move); !ok {
+		logs = append(logs, "🚫 "+name+" 无法使用 "+move.Name+"！（"+reason+"）")
+		return logs, move, false
+	}
+
+	// 混乱：回合数在行动时消耗，1/3 几率攻击自己
+	if battler.HasVolatileStatus(VolatileConfusion) {
+		battler.VolatileTurns[VolatileConfusion]--
+		if battler.VolatileTurns[VolatileConfusion] <= 0 {
+			battler.RemoveVolatileStatus(VolatileConfusion)
+			logs = append(logs, "😌 "+name+" 的混乱解除了！")
+		} else {
+			logs = append(logs, "💫 "+name+" 正在混乱中！")
+			if randInt(3) == 0 {
+				damage := battler.confusionDamage()
+				battler.TakeDamage(damage)
+				logs = append(logs, "💥 "+name+" 不知所以地攻击了自己！受到了 "+itoa(damage)+" 点伤害！")
+				return logs, move, false
+			}
+		}
+	}
+
+	// 着迷：对象不在场时解除，否则 50% 无法行动
+	if battler.HasVolatileStatus(VolatileAttraction) {
+		if !isOnField(battler.AttractedTo, opponents) {
+			battler.RemoveVolatileStatus(VolatileAttraction)
+		} else {
+			logs = append(logs, "💕 "+name+" 对 "+battler.AttractedTo.Pokemon.Name+" 着迷了！")
+			if randInt(2) == 0 {
+				logs = append(logs, "💕 "+name+" 因着迷而无法行动！")
+				return logs, move, false
+			}
+		}
+	}
+
+	return logs, move, true
+}
+
+// confusionDamage 混乱时攻击自己的伤害（威力40的无属性物理攻击）
+func (b *Battler) confusionDamage() int {
+	atk := b.GetEffectiveAtk()
+	def := b.GetEffectiveDef()
+	if def < 1 {
+		def = 1
+	}
+	damage := ((2*b.Level/5+2)*40*atk/def)/50 + 2
+	damage = damage * (randInt(16) + 85) / 100
+	if damage < 1 {
+		damage = 1
+	}
+	return damage
+}
+
+// damageSubstitute 替身代替承受伤害，返回替身承受的伤害与日志
+func (b *Battle) damageSubstitute(target *Battler, damage int) (int, []string) {
+	logs := make([]string, 0)
+	if damage > target.SubstituteHP {
+		damage = target.SubstituteHP
+	}
+	target.SubstituteHP -= damage
+	logs = append(logs, "🧸 替身代替 "+target.Pokemon.Name+" 承受了攻击！")
+	if target.SubstituteHP <= 0 {
+		target.RemoveVolatileStatus(VolatileSubstitute)
+		logs = append(logs, "💨 "+target.Pokemon.Name+" 的替身消失了！")
+	}
+	return damage, logs
+}
+
+// processVolatileTurnEnd 回合结束时的临时状态处理（寄生种子、持续回合递减）
+func (b *Battle) processVolatileTurnEnd(battler *Battler) []string {
+	logs := make([]string, 0)
+	if battler == nil || !battler.IsAlive() {
+		return logs
+	}
+	name := battler.Pokemon.Name
+
+	// 寄生种子：吸取 1/8 最大HP 回复给种下种子的位置上的宝可梦（原宝可梦被换下时回复给换上的宝可梦）
+	if battler.HasVolatileStatus(VolatileLeechSeed) {
+		drain := battler.MaxHP / 8
+		if drain < 1 {
+			drain = 1
+		}
+		drained := battler.TakeDamage(drain)
+		logs = append(logs, "🌱 寄生种子吸取了 "+name+" 的HP！")
+		if seeder := b.GetPlayer(battler.LeechSeedBy); seeder != nil {
+			if healer := seeder.GetSlot(battler.LeechSeedSlot); healer != nil && healer.IsAlive() {
+				healer.Heal(drained)
+			}
+		}
+	}
+
+	// 持续回合递减（混乱在行动时递减）
+	for _, status := range turnEndVolatiles {
+		if !battler.HasVolatileStatus(status) {
+			continue
+		}
+		battler.VolatileTurns[status]--
+		if battler.VolatileTurns[status] > 0 {
+			continue
+		}
+		battler.RemoveVolatileStatus(status)
+		switch status {
+		case VolatileTaunt:
+			logs = append(logs, "😌 "+name+" 的挑衅效果解除了！")
+		case VolatileEncore:
+			logs = append(logs, "🔁 "+name+" 的再来一次状态解除了！")
+		case VolatileDisable:
+			logs = append(logs, "✅ "+name+" 的定身法解除了！")
+		}
+	}
+
+	return logs
+}
+
+// isOnField 宝可梦是否存活且在场
+func isOnField(target *Battler, active []*Battler) bool {
+	if target == nil || !target.IsAlive() {
+		return false
+	}
+	for _, battler := range active {
+		if battler == target {
+			return true
+		}
+	}
+	return false
+}
+
+// isOppositeGender 是否为异性（无性别时不会着迷）
+func isOppositeGender(a, b Gender) bool {
+	return (a == GenderMale && b == GenderFemale) || (a == GenderFemale && b == GenderMale)
+}
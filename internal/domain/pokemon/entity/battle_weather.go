@@ -0,0 +1,231 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 天气系统（晴天、雨天、沙暴、冰雹、下雪与原始天气）
+// 原始天气（大日照、大雨、乱流）不会自然结束，只能被其他原始天气覆盖，
+// 维持天气的宝可梦全部离场后结束
+// ============================================
+
+// weatherMoves 改变天气的技能（按技能ID）
+var weatherMoves = map[int]valueobject.Weather{
+	241: valueobject.WeatherSun,  // 大晴天
+	240: valueobject.WeatherRain, // 求雨
+	201: valueobject.WeatherSand, // 沙暴
+	258: valueobject.WeatherHail, // 冰雹
+	883: valueobject.WeatherSnow, // 雪景
+}
+
+// weatherRocks 使对应天气持续 8 回合的天气岩石
+var weatherRocks = map[valueobject.Weather]valueobject.Item{
+	valueobject.WeatherSun:  valueobject.ItemHeatRock,
+	valueobject.WeatherRain: valueobject.ItemDampRock,
+	valueobject.WeatherSand: valueobject.ItemSmoothRock,
+	valueobject.WeatherHail: valueobject.ItemIcyRock,
+	valueobject.WeatherSnow: valueobject.ItemIcyRock,
+}
+
+// primalWeatherAbilities 维持原始天气的特性
+var primalWeatherAbilities = map[valueobject.Weather]valueobject.Ability{
+	valueobject.WeatherHarshSun:    valueobject.AbilityDesolateLand,
+	valueobject.WeatherHeavyRain:   valueobject.AbilityPrimordialSea,
+	valueobject.WeatherStrongWinds: valueobject.AbilityDeltaStream,
+}
+
+// SetWeather 改变天气，天气未改变时返回 false
+// setter 为引发天气的宝可梦，携带对应的天气岩石时持续 8 回合，否则持续 5 回合
+func (b *Battle) SetWeather(weather valueobject.Weather, setter *Battler) bool {
+	if weather == valueobject.WeatherNone {
+		return false
+	}
+	turns := valueobject.WeatherTurns
+	if weather.IsPrimal() {
+		turns = 0
+	} else if rock, ok := weatherRocks[weather]; ok && setter != nil && setter.holdsItem(rock) {
+		turns = valueobject.ExtendedWeatherTurns
+	}
+	return b.Weather.SetWeather(weather, turns)
+}
+
+// GetWeatherSummary 天气摘要（如"☀️晴天(3回合)"，原始天气不显示回合），没有天气时返回空字符串
+func (b *Battle) GetWeatherSummary() string {
+	current := b.Weather.Current
+	if current == valueobject.WeatherNone {
+		return ""
+	}
+	if b.Weather.TurnsLeft <= 0 {
+		return current.Icon() + string(current)
+	}
+	return current.Icon() + string(current) + "(" + itoa(b.Weather.TurnsLeft) + "回合)"
+}
+
+// executeWeatherMove 执行改变天气的技能
+func (b *Battle) executeWeatherMove(attacker *Battler, weather valueobject.Weather) []string {
+	if !b.SetWeather(weather, attacker) {
+		return []string{"❌ 但是失败了！"}
+	}
+	return []string{weather.StartMessage()}
+}
+
+// weatherNullifiesMove 大雨中火属性、大日照中水属性的攻击招式失效，返回提示
+func (b *Battle) weatherNullifiesMove(move *Move) (string, bool) {
+	if move.Category == CategoryStatus {
+		return "", false
+	}
+	switch {
+	case b.Weather.Current == valueobject.WeatherHeavyRain && move.Type == valueobject.TypeFire:
+		return "⛈️ 火属性的攻击在大雨中消失了！", true
+	case b.Weather.Current == valueobject.WeatherHarshSun && move.Type == valueobject.TypeWater:
+		return "🔥 水属性的攻击在强烈的日照中蒸发了！", true
+	}
+	return "", false
+}
+
+// applyWeatherModifiers 天气对伤害的修正
+// 晴天强化火属性、减弱水属性招式，雨天相反；沙暴中岩石属性的特防、下雪中冰属性的防御提升
+func (b *Battle) applyWeatherModifiers(defender *Battler, move *Move, mod *ability.DamageModifier) *ability.DamageModifier {
+	if !b.Weather.IsActive() || move.Category == CategoryStatus {
+		return mod
+	}
+	damageMod := b.Weather.GetDamageModifier(move.Type)
+	defenseMod := b.Weather.GetDefenseModifier(defender.Types, move.Category == CategoryPhysical)
+	if damageMod == 1.0 && defenseMod == 1.0 {
+		return mod
+	}
+
+	if mod == nil {
+		mod = ability.NewDamageModifier()
+	}
+	mod.DamageMod *= damageMod
+	mod.DefenseMod *= defenseMod
+	return mod
+}
+
+// strongWindsWeaken 乱流是否减弱了对飞行属性效果拔群的招式
+func (b *Battle) strongWindsWeaken(defender *Battler, move *Move) bool {
+	if b.Weather.Current != valueobject.WeatherStrongWinds || move.Category == CategoryStatus {
+		return false
+	}
+	return defender.HasType(valueobject.TypeFlying) &&
+		valueobject.GetEffectiveness(move.Type, []valueobject.PokeType{valueobject.TypeFlying}) > 1
+}
+
+// applyStrongWinds 乱流中，对飞行属性效果拔群的招式克制倍率减半
+func (b *Battle) applyStrongWinds(defender *Battler, move *Move, result DamageResult) DamageResult {
+	if result.Damage <= 0 || !b.strongWindsWeaken(defender, move) {
+		return result
+	}
+	result.Effectiveness /= 2
+	result.Damage /= 2
+	if result.Damage < 1 {
+		result.Damage = 1
+	}
+	return result
+}
+
+// processWeatherEffects 回合结束时沙暴与冰雹对在场宝可梦造成 1/16 最大HP的伤害（对应属性免疫）
+func (b *Battle) processWeatherEffects() []string {
+	logs := make([]string, 0)
+	if !b.Weather.CausesWeatherDamage() {
+		return logs
+	}
+
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		for _, battler := range player.ActiveBattlers() {
+			if battler == nil || !battler.IsAlive() || b.immuneToWeather(battler) {
+				continue
+			}
+			damage := battler.MaxHP / 16
+			if damage < 1 {
+				damage = 1
+			}
+			battler.TakeDamage(damage)
+			if b.Weather.IsSandy() {
+				logs = append(logs, "🏜️ "+battler.Pokemon.Name+" 受到了沙暴伤害！")
+			} else {
+				logs = append(logs, "🌨️ "+battler.Pokemon.Name+" 受到了冰雹伤害！")
+			}
+		}
+	}
+	return logs
+}
+
+// immuneToWeather 宝可梦是否免疫天气伤害
+func (b *Battle) immuneToWeather(battler *Battler) bool {
+	for _, t := range b.Weather.GetWeatherDamageExemptTypes() {
+		if battler.HasType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// tickWeather 回合结束时减少天气的剩余回合（原始天气不会自然结束）
+func (b *Battle) tickWeather() []string {
+	current := b.Weather.Current
+	if !b.Weather.Tick() {
+		return nil
+	}
+	return []string{current.EndMessage()}
+}
+
+// checkPrimalWeather 场上没有维持原始天气的宝可梦时（换下或倒下），原始天气结束
+func (b *Battle) checkPrimalWeather() []string {
+	holder, ok := primalWeatherAbilities[b.Weather.Current]
+	if !ok {
+		return nil
+	}
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player == nil {
+			continue
+		}
+		for _, battler := range player.ActiveBattlers() {
+			if battler.IsAlive() && battler.hasAbility(holder) {
+				return nil
+			}
+		}
+	}
+	current := b.Weather.Current
+	b.Weather.Clear()
+	return []string{current.EndMessage()}
+}
+
+// PrimalForm 携带的宝珠对应的原始回归形态，没有时返回 nil
+func (b *Battler) PrimalForm() *MegaForm {
+	if b.IsPrimal || b.Item == nil || b.ItemConsumed {
+		return nil
+	}
+	for _, form := range b.Pokemon.MegaForms {
+		if form.Primal && form.StoneID == b.Item.ID {
+			return form
+		}
+	}
+	return nil
+}
+
+// PrimalRevert 原始回归：属性、种族值与特性变为原始形态，HP保持不变
+func (b *Battler) PrimalRevert(form *MegaForm) {
+	b.transformInto(form)
+	b.IsPrimal = true
+}
+
+// tryPrimalReversion 携带朱红色宝珠的固拉多、携带靛蓝色宝珠的盖欧卡出场时原始回归
+func (b *Battle) tryPrimalReversion(battler *Battler) []string {
+	form := battler.PrimalForm()
+	if form == nil {
+		return nil
+	}
+	oldName := battler.Pokemon.Name
+	battler.PrimalRevert(form)
+	return []string{
+		"🌋 " + oldName + " 的" + battler.Item.Name + "发出了耀眼的光芒！",
+		"🌋 " + oldName + " 发生了原始回归！变成了 " + form.Name + "！",
+	}
+}
@@ -1,9 +1,7 @@
 package entity
 
 import (
-	"math/rand"
-	"time"
-
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
 	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
 )
 
@@ -25,14 +23,25 @@ type Battler struct {
 	StatStages    StatStages             // 能力等级变化
 	Status        StatusCondition        // 异常状态
 	StatusTurns   int                    // 状态持续回合
+	SleepInflicted bool                  // 睡眠是否由对手造成（睡眠条款）
 	Volatile      []VolatileStatus       // 临时状态
+	VolatileTurns map[VolatileStatus]int // 临时状态剩余回合
+	AttractedTo   *Battler               // 着迷的对象
+	DisabledMove  *Move                  // 被定身法封住的技能
+	EncoreMove    *Move                  // 被再来一次锁定的技能
+	SubstituteHP  int                    // 替身剩余HP
+	LeechSeedBy   string                 // 种下寄生种子的玩家ID
+	LeechSeedSlot int                    // 种下寄生种子的宝可梦所在的场上位置（吸取的HP回复给该位置的宝可梦）
 	Types         []valueobject.PokeType // 当前属性
 	Ability       *valueobject.Ability   // 当前特性
 	Item          *valueobject.Item      // 当前道具
 	ItemConsumed  bool                   // 道具是否已消耗
 	LastMove      *Move                  // 上一次使用的技能
 	LastMoveTurns int                    // 连续使用同技能的回合数
+	ChoiceLock    *Move                  // 讲究系列道具锁定的技能
 	Protected     bool                   // 是否处于守住状态
+	ProtectCount  int                    // 连续成功使用守住类技能的次数
+	Fainted       bool                   // 是否已宣告倒下（倒下的宝可梦留在场上直到替补上场）
 	Flinched      bool                   // 是否畏缩
 	MustRecharge  bool                   // 下回合必须充能（如破坏光线后）
 	IsCharging    bool                   // 正在蓄力中（如日光束）
@@ -40,10 +49,12 @@ type Battler struct {
 
 	// 特殊系统状态
 	IsMega         bool                 // 是否已超级进化
+	IsPrimal       bool                 // 是否已原始回归
 	IsDynamaxed    bool                 // 是否已极巨化
 	DynamaxTurns   int                  // 极巨化剩余回合
-	IsTerastalized bool                 // 是否已太晶化
-	TeraType       valueobject.PokeType // 太晶属性
+	IsTerastalized bool                   // 是否已太晶化
+	TeraType       valueobject.PokeType   // 太晶属性
+	PreTeraTypes   []valueobject.PokeType // 太晶化前的属性（仍享受同属性加成）
 	UsedZMove      bool                 // 是否已使用Z招式
 
 	// 形态变化状态
@@ -51,7 +62,7 @@ type Battler struct {
 	OriginalFormID      int                    // 原始形态ID
 	OriginalTypes       []valueobject.PokeType // 原始属性
 	OriginalStats       Stats                  // 原始能力值（用于恢复）
-	FormChangeBoosts    map[string]int         // 形态变化带来的能力值加成
+	FormChangeStats     map[string]int         // 变化后形态的种族值
 	FormChangeName      string                 // 变化后的形态名称
 	FormChangeSpriteURL string                 // 变化后的精灵图URL
 }
@@ -97,18 +108,24 @@ const (
 
 // NewBattler 创建对战宝可梦（简化版，兼容旧代码）
 func NewBattler(pokemon *Pokemon, level int) *Battler {
+	return NewBattlerFromBuild(newDefaultBuild(pokemon, level))
+}
+
+// newDefaultBuild 按宝可梦上的玩家设置创建配置（未选技能时使用前4个可学技能）
+func newDefaultBuild(pokemon *Pokemon, level int) *PokemonBuild {
 	build := NewPokemonBuild(pokemon)
 	build.Level = level
 	// 使用玩家选择的特性（如果已设置）
 	if pokemon.SelectedAbility != nil {
 		build.Ability = pokemon.SelectedAbility
 	}
+	build.AssignRandomGender()
 	if len(pokemon.LearnableMoves) > 0 {
 		for i := 0; i < 4 && i < len(pokemon.LearnableMoves); i++ {
 			build.AddMove(pokemon.LearnableMoves[i])
 		}
 	}
-	return NewBattlerFromBuild(build)
+	return build
 }
 
 // NewBattlerFromBuild 从配置创建对战宝可梦
@@ -123,6 +140,8 @@ func NewBattlerFromBuild(build *PokemonBuild) *Battler {
 		TeraType:   build.TeraType,
 		StatStages: StatStages{},
 		Volatile:   make([]VolatileStatus, 0),
+
+		VolatileTurns: make(map[VolatileStatus]int),
 	}
 	b.calculateStats()
 	b.copyMoves()
@@ -131,25 +150,37 @@ func NewBattlerFromBuild(build *PokemonBuild) *Battler {
 
 // calculateStats 计算实际属性（完整公式）
 func (b *Battler) calculateStats() {
-	pokemon := b.Pokemon
+	stats := b.statsFor(b.Pokemon)
+	b.MaxHP = stats.HP
+	b.CurrentHP = b.MaxHP
+	b.Atk = stats.Atk
+	b.Def = stats.Def
+	b.SpAtk = stats.SpAtk
+	b.SpDef = stats.SpDef
+	b.Speed = stats.Speed
+}
+
+// statsFor 按宝可梦的种族值计算实际能力值（等级、个体值、努力值与性格使用当前配置）
+func (b *Battler) statsFor(pokemon *Pokemon) Stats {
 	level := b.Level
 	ivs := b.Build.IVs
 	evs := b.Build.EVs
 	nature := valueobject.GetNatureModifier(b.Build.Nature)
 
-	// HP = floor((2 * Base + IV + floor(EV/4)) * Level / 100) + Level + 10
-	b.MaxHP = ((2*pokemon.BaseHP+ivs.HP+evs.HP/4)*level)/100 + level + 10
-	b.CurrentHP = b.MaxHP
+	return Stats{
+		// HP = floor((2 * Base + IV + floor(EV/4)) * Level / 100) + Level + 10
+		HP: ((2*pokemon.BaseHP+ivs.HP+evs.HP/4)*level)/100 + level + 10,
 
-	// 其他属性 = floor((floor((2 * Base + IV + floor(EV/4)) * Level / 100) + 5) * Nature)
-	b.Atk = int(float64(((2*pokemon.BaseAtk+ivs.Atk+evs.Atk/4)*level)/100+5) * nature.Atk)
-	b.Def = int(float64(((2*pokemon.BaseDef+ivs.Def+evs.Def/4)*level)/100+5) * nature.Def)
-	b.SpAtk = int(float64(((2*pokemon.BaseSpAtk+ivs.SpAtk+evs.SpAtk/4)*level)/100+5) * nature.SpAtk)
-	b.SpDef = int(float64(((2*pokemon.BaseSpDef+ivs.SpDef+evs.SpDef/4)*level)/100+5) * nature.SpDef)
-	b.Speed = int(float64(((2*pokemon.BaseSpeed+ivs.Speed+evs.Speed/4)*level)/100+5) * nature.Speed)
+		// 其他属性 = floor((floor((2 * Base + IV + floor(EV/4)) * Level / 100) + 5) * Nature)
+		Atk:   int(float64(((2*pokemon.BaseAtk+ivs.Atk+evs.Atk/4)*level)/100+5) * nature.Atk),
+		Def:   int(float64(((2*pokemon.BaseDef+ivs.Def+evs.Def/4)*level)/100+5) * nature.Def),
+		SpAtk: int(float64(((2*pokemon.BaseSpAtk+ivs.SpAtk+evs.SpAtk/4)*level)/100+5) * nature.SpAtk),
+		SpDef: int(float64(((2*pokemon.BaseSpDef+ivs.SpDef+evs.SpDef/4)*level)/100+5) * nature.SpDef),
+		Speed: int(float64(((2*pokemon.BaseSpeed+ivs.Speed+evs.Speed/4)*level)/100+5) * nature.Speed),
+	}
 }
 
-// copyMoves 复制技能
+// copyMoves 复制技能（共享技能的静态数据，只复制PP）
 func (b *Battler) copyMoves() {
 	sourceMoves := b.Build.Moves
 	if len(sourceMoves) == 0 {
@@ -157,15 +188,7 @@ func (b *Battler) copyMoves() {
 	}
 	b.Moves = make([]*Move, len(sourceMoves))
 	for i, m := range sourceMoves {
-		b.Moves[i] = &Move{
-			Name:     m.Name,
-			Type:     m.Type,
-			Category: m.Category,
-			Power:    m.Power,
-			Accuracy: m.Accuracy,
-			PP:       m.PP,
-			MaxPP:    m.MaxPP,
-		}
+		b.Moves[i] = &Move{MoveData: m.MoveData, PP: m.PP}
 	}
 }
 
@@ -195,9 +218,6 @@ func (b *Battler) GetEffectiveSpeed() int {
 	if b.Status == StatusParalyze {
 		speed = speed / 2
 	}
-	if b.Item != nil && b.Item.Name == "讲究围巾" && !b.ItemConsumed {
-		speed = speed * 3 / 2
-	}
 	return speed
 }
 
@@ -283,6 +303,26 @@ func (b *Battler) GetHPPercent() float64 {
 	return float64(b.CurrentHP) / float64(b.MaxHP) * 100
 }
 
+// HasType 是否拥有指定属性
+func (b *Battler) HasType(t valueobject.PokeType) bool {
+	for _, own := range b.Types {
+		if own == t {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAbility 是否拥有指定特性
+func (b *Battler) hasAbility(a valueobject.Ability) bool {
+	return b.Ability != nil && b.Ability.ID == a.ID
+}
+
+// holdsItem 是否携带指定道具（已消耗的道具不算）
+func (b *Battler) holdsItem(item valueobject.Item) bool {
+	return b.Item != nil && !b.ItemConsumed && b.Item.ID == item.ID
+}
+
 // DamageResult 伤害计算结果
 type DamageResult struct {
 	Damage        int
@@ -292,20 +332,27 @@ type DamageResult struct {
 }
 
 // CalculateDamage 计算伤害（完整公式）
-func (b *Battler) CalculateDamage(move *Move, target *Battler) DamageResult {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	result := DamageResult{Hit: false}
-
+// mod 为特性系统合并后的伤害修正，按以下顺序作用于公式：
+// 威力修正 → 攻击/防御修正 → 基础伤害 → 会心 → 随机 → 本属性 → 属性克制 → 最终伤害修正
+func (b *Battler) CalculateDamage(move *Move, target *Battler, mod *ability.DamageModifier) DamageResult {
 	// 命中判定
 	accuracy := move.Accuracy
 	if accuracy > 0 {
 		accMod := applyStatStage(100, b.StatStages.Accuracy-target.StatStages.Evasion)
 		effectiveAcc := accuracy * accMod / 100
-		if r.Intn(100) >= effectiveAcc {
-			return result
+		if randInt(100) >= effectiveAcc {
+			return DamageResult{Hit: false}
 		}
 	}
-	result.Hit = true
+	return b.CalculateHitDamage(move, target, mod)
+}
+
+// CalculateHitDamage 计算单次命中的伤害（不含命中判定，用于连续攻击的后续段数）
+func (b *Battler) CalculateHitDamage(move *Move, target *Battler, mod *ability.DamageModifier) DamageResult {
+	result := DamageResult{Hit: true}
+	if mod == nil {
+		mod = ability.NewDamageModifier()
+	}
 
 	// 变化技能不造成伤害
 	if move.Category == CategoryStatus {
@@ -313,13 +360,19 @@ func (b *Battler) CalculateDamage(move *Move, target *Battler) DamageResult {
 		return result
 	}
 
+	// 技能属性（特性可能改变属性，如妖精皮肤）
+	moveType := move.Type
+	if mod.TypeOverride != nil {
+		moveType = *mod.TypeOverride
+	}
+
 	// 选择攻击和防御属性
 	var atk, def int
 	if move.Category == CategoryPhysical {
 		atk = b.GetEffectiveAtk()
 		def = target.GetEffectiveDef()
 		// 灼伤减攻击（除非有毅力特性）
-		if b.Status == StatusBurn && (b.Ability == nil || b.Ability.Name != "毅力") {
+		if b.Status == StatusBurn && !b.hasAbility(valueobject.AbilityGuts) {
 			atk = atk / 2
 		}
 	} else {
@@ -327,73 +380,45 @@ func (b *Battler) CalculateDamage(move *Move, target *Battler) DamageResult {
 		def = target.GetEffectiveSpDef()
 	}
 
-	// 道具加成
-	if b.Item != nil && !b.ItemConsumed {
-		switch b.Item.Name {
-		case "讲究头带":
-			if move.Category == CategoryPhysical {
-				atk = atk * 3 / 2
-			}
-		case "讲究眼镜":
-			if move.Category == CategorySpecial {
-				atk = atk * 3 / 2
-			}
-		}
+	// 特性与道具的攻击/防御修正（如大力士、毛皮大衣、讲究头带）
+	atk = int(float64(atk) * mod.AttackMod)
+	def = int(float64(def) * mod.DefenseMod)
+	if def < 1 {
+		def = 1
 	}
 
-	// 极巨化威力转换
+	// 极巨化时技能已转换为极巨招式，威力由 MaxMoveFor 换算
 	power := move.Power
-	if b.IsDynamaxed {
-		if power < 40 {
-			power = 90
-		} else if power < 50 {
-			power = 100
-		} else if power < 60 {
-			power = 110
-		} else if power < 70 {
-			power = 120
-		} else if power < 100 {
-			power = 130
-		} else if power < 140 {
-			power = 140
-		} else {
-			power = 150
-		}
+
+	// 太晶化：威力不足 60 的太晶属性技能提升至 60（连续攻击与先制技能除外）
+	if b.IsTerastalized && moveType == b.TeraType && power < 60 && move.Priority <= 0 && !move.IsMultiHit() {
+		power = 60
+	}
+
+	// 特性与道具的威力修正（如技术高手、铁拳、属性强化道具）
+	power = int(float64(power) * mod.PowerMod)
+	if power < 1 {
+		power = 1
 	}
 
 	// 基础伤害公式
 	baseDamage := ((2*b.Level/5+2)*power*atk/def)/50 + 2
 
-	// 属性克制
-	defenseTypes := target.Types
-	if target.IsTerastalized {
-		defenseTypes = []valueobject.PokeType{target.TeraType}
-	}
-	result.Effectiveness = valueobject.GetEffectiveness(move.Type, defenseTypes)
-
-	// 同属性加成 (STAB)
-	stab := 1.0
-	attackTypes := b.Types
-	if b.IsTerastalized {
-		attackTypes = append(attackTypes, b.TeraType)
-	}
-	for _, t := range attackTypes {
-		if t == move.Type {
-			stab = 1.5
-			// 适应力特性
-			if b.Ability != nil && b.Ability.Name == "适应力" {
-				stab = 2.0
-			}
-			break
-		}
-	}
+	// 属性克制（太晶化后 Types 已变为太晶属性）
+	result.Effectiveness = valueobject.GetEffectiveness(moveType, target.Types)
+
+	// 同属性加成 (STAB)，适应力等由特性修正提供
+	stab := b.stabMultiplier(moveType, mod)
 
 	// 随机因子 (85-100%)
-	randomFactor := float64(r.Intn(16)+85) / 100.0
+	randomFactor := float64(randInt(16)+85) / 100.0
 
 	// 会心一击判定
 	critical := 1.0
 	critStage := 0
+	if move.Meta != nil {
+		critStage += move.Meta.CritRate
+	}
 	for _, v := range b.Volatile {
 		if v == VolatileFocusEnergy {
 			critStage += 2
@@ -403,30 +428,20 @@ func (b *Battler) CalculateDamage(move *Move, target *Battler) DamageResult {
 	if critStage > 3 {
 		critStage = 3
 	}
-	if r.Intn(critChances[critStage]) == 0 {
-		critical = 1.5
+	if randInt(critChances[critStage]) == 0 {
+		// 狙击手等特性提供会心修正
+		critical = 1.5 * mod.CritMod
 		result.Critical = true
-		// 狙击手特性
-		if b.Ability != nil && b.Ability.Name == "狙击手" {
-			critical = 2.25
-		}
-	}
-
-	// 道具威力加成
-	itemMod := 1.0
-	if b.Item != nil && !b.ItemConsumed {
-		switch b.Item.Name {
-		case "生命宝珠":
-			itemMod = 1.3
-		case "达人带":
-			if result.Effectiveness > 1 {
-				itemMod = 1.2
-			}
-		}
 	}
 
 	// 最终伤害
-	result.Damage = int(float64(baseDamage) * result.Effectiveness * stab * randomFactor * critical * itemMod)
+	damage := float64(baseDamage)
+	damage *= critical
+	damage *= randomFactor
+	damage *= stab
+	damage *= result.Effectiveness
+	damage *= mod.DamageMod
+	result.Damage = int(damage)
 	if result.Damage < 1 && result.Effectiveness > 0 {
 		result.Damage = 1
 	}
@@ -434,18 +449,43 @@ func (b *Battler) CalculateDamage(move *Move, target *Battler) DamageResult {
 	return result
 }
 
-// TakeDamageWithItem 受到伤害（含道具效果）
-func (b *Battler) TakeDamageWithItem(damage int) int {
-	// 气势披带
-	if b.Item != nil && b.Item.Name == "气势披带" && !b.ItemConsumed && b.CurrentHP == b.MaxHP && damage >= b.CurrentHP {
-		damage = b.CurrentHP - 1
-		b.ItemConsumed = true
+// stabMultiplier 计算同属性加成
+// 太晶化后原属性与太晶属性都享受加成，两者一致时为 2 倍（适应力为 2.25 倍）
+func (b *Battler) stabMultiplier(moveType valueobject.PokeType, mod *ability.DamageModifier) float64 {
+	if !b.IsTerastalized {
+		if b.HasType(moveType) {
+			return 1.5 * mod.STABMod
+		}
+		return 1.0
+	}
+
+	matchesOriginal := false
+	for _, t := range b.PreTeraTypes {
+		if t == moveType {
+			matchesOriginal = true
+			break
+		}
+	}
+	matchesTera := moveType == b.TeraType
+
+	switch {
+	case matchesOriginal && matchesTera:
+		if mod.STABMod > 1 {
+			return 2.25
+		}
+		return 2.0
+	case matchesTera:
+		return 1.5 * mod.STABMod
+	case matchesOriginal:
+		return 1.5
 	}
-	return b.TakeDamage(damage)
+	return 1.0
 }
 
 // ApplyFormChange 应用形态变化
-func (b *Battler) ApplyFormChange(newTypes []valueobject.PokeType, statBoosts map[string]int, formName string, spriteURL string) {
+// baseStats 为新形态的种族值（hp/atk/def/spatk/spdef/speed，缺少的项沿用原形态），按种族值重新计算能力值；
+// 最大HP变化时当前HP随之增减（如群聚变形）
+func (b *Battler) ApplyFormChange(newTypes []valueobject.PokeType, baseStats map[string]int, formName string, spriteURL string) {
 	if b.IsFormChanged {
 		return // 已经形态变化过了
 	}
@@ -466,31 +506,51 @@ func (b *Battler) ApplyFormChange(newTypes []valueobject.PokeType, statBoosts ma
 	b.IsFormChanged = true
 	b.FormChangeName = formName
 	b.FormChangeSpriteURL = spriteURL
-	b.FormChangeBoosts = statBoosts
+	b.FormChangeStats = baseStats
 
 	// 更新属性
 	if len(newTypes) > 0 {
-		b.Types = newTypes
+		b.Types = append([]valueobject.PokeType{}, newTypes...)
 	}
 
-	// 应用能力值加成
-	if statBoosts != nil {
-		if boost, ok := statBoosts["atk"]; ok {
-			b.Atk += boost
-		}
-		if boost, ok := statBoosts["def"]; ok {
-			b.Def += boost
-		}
-		if boost, ok := statBoosts["spatk"]; ok {
-			b.SpAtk += boost
-		}
-		if boost, ok := statBoosts["spdef"]; ok {
-			b.SpDef += boost
-		}
-		if boost, ok := statBoosts["speed"]; ok {
-			b.Speed += boost
+	// 按新形态的种族值重新计算能力值
+	if len(baseStats) == 0 {
+		return
+	}
+	form := *b.Pokemon
+	for stat, base := range map[string]*int{
+		"hp": &form.BaseHP, "atk": &form.BaseAtk, "def": &form.BaseDef,
+		"spatk": &form.BaseSpAtk, "spdef": &form.BaseSpDef, "speed": &form.BaseSpeed,
+	} {
+		if value, ok := baseStats[stat]; ok {
+			*base = value
 		}
 	}
+	stats := b.statsFor(&form)
+	b.setMaxHP(stats.HP)
+	b.Atk = stats.Atk
+	b.Def = stats.Def
+	b.SpAtk = stats.SpAtk
+	b.SpDef = stats.SpDef
+	b.Speed = stats.Speed
+}
+
+// setMaxHP 改变最大HP，当前HP随之增减（不会因此濒死）
+func (b *Battler) setMaxHP(maxHP int) {
+	if maxHP == b.MaxHP {
+		return
+	}
+	hp := b.CurrentHP + maxHP - b.MaxHP
+	switch {
+	case b.CurrentHP <= 0:
+		hp = 0
+	case hp < 1:
+		hp = 1
+	case hp > maxHP:
+		hp = maxHP
+	}
+	b.MaxHP = maxHP
+	b.CurrentHP = hp
 }
 
 // RevertFormChange 恢复原始形态
@@ -501,6 +561,7 @@ func (b *Battler) RevertFormChange() {
 
 	// 恢复属性
 	b.Types = b.OriginalTypes
+	b.setMaxHP(b.OriginalStats.HP)
 	b.Atk = b.OriginalStats.Atk
 	b.Def = b.OriginalStats.Def
 	b.SpAtk = b.OriginalStats.SpAtk
@@ -511,7 +572,7 @@ func (b *Battler) RevertFormChange() {
 	b.IsFormChanged = false
 	b.FormChangeName = ""
 	b.FormChangeSpriteURL = ""
-	b.FormChangeBoosts = nil
+	b.FormChangeStats = nil
 }
 
 // GetDisplayName 获取显示名称（考虑形态变化）
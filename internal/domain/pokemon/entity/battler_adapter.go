@@ -5,7 +5,7 @@ import (
 )
 
 // ============================================
-// Battler 接口方法实现（用于特性系统）
+// Battler 接口方法实现（用于特性与道具系统）
 // ============================================
 
 // GetAbility 获取特性
@@ -80,6 +80,26 @@ func (b *Battler) ConsumeItem() {
 	b.ItemConsumed = true
 }
 
+// GetName 获取宝可梦名称
+func (b *Battler) GetName() string {
+	return b.Pokemon.Name
+}
+
+// GetPokemonID 获取宝可梦ID
+func (b *Battler) GetPokemonID() int {
+	return b.Pokemon.ID
+}
+
+// CanEvolve 是否还能进化
+func (b *Battler) CanEvolve() bool {
+	return b.Pokemon.CanEvolve
+}
+
+// GetConsecutiveMoveCount 获取连续使用同一技能的次数
+func (b *Battler) GetConsecutiveMoveCount() int {
+	return b.LastMoveTurns
+}
+
 // ============================================
 // Move 接口方法实现（用于特性系统）
 // ============================================
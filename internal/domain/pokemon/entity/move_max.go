@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 极巨招式（极巨化时技能按属性转换）
+// ============================================
+
+// DynamaxTurns 极巨化持续回合数
+const DynamaxTurns = 3
+
+// maxMoveEffect 极巨招式的追加效果
+type maxMoveEffect struct {
+	Name     string              // 极巨招式名称
+	Weather  valueobject.Weather // 改变天气
+	Terrain  valueobject.Terrain // 改变场地
+	SelfStat string              // 提升己方在场宝可梦的能力
+	FoeStat  string              // 降低对手在场宝可梦的能力
+}
+
+// maxMoveEffects 各属性对应的极巨招式
+var maxMoveEffects = map[valueobject.PokeType]maxMoveEffect{
+	valueobject.TypeNormal:   {Name: "极巨攻击", FoeStat: "speed"},
+	valueobject.TypeFire:     {Name: "极巨火爆", Weather: valueobject.WeatherSun},
+	valueobject.TypeWater:    {Name: "极巨水流", Weather: valueobject.WeatherRain},
+	valueobject.TypeElectric: {Name: "极巨闪电", Terrain: valueobject.TerrainElectric},
+	valueobject.TypeGrass:    {Name: "极巨草原", Terrain: valueobject.TerrainGrassy},
+	valueobject.TypeIce:      {Name: "极巨寒冰", Weather: valueobject.WeatherHail},
+	valueobject.TypeFighting: {Name: "极巨拳斗", SelfStat: "atk"},
+	valueobject.TypePoison:   {Name: "极巨酸毒", SelfStat: "spatk"},
+	valueobject.TypeGround:   {Name: "极巨大地", SelfStat: "spdef"},
+	valueobject.TypeFlying:   {Name: "极巨飞冲", SelfStat: "speed"},
+	valueobject.TypePsychic:  {Name: "极巨超能", Terrain: valueobject.TerrainPsychic},
+	valueobject.TypeBug:      {Name: "极巨虫蛊", FoeStat: "spatk"},
+	valueobject.TypeRock:     {Name: "极巨岩石", Weather: valueobject.WeatherSand},
+	valueobject.TypeGhost:    {Name: "极巨幽魂", FoeStat: "def"},
+	valueobject.TypeDragon:   {Name: "极巨龙骑", FoeStat: "atk"},
+	valueobject.TypeDark:     {Name: "极巨恶霸", FoeStat: "spdef"},
+	valueobject.TypeSteel:    {Name: "极巨钢铁", SelfStat: "def"},
+	valueobject.TypeFairy:    {Name: "极巨妖精", Terrain: valueobject.TerrainMisty},
+}
+
+// MaxGuardName 极巨防壁（变化技能转换后的极巨招式）
+const MaxGuardName = "极巨防壁"
+
+// weightBasedMoveIDs 根据体重计算威力的技能（对极巨化的宝可梦无效）
+var weightBasedMoveIDs = map[int]bool{
+	67:  true, // 踢倒
+	447: true, // 打草结
+	484: true, // 重磅冲撞
+	535: true, // 高温重压
+}
+
+// IsWeightBased 是否为根据体重计算威力的技能
+func (m *Move) IsWeightBased() bool {
+	return weightBasedMoveIDs[m.ID]
+}
+
+// IsMaxGuard 是否为极巨防壁
+func (m *Move) IsMaxGuard() bool {
+	return m.IsMax && m.Category == CategoryStatus
+}
+
+// MaxMoveFor 将技能转换为对应的极巨招式（不修改原技能，PP 仍由原技能消耗）
+// 极巨招式不保留原技能ID，不会触发按技能ID判定的效果（如根据体重计算威力、高速旋转清除陷阱）
+func MaxMoveFor(move *Move) *Move {
+	if move.Category == CategoryStatus {
+		return &Move{
+			MoveData: &MoveData{
+				Name:     MaxGuardName,
+				Type:     move.Type,
+				Category: CategoryStatus,
+				MaxPP:    move.MaxPP,
+				Priority: 4,
+				Target:   TargetUser,
+				IsMax:    true,
+			},
+			PP: move.PP,
+		}
+	}
+
+	name := "极巨攻击"
+	if effect, ok := maxMoveEffects[move.Type]; ok {
+		name = effect.Name
+	}
+	target := move.Target
+	if !target.IsSingleTarget() {
+		target = TargetSelectedPokemon
+	}
+	return &Move{
+		MoveData: &MoveData{
+			Name:     name,
+			Type:     move.Type,
+			Category: move.Category,
+			Power:    maxMovePower(move),
+			MaxPP:    move.MaxPP,
+			Target:   target,
+			IsMax:    true,
+		},
+		PP: move.PP,
+	}
+}
+
+// maxMovePower 计算极巨招式威力（格斗、毒属性使用较低的换算表）
+func maxMovePower(move *Move) int {
+	power := move.Power
+	if power <= 0 {
+		// 踢倒等威力不定的技能
+		power = 60
+	}
+	reduced := move.Type == valueobject.TypeFighting || move.Type == valueobject.TypePoison
+	switch {
+	case power < 45:
+		return pick(reduced, 70, 90)
+	case power < 55:
+		return pick(reduced, 75, 100)
+	case power < 65:
+		return pick(reduced, 80, 110)
+	case power < 75:
+		return pick(reduced, 85, 120)
+	case power < 110:
+		return pick(reduced, 90, 130)
+	case power < 150:
+		return pick(reduced, 95, 140)
+	}
+	return pick(reduced, 100, 150)
+}
+
+// pick 按条件选择数值
+func pick(cond bool, a, b int) int {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,141 @@
+package entity
+
+// MoveTarget 技能目标（对应 PokeAPI move_targets）
+type MoveTarget int
+
+const (
+	TargetSpecificMove    MoveTarget = 1  // 特定技能（如反击、镜面反射）
+	TargetSelectedMeFirst MoveTarget = 2  // 选择的宝可梦（抢先一步）
+	TargetAlly            MoveTarget = 3  // 同伴
+	TargetUsersField      MoveTarget = 4  // 己方场地
+	TargetUserOrAlly      MoveTarget = 5  // 自己或同伴
+	TargetOpponentsField  MoveTarget = 6  // 对方场地
+	TargetUser            MoveTarget = 7  // 自己
+	TargetRandomOpponent  MoveTarget = 8  // 随机一个对手
+	TargetAllOtherPokemon MoveTarget = 9  // 除自己以外的所有宝可梦
+	TargetSelectedPokemon MoveTarget = 10 // 选择的宝可梦
+	TargetAllOpponents    MoveTarget = 11 // 所有对手
+	TargetEntireField     MoveTarget = 12 // 整个场地
+	TargetUserAndAllies   MoveTarget = 13 // 自己和同伴
+	TargetAllPokemon      MoveTarget = 14 // 所有宝可梦
+	TargetAllAllies       MoveTarget = 15 // 所有同伴
+	TargetFaintingPokemon MoveTarget = 16 // 濒死的宝可梦
+)
+
+// IsSelfTarget 是否以自己或己方场地为目标（以同伴为目标的技能见 IsAllyTarget）
+func (t MoveTarget) IsSelfTarget() bool {
+	switch t {
+	case TargetUser, TargetUsersField, TargetUserOrAlly, TargetUserAndAllies:
+		return true
+	}
+	return false
+}
+
+// IsAllyTarget 是否只以同伴为目标（如帮助、芳香薄雾，单打时没有目标）
+func (t MoveTarget) IsAllyTarget() bool {
+	return t == TargetAlly || t == TargetAllAllies
+}
+
+// IsSingleTarget 是否为需要选择的单体目标
+func (t MoveTarget) IsSingleTarget() bool {
+	switch t {
+	case TargetSelectedPokemon, TargetSelectedMeFirst, TargetSpecificMove, 0:
+		return true
+	}
+	return false
+}
+
+// MoveMetaCategory 技能效果分类（对应 PokeAPI move_meta_categories）
+type MoveMetaCategory int
+
+const (
+	MetaDamage           MoveMetaCategory = 0  // 纯伤害
+	MetaAilment          MoveMetaCategory = 1  // 施加状态
+	MetaNetGoodStats     MoveMetaCategory = 2  // 能力变化
+	MetaHeal             MoveMetaCategory = 3  // 回复
+	MetaDamageAilment    MoveMetaCategory = 4  // 伤害 + 状态
+	MetaSwagger          MoveMetaCategory = 5  // 提升对手能力 + 混乱
+	MetaDamageLower      MoveMetaCategory = 6  // 伤害 + 降低目标能力
+	MetaDamageRaise      MoveMetaCategory = 7  // 伤害 + 改变自身能力
+	MetaDamageHeal       MoveMetaCategory = 8  // 伤害 + 吸取
+	MetaOHKO             MoveMetaCategory = 9  // 一击必杀
+	MetaWholeFieldEffect MoveMetaCategory = 10 // 全场效果
+	MetaFieldEffect      MoveMetaCategory = 11 // 单侧场地效果
+	MetaForceSwitch      MoveMetaCategory = 12 // 强制替换
+	MetaUnique           MoveMetaCategory = 13 // 特殊效果
+)
+
+// MoveMeta 技能追加效果数据（来自 move_meta.csv 等）
+// 同一技能的所有副本共享同一份 MoveMeta，不可在对战中修改
+type MoveMeta struct {
+	Category      MoveMetaCategory // 效果分类
+	Ailment       string           // 附加状态（异常状态或临时状态名称，空表示无）
+	AilmentChance int              // 附加状态几率（0 表示必定触发）
+	MinHits       int              // 最少攻击次数（0 表示单次）
+	MaxHits       int              // 最多攻击次数
+	MinTurns      int              // 状态最少持续回合
+	MaxTurns      int              // 状态最多持续回合
+	Drain         int              // 吸取比例（正数为回复造成伤害的百分比，负数为反作用伤害百分比）
+	Healing       int              // 回复最大HP的百分比（负数为损失）
+	CritRate      int              // 会心等级加成
+	FlinchChance  int              // 畏缩几率
+	StatChance    int              // 能力变化几率（0 表示必定触发）
+	StatChanges   map[string]int   // 能力变化（atk/def/spatk/spdef/speed/accuracy/evasion）
+}
+
+// StatChangeTargetsSelf 能力变化是否作用于使用者
+func (m *Move) StatChangeTargetsSelf() bool {
+	if m.Meta == nil {
+		return false
+	}
+	switch m.Meta.Category {
+	case MetaDamageRaise:
+		return true
+	case MetaDamageLower, MetaSwagger:
+		return false
+	}
+	return m.Target.IsSelfTarget()
+}
+
+// IsMultiHit 是否为连续攻击技能
+func (m *Move) IsMultiHit() bool {
+	return m.Meta != nil && m.Meta.MaxHits > 1
+}
+
+// GetHitCount 随机决定连续攻击次数（2-5 次按 35/35/15/15 分布）
+func (m *Move) GetHitCount() int {
+	if m.Meta == nil || m.Meta.MaxHits <= 1 {
+		return 1
+	}
+	minHits, maxHits := m.Meta.MinHits, m.Meta.MaxHits
+	if minHits < 1 {
+		minHits = 1
+	}
+	if minHits == maxHits {
+		return minHits
+	}
+	if minHits == 2 && maxHits == 5 {
+		roll := randInt(100)
+		switch {
+		case roll < 35:
+			return 2
+		case roll < 70:
+			return 3
+		case roll < 85:
+			return 4
+		default:
+			return 5
+		}
+	}
+	return minHits + randInt(maxHits-minHits+1)
+}
+
+// IsOHKO 是否为一击必杀技能
+func (m *Move) IsOHKO() bool {
+	return m.Meta != nil && m.Meta.Category == MetaOHKO
+}
+
+// RaisesEvasion 是否为提升自身闪避率的技能（如影子分身、变小）
+func (m *Move) RaisesEvasion() bool {
+	return m.Meta != nil && m.Meta.StatChanges["evasion"] > 0 && m.StatChangeTargetsSelf()
+}
@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// Z招式（携带Z纯晶时同属性技能转换为Z招式）
+// ============================================
+
+// zMoveNames 各属性对应的攻击类Z招式
+var zMoveNames = map[valueobject.PokeType]string{
+	valueobject.TypeNormal:   "究极无敌大冲撞",
+	valueobject.TypeFire:     "超强极限爆焰弹",
+	valueobject.TypeWater:    "超级无敌大旋涡",
+	valueobject.TypeElectric: "终极伏特狂雷闪",
+	valueobject.TypeGrass:    "绚烂缤纷花怒放",
+	valueobject.TypeIce:      "激狂大地万里冰",
+	valueobject.TypeFighting: "全力无双激烈拳",
+	valueobject.TypePoison:   "强酸剧毒灭绝雨",
+	valueobject.TypeGround:   "地隆啸天大终结",
+	valueobject.TypeFlying:   "极速俯冲轰烈撞",
+	valueobject.TypePsychic:  "至高精神破坏波",
+	valueobject.TypeBug:      "绝对捕食回旋斩",
+	valueobject.TypeRock:     "毁天灭地巨岩坠",
+	valueobject.TypeGhost:    "无尽暗夜之诱惑",
+	valueobject.TypeDragon:   "究极巨龙震天地",
+	valueobject.TypeDark:     "黑洞吞噬万物灭",
+	valueobject.TypeSteel:    "超绝螺旋连击",
+	valueobject.TypeFairy:    "可爱星星飞天撞",
+}
+
+// zStatusEffect 变化技能Z招式的追加效果（都未设置时复原下降的能力）
+type zStatusEffect struct {
+	Stats map[string]int // 提升自身能力
+	Heal  bool           // 回复全部HP
+	Crit  bool           // 变得容易击中要害
+}
+
+// zStatusEffects 变化技能的Z追加效果（按技能ID，未列出的技能如定身法、守住复原下降的能力）
+var zStatusEffects = map[int]zStatusEffect{
+	39:  {Stats: map[string]int{"atk": 1}},      // 摇尾巴
+	43:  {Stats: map[string]int{"atk": 1}},      // 瞪眼
+	45:  {Stats: map[string]int{"def": 1}},      // 叫声
+	48:  {Stats: map[string]int{"spdef": 1}},    // 超音波
+	77:  {Stats: map[string]int{"def": 1}},      // 毒粉
+	78:  {Stats: map[string]int{"spdef": 1}},    // 麻痹粉
+	79:  {Stats: map[string]int{"speed": 1}},    // 催眠粉
+	86:  {Stats: map[string]int{"spdef": 1}},    // 电磁波
+	92:  {Stats: map[string]int{"def": 1}},      // 剧毒
+	95:  {Stats: map[string]int{"speed": 1}},    // 催眠术
+	109: {Stats: map[string]int{"spatk": 1}},    // 奇异之光
+	113: {Stats: map[string]int{"spdef": 1}},    // 光墙
+	114: {Heal: true},                           // 黑雾
+	115: {Stats: map[string]int{"def": 1}},      // 反射壁
+	116: {Stats: map[string]int{"accuracy": 1}}, // 聚气
+	150: {Stats: map[string]int{"atk": 3}},      // 跃起
+	187: {Heal: true},                           // 腹鼓
+	191: {Stats: map[string]int{"def": 1}},      // 撒菱
+	201: {Stats: map[string]int{"speed": 1}},    // 沙暴
+	227: {Stats: map[string]int{"speed": 1}},    // 再来一次
+	240: {Stats: map[string]int{"speed": 1}},    // 求雨
+	241: {Stats: map[string]int{"speed": 1}},    // 大晴天
+	258: {Stats: map[string]int{"speed": 1}},    // 冰雹
+	261: {Stats: map[string]int{"atk": 1}},      // 鬼火
+	269: {Stats: map[string]int{"atk": 1}},      // 挑衅
+	366: {Crit: true},                           // 顺风
+	433: {Stats: map[string]int{"accuracy": 1}}, // 戏法空间
+	446: {Stats: map[string]int{"def": 1}},      // 隐形岩
+}
+
+// zStatusEffectFor 获取变化技能Z招式的追加效果
+func zStatusEffectFor(move *Move) zStatusEffect {
+	return zStatusEffects[move.ID]
+}
+
+// ZMoveFor 将技能转换为对应的Z招式（不修改原技能，PP 仍由原技能消耗）
+// 变化技能保留原效果并附加Z追加效果；攻击技能变为同属性的Z招式，必定命中且优先度为 0
+// 攻击技能的Z招式不保留原技能ID，不会触发按技能ID判定的效果（如高速旋转清除陷阱、急速折返换人）
+func ZMoveFor(move *Move) *Move {
+	if move.Category == CategoryStatus {
+		data := *move.MoveData
+		data.Name = "Z" + move.Name
+		data.IsZ = true
+		return &Move{MoveData: &data, PP: move.PP}
+	}
+
+	name := zMoveNames[move.Type]
+	if name == "" {
+		name = zMoveNames[valueobject.TypeNormal]
+	}
+	target := move.Target
+	if !target.IsSingleTarget() {
+		target = TargetSelectedPokemon
+	}
+	return &Move{
+		MoveData: &MoveData{
+			Name:     name,
+			Type:     move.Type,
+			Category: move.Category,
+			Power:    zMovePower(move),
+			MaxPP:    move.MaxPP,
+			Target:   target,
+			IsZ:      true,
+		},
+		PP: move.PP,
+	}
+}
+
+// zMovePower 根据原技能威力计算Z招式威力
+func zMovePower(move *Move) int {
+	power := move.Power
+	if power <= 0 {
+		// 威力不定的技能
+		power = 60
+	}
+	switch {
+	case power < 60:
+		return 100
+	case power < 70:
+		return 120
+	case power < 80:
+		return 140
+	case power < 90:
+		return 160
+	case power < 100:
+		return 175
+	case power < 110:
+		return 180
+	case power < 120:
+		return 185
+	case power < 130:
+		return 190
+	case power < 140:
+		return 195
+	}
+	return 200
+}
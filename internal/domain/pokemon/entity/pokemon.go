@@ -1,12 +1,15 @@
 package entity
 
 import (
+	"errors"
+
 	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
 )
 
 // Pokemon 宝可梦实体（图鉴数据）
 type Pokemon struct {
-	ID              int                      // 全国图鉴编号
+	ID              int                      // 全国图鉴编号（非默认形态为 PokeAPI 宝可梦ID，如阿罗拉拉达为 10091）
+	SpeciesID       int                      // 所属种类的全国图鉴编号（0 表示与 ID 相同）
 	Name            string                   // 名称
 	Types           []valueobject.PokeType   // 属性（最多2个）
 	BaseHP          int                      // 基础HP
@@ -24,7 +27,25 @@ type Pokemon struct {
 	SpriteURL       string                   // 精灵图URL
 	CanMegaEvolve   bool                     // 是否可超级进化
 	MegaStoneID     int                      // 超级石ID
+	MegaForms       []*MegaForm              // 超级进化形态
+	HeldItem        *valueobject.Item        // 携带的道具
 	CanGigantamax   bool                     // 是否可极巨化
+	CanEvolve       bool                     // 是否还能进化（进化奇石）
+	EVs             *Stats                   // 玩家设置的努力值（nil=全0）
+	IVs             *Stats                   // 玩家设置的个体值（nil=6V）
+}
+
+// MegaForm 超级进化形态
+type MegaForm struct {
+	PokemonID int                    // 形态的宝可梦ID
+	Name      string                 // 形态名称（如"超级喷火龙X"）
+	StoneID   int                    // 对应的超级石ID（原始回归为宝珠ID，烈空坐为 0）
+	MoveID    int                    // 不需要超级石时，超级进化所需的技能ID（如烈空坐的画龙点睛）
+	Primal    bool                   // 是否为原始回归形态（携带宝珠出场时自动变化）
+	Types     []valueobject.PokeType // 属性
+	BaseStats Stats                  // 种族值
+	Ability   *valueobject.Ability   // 特性
+	SpriteURL string                 // 精灵图URL
 }
 
 // PokemonBuild 宝可梦配置（玩家自定义）
@@ -74,6 +95,25 @@ func NewPokemonBuild(pokemon *Pokemon) *PokemonBuild {
 		Moves:    make([]*Move, 0, 4),
 		Gender:   GenderUnknown,
 	}
+	// 玩家已指定性格时使用指定的性格
+	if pokemon.Nature != "" {
+		build.Nature = pokemon.Nature
+	}
+	// 玩家已设置努力值/个体值时使用设置的数值
+	if pokemon.EVs != nil {
+		build.EVs = *pokemon.EVs
+	}
+	if pokemon.IVs != nil {
+		build.IVs = *pokemon.IVs
+	}
+	// 玩家已指定太晶属性时使用指定的属性
+	if pokemon.TeraType != "" {
+		build.TeraType = pokemon.TeraType
+	}
+	// 携带玩家选择的道具
+	if pokemon.HeldItem != nil {
+		build.Item = pokemon.HeldItem
+	}
 	// 默认选择第一个特性
 	if len(pokemon.Abilities) > 0 {
 		build.Ability = &pokemon.Abilities[0]
@@ -110,6 +150,65 @@ func (b *PokemonBuild) SetEVs(hp, atk, def, spAtk, spDef, speed int) {
 	}
 }
 
+// CalculateStats 按当前配置计算实际能力值（用于配置预览）
+func (b *PokemonBuild) CalculateStats() Stats {
+	battler := &Battler{Build: b, Pokemon: b.Pokemon, Level: b.Level}
+	battler.calculateStats()
+	return Stats{
+		HP:    battler.MaxHP,
+		Atk:   battler.Atk,
+		Def:   battler.Def,
+		SpAtk: battler.SpAtk,
+		SpDef: battler.SpDef,
+		Speed: battler.Speed,
+	}
+}
+
+// Total 六项之和
+func (s Stats) Total() int {
+	return s.HP + s.Atk + s.Def + s.SpAtk + s.SpDef + s.Speed
+}
+
+// Values 按 HP/攻击/防御/特攻/特防/速度 顺序返回六项数值
+func (s Stats) Values() [6]int {
+	return [6]int{s.HP, s.Atk, s.Def, s.SpAtk, s.SpDef, s.Speed}
+}
+
+// ValidateEVs 检查努力值（单项0-252，总计不超过510）
+func (s Stats) ValidateEVs() error {
+	for _, v := range s.Values() {
+		if v < 0 || v > 252 {
+			return errors.New("努力值单项必须在 0-252 之间")
+		}
+	}
+	if s.Total() > 510 {
+		return errors.New("努力值总计不能超过 510")
+	}
+	return nil
+}
+
+// ValidateIVs 检查个体值（单项0-31）
+func (s Stats) ValidateIVs() error {
+	for _, v := range s.Values() {
+		if v < 0 || v > 31 {
+			return errors.New("个体值单项必须在 0-31 之间")
+		}
+	}
+	return nil
+}
+
+// AssignRandomGender 未指定性别时随机分配（用于着迷等判定）
+func (b *PokemonBuild) AssignRandomGender() {
+	if b.Gender != GenderUnknown && b.Gender != "" {
+		return
+	}
+	if randInt(2) == 0 {
+		b.Gender = GenderMale
+	} else {
+		b.Gender = GenderFemale
+	}
+}
+
 // AddMove 添加技能
 func (b *PokemonBuild) AddMove(move *Move) bool {
 	if len(b.Moves) >= 4 {
@@ -147,6 +246,14 @@ func NewPokemon(id int, name string, types []valueobject.PokeType) *Pokemon {
 	}
 }
 
+// DexNumber 获取全国图鉴编号（非默认形态返回所属种类的编号）
+func (p *Pokemon) DexNumber() int {
+	if p.SpeciesID > 0 {
+		return p.SpeciesID
+	}
+	return p.ID
+}
+
 // SetBaseStats 设置基础属性
 func (p *Pokemon) SetBaseStats(hp, atk, def, spAtk, spDef, speed int) {
 	p.BaseHP = hp
@@ -188,22 +295,33 @@ func itoa(n int) string {
 	return result
 }
 
-// Move 技能
-type Move struct {
+// MoveData 技能的静态数据
+// 图鉴中每个技能只有一份，由所有宝可梦与对战共享，创建后不可修改
+type MoveData struct {
+	ID       int                    // 技能ID（PokeAPI）
 	Name     string                 // 技能名称
 	Type     valueobject.PokeType   // 技能属性
 	Category MoveCategory           // 技能分类
 	Power    int                    // 威力
 	Accuracy int                    // 命中率
-	PP       int                    // PP值
 	MaxPP    int                    // 最大PP
 	Priority int                    // 优先度（-7 到 +5）
-	
+
 	// 技能效果
 	RechargeRequired bool           // 使用后需要充能（如破坏光线）
 	ChargeRequired   bool           // 使用前需要蓄力（如日光束）
 	MakesContact     bool           // 是否为接触技能
 	EffectChance     int            // 追加效果触发概率（0-100）
+	Target           MoveTarget     // 技能目标
+	Meta             *MoveMeta      // 追加效果数据（能力变化、异常状态、回复等）
+	IsMax            bool           // 是否为极巨招式（极巨化时由原技能转换）
+	IsZ              bool           // 是否为Z招式（由Z纯晶转换）
+}
+
+// Move 技能（引用共享的静态数据，只保存自身的PP）
+type Move struct {
+	*MoveData
+	PP int // 剩余PP
 }
 
 // MoveCategory 技能分类
@@ -218,13 +336,15 @@ const (
 // NewMove 创建技能
 func NewMove(name string, pokeType valueobject.PokeType, category MoveCategory, power, accuracy, pp int) *Move {
 	return &Move{
-		Name:     name,
-		Type:     pokeType,
-		Category: category,
-		Power:    power,
-		Accuracy: accuracy,
-		PP:       pp,
-		MaxPP:    pp,
+		MoveData: &MoveData{
+			Name:     name,
+			Type:     pokeType,
+			Category: category,
+			Power:    power,
+			Accuracy: accuracy,
+			MaxPP:    pp,
+		},
+		PP: pp,
 	}
 }
 
@@ -0,0 +1,149 @@
+package item
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+)
+
+// TriggerType 道具触发时机
+type TriggerType string
+
+const (
+	TriggerOnDamageCalc   TriggerType = "on_damage_calc"   // 伤害计算时
+	TriggerOnSpeedCalc    TriggerType = "on_speed_calc"    // 速度计算时
+	TriggerOnBeingHit     TriggerType = "on_being_hit"     // 被攻击后
+	TriggerOnAfterAttack  TriggerType = "on_after_attack"  // 攻击后
+	TriggerOnTurnEnd      TriggerType = "on_turn_end"      // 回合结束时
+	TriggerOnHPThreshold  TriggerType = "on_hp_threshold"  // HP低于阈值时
+	TriggerOnLethalDamage TriggerType = "on_lethal_damage" // 受到致命伤害时
+	TriggerOnMoveSelect   TriggerType = "on_move_select"   // 选择技能时
+)
+
+// 与特性系统共用的接口与修正结构
+type (
+	Move           = ability.Move
+	BattleContext  = ability.BattleContext
+	DamageModifier = ability.DamageModifier
+)
+
+// Battler 持有道具的宝可梦接口（避免循环依赖）
+type Battler interface {
+	ability.Battler
+	GetName() string              // 宝可梦名称（用于消息）
+	GetPokemonID() int            // 宝可梦ID（用于专属道具）
+	CanEvolve() bool              // 是否还能进化（进化奇石）
+	GetConsecutiveMoveCount() int // 连续使用同一技能的次数（节拍器）
+}
+
+// HitResult 受击/攻击后效果结果
+type HitResult struct {
+	Messages     []string // 消息
+	RecoilDamage int      // 对另一方（受击时为攻击方，攻击后为自身）造成的伤害
+	Consume      bool     // 是否消耗道具
+}
+
+// TurnEndResult 回合结束/HP阈值效果结果
+type TurnEndResult struct {
+	Messages     []string // 消息
+	HealAmount   int      // 回复量
+	DamageAmount int      // 伤害量
+	Consume      bool     // 是否消耗道具
+}
+
+// SurviveResult 致命伤害效果结果
+type SurviveResult struct {
+	Survive bool   // 是否以 1 HP 撑住
+	Message string // 消息
+	Consume bool   // 是否消耗道具
+}
+
+// SelectResult 技能选择限制结果
+type SelectResult struct {
+	Blocked bool   // 是否不能选择
+	Reason  string // 原因
+}
+
+// Effect 道具效果接口
+type Effect interface {
+	// GetItemID 获取对应的道具ID
+	GetItemID() int
+
+	// GetTriggers 获取触发时机
+	GetTriggers() []TriggerType
+
+	// OnDamageCalcAttacker 伤害计算时触发（作为攻击方）
+	OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier
+
+	// OnDamageCalcDefender 伤害计算时触发（作为防御方）
+	OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier
+
+	// OnSpeedCalc 速度计算时触发，返回速度倍率（0 表示不修正）
+	OnSpeedCalc(self Battler, ctx *BattleContext) float64
+
+	// OnBeingHit 受到攻击后触发
+	OnBeingHit(self Battler, attacker Battler, move Move, damage int, ctx *BattleContext) *HitResult
+
+	// OnAfterAttack 使用攻击技能造成伤害后触发
+	OnAfterAttack(self Battler, move Move, damage int, ctx *BattleContext) *HitResult
+
+	// OnTurnEnd 回合结束时触发
+	OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult
+
+	// OnHPThreshold HP变化后检查（如文柚果）
+	OnHPThreshold(self Battler, ctx *BattleContext) *TurnEndResult
+
+	// OnLethalDamage 受到致命伤害时触发（如气势披带）
+	OnLethalDamage(self Battler, damage int, ctx *BattleContext) *SurviveResult
+
+	// OnMoveSelect 选择技能时检查（如突击背心）
+	OnMoveSelect(self Battler, move Move) *SelectResult
+}
+
+// BaseEffect 基础效果实现（提供默认空实现）
+type BaseEffect struct {
+	ItemID   int
+	Triggers []TriggerType
+}
+
+func (e *BaseEffect) GetItemID() int {
+	return e.ItemID
+}
+
+func (e *BaseEffect) GetTriggers() []TriggerType {
+	return e.Triggers
+}
+
+func (e *BaseEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	return nil
+}
+
+func (e *BaseEffect) OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	return nil
+}
+
+func (e *BaseEffect) OnSpeedCalc(self Battler, ctx *BattleContext) float64 {
+	return 0
+}
+
+func (e *BaseEffect) OnBeingHit(self Battler, attacker Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	return nil
+}
+
+func (e *BaseEffect) OnAfterAttack(self Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	return nil
+}
+
+func (e *BaseEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
+	return nil
+}
+
+func (e *BaseEffect) OnHPThreshold(self Battler, ctx *BattleContext) *TurnEndResult {
+	return nil
+}
+
+func (e *BaseEffect) OnLethalDamage(self Battler, damage int, ctx *BattleContext) *SurviveResult {
+	return nil
+}
+
+func (e *BaseEffect) OnMoveSelect(self Battler, move Move) *SelectResult {
+	return nil
+}
@@ -0,0 +1,454 @@
+package item
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 讲究系列（锁定技能由对战逻辑处理）
+// ============================================
+
+// ChoiceBandEffect 讲究头带
+type ChoiceBandEffect struct {
+	BaseEffect
+}
+
+func (e *ChoiceBandEffect) GetItemID() int {
+	return valueobject.ItemChoiceBand.ID
+}
+
+func (e *ChoiceBandEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *ChoiceBandEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "physical" {
+		mod := NewDamageModifier()
+		mod.AttackMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+// ChoiceSpecsEffect 讲究眼镜
+type ChoiceSpecsEffect struct {
+	BaseEffect
+}
+
+func (e *ChoiceSpecsEffect) GetItemID() int {
+	return valueobject.ItemChoiceSpecs.ID
+}
+
+func (e *ChoiceSpecsEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *ChoiceSpecsEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "special" {
+		mod := NewDamageModifier()
+		mod.AttackMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+// ChoiceScarfEffect 讲究围巾
+type ChoiceScarfEffect struct {
+	BaseEffect
+}
+
+func (e *ChoiceScarfEffect) GetItemID() int {
+	return valueobject.ItemChoiceScarf.ID
+}
+
+func (e *ChoiceScarfEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnSpeedCalc}
+}
+
+func (e *ChoiceScarfEffect) OnSpeedCalc(self Battler, ctx *BattleContext) float64 {
+	return 1.5
+}
+
+// ============================================
+// 计算修正类（攻击方）
+// ============================================
+
+// LifeOrbEffect 生命宝珠
+type LifeOrbEffect struct {
+	BaseEffect
+}
+
+func (e *LifeOrbEffect) GetItemID() int {
+	return valueobject.ItemLifeOrb.ID
+}
+
+func (e *LifeOrbEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc, TriggerOnAfterAttack}
+}
+
+func (e *LifeOrbEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "status" {
+		return nil
+	}
+	mod := NewDamageModifier()
+	mod.DamageMod = 1.3
+	return mod
+}
+
+func (e *LifeOrbEffect) OnAfterAttack(self Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	if damage <= 0 {
+		return nil
+	}
+	recoil := self.GetMaxHP() / 10
+	if recoil < 1 {
+		recoil = 1
+	}
+	return &HitResult{
+		Messages:     []string{"🔮 " + self.GetName() + " 因生命宝珠损失了HP！"},
+		RecoilDamage: recoil,
+	}
+}
+
+// ExpertBeltEffect 达人带
+type ExpertBeltEffect struct {
+	BaseEffect
+}
+
+func (e *ExpertBeltEffect) GetItemID() int {
+	return valueobject.ItemExpertBelt.ID
+}
+
+func (e *ExpertBeltEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *ExpertBeltEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if valueobject.GetEffectiveness(move.GetType(), target.GetTypes()) > 1.0 {
+		mod := NewDamageModifier()
+		mod.DamageMod = 1.2
+		return mod
+	}
+	return nil
+}
+
+// MuscleBandEffect 力量头带
+type MuscleBandEffect struct {
+	BaseEffect
+}
+
+func (e *MuscleBandEffect) GetItemID() int {
+	return valueobject.ItemMuscleBand.ID
+}
+
+func (e *MuscleBandEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *MuscleBandEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "physical" {
+		mod := NewDamageModifier()
+		mod.PowerMod = 1.1
+		return mod
+	}
+	return nil
+}
+
+// WiseGlassesEffect 博识眼镜
+type WiseGlassesEffect struct {
+	BaseEffect
+}
+
+func (e *WiseGlassesEffect) GetItemID() int {
+	return valueobject.ItemWiseGlasses.ID
+}
+
+func (e *WiseGlassesEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *WiseGlassesEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "special" {
+		mod := NewDamageModifier()
+		mod.PowerMod = 1.1
+		return mod
+	}
+	return nil
+}
+
+// MetronomeEffect 节拍器（连续使用同一技能时每次威力提升 20%，最多 2 倍）
+type MetronomeEffect struct {
+	BaseEffect
+}
+
+func (e *MetronomeEffect) GetItemID() int {
+	return valueobject.ItemMetronome.ID
+}
+
+func (e *MetronomeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *MetronomeEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	count := self.GetConsecutiveMoveCount()
+	if count <= 1 {
+		return nil
+	}
+	boost := 1.0 + 0.2*float64(count-1)
+	if boost > 2.0 {
+		boost = 2.0
+	}
+	mod := NewDamageModifier()
+	mod.DamageMod = boost
+	return mod
+}
+
+// ============================================
+// 属性强化类
+// ============================================
+
+// TypeBoostEffect 属性强化道具（对应属性技能威力x1.2）
+type TypeBoostEffect struct {
+	BaseEffect
+	Type valueobject.PokeType // 强化的属性
+}
+
+// newTypeBoost 创建属性强化道具效果
+func newTypeBoost(item valueobject.Item, pokeType valueobject.PokeType) *TypeBoostEffect {
+	return &TypeBoostEffect{
+		BaseEffect: BaseEffect{ItemID: item.ID, Triggers: []TriggerType{TriggerOnDamageCalc}},
+		Type:       pokeType,
+	}
+}
+
+func (e *TypeBoostEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetType() == e.Type {
+		mod := NewDamageModifier()
+		mod.PowerMod = 1.2
+		return mod
+	}
+	return nil
+}
+
+// ============================================
+// 宝珠系列（仅对应的宝可梦生效）
+// ============================================
+
+// orbBoost 专属宝珠的威力修正
+func orbBoost(self Battler, move Move, pokemonIDs []int, types ...valueobject.PokeType) *DamageModifier {
+	matched := false
+	for _, id := range pokemonIDs {
+		if self.GetPokemonID() == id {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil
+	}
+	for _, t := range types {
+		if move.GetType() == t {
+			mod := NewDamageModifier()
+			mod.PowerMod = 1.2
+			return mod
+		}
+	}
+	return nil
+}
+
+// AdamantOrbEffect 金刚宝珠（帝牙卢卡）
+type AdamantOrbEffect struct {
+	BaseEffect
+}
+
+func (e *AdamantOrbEffect) GetItemID() int {
+	return valueobject.ItemAdamantOrb.ID
+}
+
+func (e *AdamantOrbEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *AdamantOrbEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	return orbBoost(self, move, []int{483}, valueobject.TypeDragon, valueobject.TypeSteel)
+}
+
+// LustrousOrbEffect 白玉宝珠（帕路奇亚）
+type LustrousOrbEffect struct {
+	BaseEffect
+}
+
+func (e *LustrousOrbEffect) GetItemID() int {
+	return valueobject.ItemLustrousOrb.ID
+}
+
+func (e *LustrousOrbEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *LustrousOrbEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	return orbBoost(self, move, []int{484}, valueobject.TypeDragon, valueobject.TypeWater)
+}
+
+// GriseousOrbEffect 白金宝珠（骑拉帝纳，含起源形态）
+type GriseousOrbEffect struct {
+	BaseEffect
+}
+
+func (e *GriseousOrbEffect) GetItemID() int {
+	return valueobject.ItemGriseousOrb.ID
+}
+
+func (e *GriseousOrbEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *GriseousOrbEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	return orbBoost(self, move, []int{487, 10007}, valueobject.TypeDragon, valueobject.TypeGhost)
+}
+
+// SoulDewEffect 心之水滴（拉帝亚斯、拉帝欧斯特攻特防x1.5）
+type SoulDewEffect struct {
+	BaseEffect
+}
+
+func (e *SoulDewEffect) GetItemID() int {
+	return valueobject.ItemSoulDew.ID
+}
+
+func (e *SoulDewEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+// isLati 是否为拉帝亚斯或拉帝欧斯
+func (e *SoulDewEffect) isLati(self Battler) bool {
+	return self.GetPokemonID() == 380 || self.GetPokemonID() == 381
+}
+
+func (e *SoulDewEffect) OnDamageCalcAttacker(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if e.isLati(self) && move.GetCategory() == "special" {
+		mod := NewDamageModifier()
+		mod.AttackMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+func (e *SoulDewEffect) OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if e.isLati(self) && move.GetCategory() == "special" {
+		mod := NewDamageModifier()
+		mod.DefenseMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+// ============================================
+// 计算修正类（防御方）
+// ============================================
+
+// AssaultVestEffect 突击背心
+type AssaultVestEffect struct {
+	BaseEffect
+}
+
+func (e *AssaultVestEffect) GetItemID() int {
+	return valueobject.ItemAssaultVest.ID
+}
+
+func (e *AssaultVestEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc, TriggerOnMoveSelect}
+}
+
+func (e *AssaultVestEffect) OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetCategory() == "special" {
+		mod := NewDamageModifier()
+		mod.DefenseMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+func (e *AssaultVestEffect) OnMoveSelect(self Battler, move Move) *SelectResult {
+	if move.GetCategory() == "status" {
+		return &SelectResult{
+			Blocked: true,
+			Reason:  "携带突击背心时无法使用变化招式",
+		}
+	}
+	return nil
+}
+
+// EvioliteEffect 进化奇石（未完全进化的宝可梦双防x1.5）
+type EvioliteEffect struct {
+	BaseEffect
+}
+
+func (e *EvioliteEffect) GetItemID() int {
+	return valueobject.ItemEviolite.ID
+}
+
+func (e *EvioliteEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc}
+}
+
+func (e *EvioliteEffect) OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if self.CanEvolve() && move.GetCategory() != "status" {
+		mod := NewDamageModifier()
+		mod.DefenseMod = 1.5
+		return mod
+	}
+	return nil
+}
+
+// AirBalloonEffect 气球（免疫地面招式，被攻击后破裂）
+type AirBalloonEffect struct {
+	BaseEffect
+}
+
+func (e *AirBalloonEffect) GetItemID() int {
+	return valueobject.ItemAirBalloon.ID
+}
+
+func (e *AirBalloonEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnDamageCalc, TriggerOnBeingHit}
+}
+
+func (e *AirBalloonEffect) OnDamageCalcDefender(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	if move.GetType() == valueobject.TypeGround && move.GetCategory() != "status" {
+		mod := NewDamageModifier()
+		mod.Immune = true
+		return mod
+	}
+	return nil
+}
+
+func (e *AirBalloonEffect) OnBeingHit(self Battler, attacker Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	if damage <= 0 {
+		return nil
+	}
+	return &HitResult{
+		Messages: []string{"🎈 " + self.GetName() + " 的气球破裂了！"},
+		Consume:  true,
+	}
+}
+
+// ============================================
+// 速度修正类
+// ============================================
+
+// IronBallEffect 黑铁球
+type IronBallEffect struct {
+	BaseEffect
+}
+
+func (e *IronBallEffect) GetItemID() int {
+	return valueobject.ItemIronBall.ID
+}
+
+func (e *IronBallEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnSpeedCalc}
+}
+
+func (e *IronBallEffect) OnSpeedCalc(self Battler, ctx *BattleContext) float64 {
+	return 0.5
+}
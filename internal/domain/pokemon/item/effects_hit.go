@@ -0,0 +1,36 @@
+package item
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 受击触发类
+// ============================================
+
+// RockyHelmetEffect 凸凸头盔
+type RockyHelmetEffect struct {
+	BaseEffect
+}
+
+func (e *RockyHelmetEffect) GetItemID() int {
+	return valueobject.ItemRockyHelmet.ID
+}
+
+func (e *RockyHelmetEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnBeingHit}
+}
+
+func (e *RockyHelmetEffect) OnBeingHit(self Battler, attacker Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	if !move.IsContact() || !attacker.IsAlive() {
+		return nil
+	}
+	recoil := attacker.GetMaxHP() / 6
+	if recoil < 1 {
+		recoil = 1
+	}
+	return &HitResult{
+		Messages:     []string{"⛑️ " + attacker.GetName() + " 受到了凸凸头盔的伤害！"},
+		RecoilDamage: recoil,
+	}
+}
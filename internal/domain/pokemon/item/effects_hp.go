@@ -0,0 +1,84 @@
+package item
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// HP阈值/致命伤害类
+// ============================================
+
+// SitrusBerryEffect 文柚果（HP降至一半以下时回复 1/4）
+type SitrusBerryEffect struct {
+	BaseEffect
+}
+
+func (e *SitrusBerryEffect) GetItemID() int {
+	return valueobject.ItemSitrusBerry.ID
+}
+
+func (e *SitrusBerryEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnHPThreshold}
+}
+
+func (e *SitrusBerryEffect) OnHPThreshold(self Battler, ctx *BattleContext) *TurnEndResult {
+	if self.GetCurrentHP()*2 > self.GetMaxHP() {
+		return nil
+	}
+	return &TurnEndResult{
+		Messages:   []string{"🍊 " + self.GetName() + " 吃掉了文柚果，回复了HP！"},
+		HealAmount: self.GetMaxHP() / 4,
+		Consume:    true,
+	}
+}
+
+// FocusSashEffect 气势披带（HP全满时受到致命伤害必定留下 1 HP）
+type FocusSashEffect struct {
+	BaseEffect
+}
+
+func (e *FocusSashEffect) GetItemID() int {
+	return valueobject.ItemFocusSash.ID
+}
+
+func (e *FocusSashEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnLethalDamage}
+}
+
+func (e *FocusSashEffect) OnLethalDamage(self Battler, damage int, ctx *BattleContext) *SurviveResult {
+	if self.GetCurrentHP() < self.GetMaxHP() {
+		return nil
+	}
+	return &SurviveResult{
+		Survive: true,
+		Message: "🎗️ " + self.GetName() + " 用气势披带撑住了！",
+		Consume: true,
+	}
+}
+
+// FocusBandEffect 气势头带（10% 几率留下 1 HP，不消耗）
+type FocusBandEffect struct {
+	BaseEffect
+}
+
+func (e *FocusBandEffect) GetItemID() int {
+	return valueobject.ItemFocusBand.ID
+}
+
+func (e *FocusBandEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnLethalDamage}
+}
+
+func (e *FocusBandEffect) OnLethalDamage(self Battler, damage int, ctx *BattleContext) *SurviveResult {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if r.Intn(100) >= 10 {
+		return nil
+	}
+	return &SurviveResult{
+		Survive: true,
+		Message: "🎗️ " + self.GetName() + " 用气势头带撑住了！",
+	}
+}
@@ -0,0 +1,76 @@
+package item
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// ============================================
+// 回合结束类
+// ============================================
+
+// LeftoversEffect 吃剩的东西
+type LeftoversEffect struct {
+	BaseEffect
+}
+
+func (e *LeftoversEffect) GetItemID() int {
+	return valueobject.ItemLeftovers.ID
+}
+
+func (e *LeftoversEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnTurnEnd}
+}
+
+func (e *LeftoversEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
+	if self.GetCurrentHP() >= self.GetMaxHP() {
+		return nil
+	}
+	heal := self.GetMaxHP() / 16
+	if heal < 1 {
+		heal = 1
+	}
+	return &TurnEndResult{
+		Messages:   []string{"🍎 " + self.GetName() + " 通过吃剩的东西回复了HP！"},
+		HealAmount: heal,
+	}
+}
+
+// BlackSludgeEffect 黑色污泥（毒属性回复 1/16，其他属性损失 1/8）
+type BlackSludgeEffect struct {
+	BaseEffect
+}
+
+func (e *BlackSludgeEffect) GetItemID() int {
+	return valueobject.ItemBlackSludge.ID
+}
+
+func (e *BlackSludgeEffect) GetTriggers() []TriggerType {
+	return []TriggerType{TriggerOnTurnEnd}
+}
+
+func (e *BlackSludgeEffect) OnTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
+	for _, t := range self.GetTypes() {
+		if t == valueobject.TypePoison {
+			if self.GetCurrentHP() >= self.GetMaxHP() {
+				return nil
+			}
+			heal := self.GetMaxHP() / 16
+			if heal < 1 {
+				heal = 1
+			}
+			return &TurnEndResult{
+				Messages:   []string{"🧪 " + self.GetName() + " 通过黑色污泥回复了HP！"},
+				HealAmount: heal,
+			}
+		}
+	}
+
+	damage := self.GetMaxHP() / 8
+	if damage < 1 {
+		damage = 1
+	}
+	return &TurnEndResult{
+		Messages:     []string{"🧪 " + self.GetName() + " 受到了黑色污泥的伤害！"},
+		DamageAmount: damage,
+	}
+}
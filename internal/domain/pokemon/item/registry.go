@@ -0,0 +1,141 @@
+package item
+
+import (
+	"sync"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// Registry 道具效果注册表
+type Registry struct {
+	effects map[int]Effect
+	mu      sync.RWMutex
+}
+
+var (
+	globalRegistry *Registry
+	once           sync.Once
+)
+
+// GetRegistry 获取全局注册表
+func GetRegistry() *Registry {
+	once.Do(func() {
+		globalRegistry = &Registry{
+			effects: make(map[int]Effect),
+		}
+		// 注册所有道具效果
+		registerAllEffects(globalRegistry)
+	})
+	return globalRegistry
+}
+
+// Register 注册道具效果
+func (r *Registry) Register(effect Effect) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.effects[effect.GetItemID()] = effect
+}
+
+// Get 获取道具效果
+func (r *Registry) Get(itemID int) Effect {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.effects[itemID]
+}
+
+// Has 检查是否有道具效果
+func (r *Registry) Has(itemID int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.effects[itemID]
+	return ok
+}
+
+// GetAll 获取所有已注册的道具效果
+func (r *Registry) GetAll() []Effect {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]Effect, 0, len(r.effects))
+	for _, e := range r.effects {
+		result = append(result, e)
+	}
+	return result
+}
+
+// registerAllEffects 注册所有道具效果
+func registerAllEffects(r *Registry) {
+	// ============================================
+	// 讲究系列
+	// ============================================
+	r.Register(&ChoiceBandEffect{})  // 220 讲究头带
+	r.Register(&ChoiceSpecsEffect{}) // 297 讲究眼镜
+	r.Register(&ChoiceScarfEffect{}) // 287 讲究围巾
+
+	// ============================================
+	// 计算修正类（攻击方）
+	// ============================================
+	r.Register(&LifeOrbEffect{})     // 270 生命宝珠
+	r.Register(&ExpertBeltEffect{})  // 268 达人带
+	r.Register(&MuscleBandEffect{})  // 266 力量头带
+	r.Register(&WiseGlassesEffect{}) // 267 博识眼镜
+	r.Register(&MetronomeEffect{})   // 277 节拍器
+
+	// ============================================
+	// 属性强化类
+	// ============================================
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostFire, valueobject.TypeFire))       // 271 木炭
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostWater, valueobject.TypeWater))     // 243 神秘水滴
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostElec, valueobject.TypeElectric))   // 242 磁铁
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostGrass, valueobject.TypeGrass))     // 237 奇迹种子
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostIce, valueobject.TypeIce))         // 238 不融冰
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostFight, valueobject.TypeFighting))  // 241 黑带
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostPoison, valueobject.TypePoison))   // 245 毒针
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostGround, valueobject.TypeGround))   // 247 柔软沙子
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostFlying, valueobject.TypeFlying))   // 244 锐利鸟嘴
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostPsychic, valueobject.TypePsychic)) // 248 弯曲汤匙
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostBug, valueobject.TypeBug))         // 246 银粉
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostRock, valueobject.TypeRock))       // 249 硬石头
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostGhost, valueobject.TypeGhost))     // 250 诅咒护符
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostDragon, valueobject.TypeDragon))   // 252 龙之牙
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostDark, valueobject.TypeDark))       // 251 黑色眼镜
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostSteel, valueobject.TypeSteel))     // 253 金属膜
+	r.Register(newTypeBoost(valueobject.ItemTypeBoostFairy, valueobject.TypeFairy))     // 644 妖精羽毛
+
+	// ============================================
+	// 宝珠系列
+	// ============================================
+	r.Register(&AdamantOrbEffect{})  // 135 金刚宝珠
+	r.Register(&LustrousOrbEffect{}) // 136 白玉宝珠
+	r.Register(&GriseousOrbEffect{}) // 112 白金宝珠
+	r.Register(&SoulDewEffect{})     // 225 心之水滴
+
+	// ============================================
+	// 计算修正类（防御方）
+	// ============================================
+	r.Register(&AssaultVestEffect{}) // 640 突击背心
+	r.Register(&EvioliteEffect{})    // 538 进化奇石
+	r.Register(&AirBalloonEffect{})  // 541 气球
+
+	// ============================================
+	// 速度修正类
+	// ============================================
+	r.Register(&IronBallEffect{}) // 278 黑铁球
+
+	// ============================================
+	// 受击触发类
+	// ============================================
+	r.Register(&RockyHelmetEffect{}) // 540 凸凸头盔
+
+	// ============================================
+	// 回合结束类
+	// ============================================
+	r.Register(&LeftoversEffect{})   // 234 吃剩的东西
+	r.Register(&BlackSludgeEffect{}) // 281 黑色污泥
+
+	// ============================================
+	// HP阈值/致命伤害类
+	// ============================================
+	r.Register(&SitrusBerryEffect{}) // 158 文柚果
+	r.Register(&FocusSashEffect{})   // 275 气势披带
+	r.Register(&FocusBandEffect{})   // 230 气势头带
+}
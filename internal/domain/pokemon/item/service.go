@@ -0,0 +1,166 @@
+package item
+
+import (
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// NewDamageModifier 创建默认伤害修正（与特性系统共用）
+var NewDamageModifier = ability.NewDamageModifier
+
+// Service 道具效果服务
+type Service struct {
+	registry *Registry
+}
+
+// NewService 创建道具效果服务
+func NewService() *Service {
+	return &Service{
+		registry: GetRegistry(),
+	}
+}
+
+// effectFor 获取宝可梦当前携带道具的效果（未携带、已消耗或超级石/Z纯晶时返回 nil）
+func (s *Service) effectFor(self Battler) Effect {
+	item := self.GetItem()
+	if item == nil || self.IsItemConsumed() {
+		return nil
+	}
+	// 超级石与Z纯晶使用 PokeAPI 的道具ID，由特殊机制处理
+	if item.Category == valueobject.ItemCategoryMega || item.Category == valueobject.ItemCategoryZCrystal {
+		return nil
+	}
+	return s.registry.Get(item.ID)
+}
+
+// IsChoiceItem 检查是否携带讲究系列道具
+func (s *Service) IsChoiceItem(self Battler) bool {
+	item := self.GetItem()
+	return item != nil && !self.IsItemConsumed() && item.Category == valueobject.ItemCategoryChoice
+}
+
+// ApplyAttackerDamageMods 应用攻击方道具伤害修正
+func (s *Service) ApplyAttackerDamageMods(self Battler, target Battler, move Move, ctx *BattleContext) *DamageModifier {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnDamageCalcAttacker(self, target, move, ctx)
+}
+
+// ApplyDefenderDamageMods 应用防御方道具伤害修正
+func (s *Service) ApplyDefenderDamageMods(self Battler, attacker Battler, move Move, ctx *BattleContext) *DamageModifier {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnDamageCalcDefender(self, attacker, move, ctx)
+}
+
+// TriggerBeingHit 触发受击道具
+func (s *Service) TriggerBeingHit(self Battler, attacker Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnBeingHit(self, attacker, move, damage, ctx)
+}
+
+// TriggerAfterAttack 触发攻击后道具
+func (s *Service) TriggerAfterAttack(self Battler, move Move, damage int, ctx *BattleContext) *HitResult {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnAfterAttack(self, move, damage, ctx)
+}
+
+// TriggerTurnEnd 触发回合结束道具
+func (s *Service) TriggerTurnEnd(self Battler, ctx *BattleContext) *TurnEndResult {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnTurnEnd(self, ctx)
+}
+
+// CheckHPThreshold 检查HP阈值道具
+func (s *Service) CheckHPThreshold(self Battler, ctx *BattleContext) *TurnEndResult {
+	if !self.IsAlive() {
+		return nil
+	}
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnHPThreshold(self, ctx)
+}
+
+// CheckLethalDamage 检查致命伤害道具
+func (s *Service) CheckLethalDamage(self Battler, damage int, ctx *BattleContext) *SurviveResult {
+	if damage < self.GetCurrentHP() {
+		return nil
+	}
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnLethalDamage(self, damage, ctx)
+}
+
+// CheckMoveSelect 检查道具是否允许选择该技能
+func (s *Service) CheckMoveSelect(self Battler, move Move) *SelectResult {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return nil
+	}
+	return effect.OnMoveSelect(self, move)
+}
+
+// GetEffectiveSpeed 获取包含道具效果的有效速度
+func (s *Service) GetEffectiveSpeed(self Battler, baseSpeed int, ctx *BattleContext) int {
+	effect := s.effectFor(self)
+	if effect == nil {
+		return baseSpeed
+	}
+	multiplier := effect.OnSpeedCalc(self, ctx)
+	if multiplier <= 0 {
+		return baseSpeed
+	}
+	return int(float64(baseSpeed) * multiplier)
+}
+
+// CalculateDamageWithItems 合并攻击方与防御方道具的伤害修正
+// 返回合并后的修正（各倍率相乘）与消息列表；防御方道具免疫时返回的修正 Immune 为 true
+func (s *Service) CalculateDamageWithItems(
+	attacker Battler,
+	defender Battler,
+	move Move,
+	ctx *BattleContext,
+) (mod *DamageModifier, messages []string) {
+	mod = NewDamageModifier()
+	messages = make([]string, 0)
+
+	// 防御方道具（免疫优先判定）
+	defItemMod := s.ApplyDefenderDamageMods(defender, attacker, move, ctx)
+	if defItemMod != nil && defItemMod.Immune {
+		mod.Immune = true
+		messages = append(messages, "🎈 "+defender.GetName()+" 因"+defender.GetItem().Name+"而没有受到攻击！")
+		return
+	}
+
+	// 攻击方道具
+	atkItemMod := s.ApplyAttackerDamageMods(attacker, defender, move, ctx)
+	if atkItemMod != nil {
+		mod.PowerMod *= atkItemMod.PowerMod
+		mod.AttackMod *= atkItemMod.AttackMod
+		mod.DamageMod *= atkItemMod.DamageMod
+	}
+
+	if defItemMod != nil {
+		mod.DefenseMod *= defItemMod.DefenseMod
+		mod.DamageMod *= defItemMod.DamageMod
+	}
+
+	return
+}
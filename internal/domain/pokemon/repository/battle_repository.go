@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/user/dcminigames/internal/domain/pokemon/entity"
+
+// BattleRepository 对战仓储
+type BattleRepository interface {
+	// Save 保存对战（以频道ID为键，已存在时覆盖）
+	Save(battle *entity.Battle) error
+	// FindByChannelID 通过频道ID查找对战
+	FindByChannelID(channelID string) (*entity.Battle, error)
+	// FindByPlayerID 通过玩家ID查找对战
+	FindByPlayerID(playerID string) (*entity.Battle, error)
+	// Delete 删除对战
+	Delete(channelID string) error
+	// Exists 检查对战是否存在
+	Exists(channelID string) bool
+}
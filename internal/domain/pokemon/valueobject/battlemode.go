@@ -68,10 +68,11 @@ type BattleConfig struct {
 	TurnTimeLimit  int             // 每回合时间限制（秒）
 	TotalTimeLimit int             // 总时间限制（秒）
 	ItemClause     bool            // 道具条款（同队不能重复道具）
-	SpeciesClause  bool            // 种族条款（同队不能重复宝可梦）
+	SpeciesClause  bool            // 种族条款（同队不能重复全国图鉴编号相同的宝可梦）
 	SleepClause    bool            // 睡眠条款
 	OHKOClause     bool            // 一击必杀条款
 	EvasionClause  bool            // 闪避条款
+	Learnset       LearnsetFormat  // 默认的可学技能范围（空=全国图鉴）
 }
 
 // DefaultSingleConfig 默认单打配置
@@ -149,9 +150,89 @@ func VGCConfig() *BattleConfig {
 		SleepClause:    false,
 		OHKOClause:     false,
 		EvasionClause:  false,
+		Learnset:       LearnsetSV,
 	}
 }
 
+// 规则预设ID（用于创建对战时选择）
+const (
+	RulesetQuick  = "quick"  // 快速对战
+	RulesetSingle = "single" // 平坦单打
+	RulesetDouble = "double" // 平坦双打
+	RulesetVGC    = "vgc"    // VGC
+)
+
+// RulesetIDs 可选择的规则预设（按显示顺序）
+var RulesetIDs = []string{RulesetSingle, RulesetDouble, RulesetVGC}
+
+// ConfigForRuleset 根据规则预设ID创建对战配置，未知ID返回 nil
+func ConfigForRuleset(id string) *BattleConfig {
+	switch id {
+	case RulesetQuick:
+		return QuickBattleConfig()
+	case RulesetSingle:
+		return DefaultSingleConfig()
+	case RulesetDouble:
+		return DefaultDoubleConfig()
+	case RulesetVGC:
+		return VGCConfig()
+	}
+	return nil
+}
+
+// RulesetDisplayName 获取规则预设显示名称
+func RulesetDisplayName(id string) string {
+	names := map[string]string{
+		RulesetQuick:  "快速对战",
+		RulesetSingle: "平坦单打",
+		RulesetDouble: "平坦双打",
+		RulesetVGC:    "VGC 双打",
+	}
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
+
+// RulesetID 根据对战配置推断对应的规则预设ID（用于匹配配队预设的适用规则）
+func (c *BattleConfig) RulesetID() string {
+	switch {
+	case c.Rule == RuleVGC:
+		return RulesetVGC
+	case c.Rule == RuleFlat && c.Mode == ModeDouble:
+		return RulesetDouble
+	case c.Rule == RuleFlat:
+		return RulesetSingle
+	}
+	return RulesetQuick
+}
+
+// AllowsGimmick 是否允许使用指定的特殊系统
+func (c *BattleConfig) AllowsGimmick(g GimmickSystem) bool {
+	return c.Gimmick == GimmickAll || c.Gimmick == g
+}
+
+// ClauseNames 获取已启用的条款名称
+func (c *BattleConfig) ClauseNames() []string {
+	var clauses []string
+	if c.SpeciesClause {
+		clauses = append(clauses, "种族条款")
+	}
+	if c.ItemClause {
+		clauses = append(clauses, "道具条款")
+	}
+	if c.SleepClause {
+		clauses = append(clauses, "睡眠条款")
+	}
+	if c.OHKOClause {
+		clauses = append(clauses, "一击必杀条款")
+	}
+	if c.EvasionClause {
+		clauses = append(clauses, "闪避条款")
+	}
+	return clauses
+}
+
 // GetModeDisplayName 获取模式显示名称
 func (m BattleMode) DisplayName() string {
 	names := map[BattleMode]string{
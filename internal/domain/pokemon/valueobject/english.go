@@ -0,0 +1,111 @@
+package valueobject
+
+import "strings"
+
+// ToID 将英文名称转换为 Showdown 风格的标识（小写，只保留字母和数字）
+// 例如 "U-turn" -> "uturn"，"Mr. Mime" -> "mrmime"
+func ToID(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// natureEnglishNames 性格英文名称
+var natureEnglishNames = map[Nature]string{
+	NatureHardy: "Hardy", NatureLonely: "Lonely", NatureBrave: "Brave", NatureAdamant: "Adamant", NatureNaughty: "Naughty",
+	NatureBold: "Bold", NatureDocile: "Docile", NatureRelaxed: "Relaxed", NatureImpish: "Impish", NatureLax: "Lax",
+	NatureTimid: "Timid", NatureHasty: "Hasty", NatureSerious: "Serious", NatureJolly: "Jolly", NatureNaive: "Naive",
+	NatureModest: "Modest", NatureMild: "Mild", NatureQuiet: "Quiet", NatureBashful: "Bashful", NatureRash: "Rash",
+	NatureCalm: "Calm", NatureGentle: "Gentle", NatureSassy: "Sassy", NatureCareful: "Careful", NatureQuirky: "Quirky",
+}
+
+// EnglishName 获取性格英文名称
+func (n Nature) EnglishName() string {
+	return natureEnglishNames[n]
+}
+
+// GetNatureByEnglishName 通过英文名称获取性格
+func GetNatureByEnglishName(name string) (Nature, bool) {
+	id := ToID(name)
+	for nature, english := range natureEnglishNames {
+		if ToID(english) == id {
+			return nature, true
+		}
+	}
+	return "", false
+}
+
+// typeEnglishNames 属性英文名称
+var typeEnglishNames = map[PokeType]string{
+	TypeNormal: "Normal", TypeFire: "Fire", TypeWater: "Water", TypeElectric: "Electric",
+	TypeGrass: "Grass", TypeIce: "Ice", TypeFighting: "Fighting", TypePoison: "Poison",
+	TypeGround: "Ground", TypeFlying: "Flying", TypePsychic: "Psychic", TypeBug: "Bug",
+	TypeRock: "Rock", TypeGhost: "Ghost", TypeDragon: "Dragon", TypeDark: "Dark",
+	TypeSteel: "Steel", TypeFairy: "Fairy",
+}
+
+// EnglishName 获取属性英文名称
+func (t PokeType) EnglishName() string {
+	return typeEnglishNames[t]
+}
+
+// GetTypeByEnglishName 通过英文名称获取属性
+func GetTypeByEnglishName(name string) (PokeType, bool) {
+	id := ToID(name)
+	for pokeType, english := range typeEnglishNames {
+		if ToID(english) == id {
+			return pokeType, true
+		}
+	}
+	return "", false
+}
+
+// itemEnglishNames 携带道具英文名称（超级石与Z纯晶的英文名称由 PokeAPI 数据提供）
+var itemEnglishNames = map[int]string{
+	ItemChoiceBand.ID: "Choice Band", ItemChoiceSpecs.ID: "Choice Specs", ItemChoiceScarf.ID: "Choice Scarf",
+
+	ItemLifeOrb.ID: "Life Orb", ItemExpertBelt.ID: "Expert Belt", ItemMuscleBand.ID: "Muscle Band",
+	ItemWiseGlasses.ID: "Wise Glasses", ItemMetronome.ID: "Metronome",
+
+	ItemFocusSash.ID: "Focus Sash", ItemFocusBand.ID: "Focus Band", ItemAssaultVest.ID: "Assault Vest",
+	ItemEviolite.ID: "Eviolite", ItemRockyHelmet.ID: "Rocky Helmet", ItemLeftovers.ID: "Leftovers",
+	ItemBlackSludge.ID: "Black Sludge", ItemSitrusBerry.ID: "Sitrus Berry",
+
+	ItemTypeBoostFire.ID: "Charcoal", ItemTypeBoostWater.ID: "Mystic Water", ItemTypeBoostElec.ID: "Magnet",
+	ItemTypeBoostGrass.ID: "Miracle Seed", ItemTypeBoostIce.ID: "Never-Melt Ice", ItemTypeBoostFight.ID: "Black Belt",
+	ItemTypeBoostPoison.ID: "Poison Barb", ItemTypeBoostGround.ID: "Soft Sand", ItemTypeBoostFlying.ID: "Sharp Beak",
+	ItemTypeBoostPsychic.ID: "Twisted Spoon", ItemTypeBoostBug.ID: "Silver Powder", ItemTypeBoostRock.ID: "Hard Stone",
+	ItemTypeBoostGhost.ID: "Spell Tag", ItemTypeBoostDragon.ID: "Dragon Fang", ItemTypeBoostDark.ID: "Black Glasses",
+	ItemTypeBoostSteel.ID: "Metal Coat", ItemTypeBoostFairy.ID: "Fairy Feather",
+
+	ItemQuickClaw.ID: "Quick Claw", ItemIronBall.ID: "Iron Ball", ItemLaggingTail.ID: "Lagging Tail",
+
+	ItemHeavyDutyBoots.ID: "Heavy-Duty Boots", ItemSafetyGoggles.ID: "Safety Goggles", ItemAirBalloon.ID: "Air Balloon",
+	ItemRedCard.ID: "Red Card", ItemEjectButton.ID: "Eject Button", ItemShedShell.ID: "Shed Shell",
+
+	ItemHeatRock.ID: "Heat Rock", ItemDampRock.ID: "Damp Rock", ItemSmoothRock.ID: "Smooth Rock",
+	ItemIcyRock.ID: "Icy Rock",
+
+	ItemAdamantOrb.ID: "Adamant Orb", ItemLustrousOrb.ID: "Lustrous Orb", ItemGriseousOrb.ID: "Griseous Orb",
+	ItemSoulDew.ID: "Soul Dew", ItemRedOrb.ID: "Red Orb", ItemBlueOrb.ID: "Blue Orb",
+}
+
+// ItemEnglishName 获取携带道具英文名称（不存在时返回空字符串）
+func ItemEnglishName(id int) string {
+	return itemEnglishNames[id]
+}
+
+// GetItemByEnglishName 通过英文名称获取携带道具
+func GetItemByEnglishName(name string) *Item {
+	id := ToID(name)
+	for itemID, english := range itemEnglishNames {
+		if ToID(english) == id {
+			return GetItemByID(itemID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package valueobject
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EvolutionTrigger 进化方式（PokeAPI evolution_trigger_id）
+type EvolutionTrigger int
+
+const (
+	EvolutionLevelUp EvolutionTrigger = 1 // 升级
+	EvolutionTrade   EvolutionTrigger = 2 // 通信交换
+	EvolutionUseItem EvolutionTrigger = 3 // 使用道具
+)
+
+// Evolution 宝可梦由上一阶段进化而来的方式
+type Evolution struct {
+	SpeciesID    int              // 进化后的种类ID
+	Name         string           // 进化后的宝可梦名称
+	Trigger      EvolutionTrigger // 进化方式（0 表示进化链的起点）
+	MinLevel     int              // 最低等级（0 表示不限）
+	Item         string           // 使用或携带的道具名称
+	MinHappiness int              // 最低亲密度（0 表示不限）
+}
+
+// Condition 进化条件描述（如"Lv.16"、"使用火之石"）
+func (e Evolution) Condition() string {
+	switch e.Trigger {
+	case 0:
+		return ""
+	case EvolutionLevelUp:
+		switch {
+		case e.MinLevel > 0:
+			return fmt.Sprintf("Lv.%d", e.MinLevel)
+		case e.MinHappiness > 0:
+			return "亲密度升级"
+		case e.Item != "":
+			return "携带" + e.Item + "升级"
+		}
+		return "升级"
+	case EvolutionTrade:
+		if e.Item != "" {
+			return "携带" + e.Item + "通信交换"
+		}
+		return "通信交换"
+	case EvolutionUseItem:
+		if e.Item != "" {
+			return "使用" + e.Item
+		}
+		return "使用道具"
+	}
+	return "特殊条件"
+}
+
+// EvolutionChain 进化链（按进化阶段排列，同一阶段可能有多个分支）
+type EvolutionChain [][]Evolution
+
+// String 进化链描述（如"小火龙 → 火恐龙(Lv.16) → 喷火龙(Lv.36)"）
+func (c EvolutionChain) String() string {
+	stages := make([]string, 0, len(c))
+	for _, stage := range c {
+		names := make([]string, 0, len(stage))
+		for _, evolution := range stage {
+			if condition := evolution.Condition(); condition != "" {
+				names = append(names, fmt.Sprintf("%s(%s)", evolution.Name, condition))
+			} else {
+				names = append(names, evolution.Name)
+			}
+		}
+		stages = append(stages, strings.Join(names, " / "))
+	}
+	return strings.Join(stages, " → ")
+}
@@ -1,5 +1,7 @@
 package valueobject
 
+import "strings"
+
 // Item 道具
 type Item struct {
 	ID          int
@@ -79,34 +81,51 @@ var (
 	ItemEjectButton    = Item{547, "逃脱按键", "被攻击时可交换", ItemCategoryHeld}
 	ItemShedShell      = Item{295, "脱壳", "可无视束缚交换", ItemCategoryHeld}
 
+	// 天气岩石
+	ItemHeatRock   = Item{284, "炽热岩石", "晴天持续8回合", ItemCategoryHeld}
+	ItemDampRock   = Item{285, "潮湿岩石", "雨天持续8回合", ItemCategoryHeld}
+	ItemSmoothRock = Item{283, "沙沙岩石", "沙暴持续8回合", ItemCategoryHeld}
+	ItemIcyRock    = Item{282, "冰冷岩石", "冰雹与下雪持续8回合", ItemCategoryHeld}
+
 	// 宝珠系列
 	ItemAdamantOrb  = Item{135, "金刚宝珠", "帝牙卢卡龙钢招式x1.2", ItemCategoryOrb}
 	ItemLustrousOrb = Item{136, "白玉宝珠", "帕路奇亚龙水招式x1.2", ItemCategoryOrb}
 	ItemGriseousOrb = Item{112, "白金宝珠", "骑拉帝纳龙幽灵招式x1.2", ItemCategoryOrb}
 	ItemSoulDew     = Item{225, "心之水滴", "拉帝特攻特防x1.5", ItemCategoryOrb}
+	ItemRedOrb      = Item{534, "朱红色宝珠", "固拉多出场时原始回归", ItemCategoryOrb}
+	ItemBlueOrb     = Item{535, "靛蓝色宝珠", "盖欧卡出场时原始回归", ItemCategoryOrb}
 )
 
 func ite(n int) int { return n }
 
-// ItemMap 道具ID映射
-var ItemMap = map[int]Item{
-	220: ItemChoiceBand,
-	297: ItemChoiceSpecs,
-	287: ItemChoiceScarf,
-	270: ItemLifeOrb,
-	268: ItemExpertBelt,
-	275: ItemFocusSash,
-	640: ItemAssaultVest,
-	538: ItemEviolite,
-	540: ItemRockyHelmet,
-	234: ItemLeftovers,
-	281: ItemBlackSludge,
-	158: ItemSitrusBerry,
-	217: ItemQuickClaw,
-	1120: ItemHeavyDutyBoots,
-	541: ItemAirBalloon,
+// HeldItems 可供选择的全部携带道具（按展示顺序排列，不含超级石与Z纯晶）
+var HeldItems = []Item{
+	ItemChoiceBand, ItemChoiceSpecs, ItemChoiceScarf,
+	ItemLifeOrb, ItemExpertBelt, ItemMuscleBand, ItemWiseGlasses, ItemMetronome,
+	ItemFocusSash, ItemFocusBand, ItemAssaultVest, ItemEviolite,
+	ItemRockyHelmet, ItemLeftovers, ItemBlackSludge, ItemSitrusBerry,
+	ItemTypeBoostFire, ItemTypeBoostWater, ItemTypeBoostElec, ItemTypeBoostGrass,
+	ItemTypeBoostIce, ItemTypeBoostFight, ItemTypeBoostPoison, ItemTypeBoostGround,
+	ItemTypeBoostFlying, ItemTypeBoostPsychic, ItemTypeBoostBug, ItemTypeBoostRock,
+	ItemTypeBoostGhost, ItemTypeBoostDragon, ItemTypeBoostDark, ItemTypeBoostSteel,
+	ItemTypeBoostFairy,
+	ItemQuickClaw, ItemIronBall, ItemLaggingTail,
+	ItemHeavyDutyBoots, ItemSafetyGoggles, ItemAirBalloon, ItemRedCard,
+	ItemEjectButton, ItemShedShell,
+	ItemHeatRock, ItemDampRock, ItemSmoothRock, ItemIcyRock,
+	ItemAdamantOrb, ItemLustrousOrb, ItemGriseousOrb, ItemSoulDew,
+	ItemRedOrb, ItemBlueOrb,
 }
 
+// ItemMap 道具ID映射
+var ItemMap = func() map[int]Item {
+	m := make(map[int]Item, len(HeldItems))
+	for _, item := range HeldItems {
+		m[item.ID] = item
+	}
+	return m
+}()
+
 // GetItemByID 通过ID获取道具
 func GetItemByID(id int) *Item {
 	if item, ok := ItemMap[id]; ok {
@@ -125,6 +144,38 @@ func GetItemByName(name string) *Item {
 	return nil
 }
 
+// GetItemsByCategory 获取指定分类的携带道具（分类为空时返回全部）
+func GetItemsByCategory(categories ...ItemCategory) []Item {
+	if len(categories) == 0 {
+		return HeldItems
+	}
+	result := make([]Item, 0)
+	for _, item := range HeldItems {
+		for _, category := range categories {
+			if item.Category == category {
+				result = append(result, item)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// SearchItems 按名称或说明关键字搜索携带道具
+func SearchItems(keyword string) []Item {
+	keyword = strings.TrimSpace(keyword)
+	result := make([]Item, 0)
+	if keyword == "" {
+		return result
+	}
+	for _, item := range HeldItems {
+		if strings.Contains(item.Name, keyword) || strings.Contains(item.Description, keyword) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // CommonHeldItems 常用携带道具列表
 var CommonHeldItems = []Item{
 	ItemChoiceBand, ItemChoiceSpecs, ItemChoiceScarf,
@@ -133,3 +184,15 @@ var CommonHeldItems = []Item{
 	ItemRockyHelmet, ItemLeftovers, ItemBlackSludge,
 	ItemSitrusBerry, ItemHeavyDutyBoots, ItemAirBalloon,
 }
+
+// ZCrystalType 获取Z纯晶对应的属性（Z纯晶以"属性+Z"命名，如"火Z"）
+func ZCrystalType(item *Item) (PokeType, bool) {
+	if item == nil || item.Category != ItemCategoryZCrystal || !strings.HasSuffix(item.Name, "Z") {
+		return "", false
+	}
+	pokeType := PokeType(strings.TrimSuffix(item.Name, "Z"))
+	if _, ok := TypeEffectiveness[pokeType]; !ok {
+		return "", false
+	}
+	return pokeType, true
+}
@@ -0,0 +1,89 @@
+package valueobject
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LearnsetFormat 可学技能范围（决定配置技能时可以选择哪些技能）
+type LearnsetFormat string
+
+const (
+	LearnsetNational LearnsetFormat = ""   // 全国图鉴：历代任意版本可以学会的技能
+	LearnsetSV       LearnsetFormat = "sv" // 朱紫：朱紫（含零之秘宝）可以学会的技能
+)
+
+// LearnsetFormats 可选择的可学技能范围（按显示顺序）
+var LearnsetFormats = []LearnsetFormat{LearnsetNational, LearnsetSV}
+
+// DisplayName 获取可学技能范围显示名称
+func (f LearnsetFormat) DisplayName() string {
+	names := map[LearnsetFormat]string{
+		LearnsetNational: "全国图鉴",
+		LearnsetSV:       "朱紫",
+	}
+	if name, ok := names[f]; ok {
+		return name
+	}
+	return string(f)
+}
+
+// Next 获取下一个可学技能范围（按 LearnsetFormats 顺序循环）
+func (f LearnsetFormat) Next() LearnsetFormat {
+	for idx, format := range LearnsetFormats {
+		if format == f {
+			return LearnsetFormats[(idx+1)%len(LearnsetFormats)]
+		}
+	}
+	return LearnsetNational
+}
+
+// MoveLearnMethod 技能学习方式（PokeAPI pokemon_move_method_id）
+type MoveLearnMethod int
+
+const (
+	LearnLevelUp    MoveLearnMethod = 1  // 升级
+	LearnEgg        MoveLearnMethod = 2  // 遗传
+	LearnTutor      MoveLearnMethod = 3  // 教学
+	LearnMachine    MoveLearnMethod = 4  // 招式学习器
+	LearnFormChange MoveLearnMethod = 10 // 形态变化
+)
+
+// DisplayName 获取学习方式显示名称
+func (m MoveLearnMethod) DisplayName() string {
+	names := map[MoveLearnMethod]string{
+		LearnLevelUp:    "升级",
+		LearnEgg:        "遗传",
+		LearnTutor:      "教学",
+		LearnMachine:    "招式学习器",
+		LearnFormChange: "形态变化",
+	}
+	if name, ok := names[m]; ok {
+		return name
+	}
+	return "其他"
+}
+
+// MoveLearn 宝可梦学会某个技能的方式
+type MoveLearn struct {
+	MoveID  int               // 技能ID
+	Methods []MoveLearnMethod // 学习方式（按ID排序，不重复）
+	Level   int               // 升级学会的最低等级（0 表示未知或不通过升级学会）
+}
+
+// Description 学习方式描述（如"Lv.26 / 招式学习器"）
+func (l MoveLearn) Description() string {
+	parts := make([]string, 0, len(l.Methods))
+	seen := make(map[string]bool)
+	for _, method := range l.Methods {
+		name := method.DisplayName()
+		if method == LearnLevelUp && l.Level > 0 {
+			name = fmt.Sprintf("Lv.%d", l.Level)
+		}
+		if !seen[name] {
+			seen[name] = true
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,76 @@
+package valueobject
+
+// Terrain 场地类型（只影响着地的宝可梦）
+type Terrain string
+
+const (
+	TerrainNone     Terrain = ""
+	TerrainElectric Terrain = "电气场地"
+	TerrainGrassy   Terrain = "青草场地"
+	TerrainPsychic  Terrain = "精神场地"
+	TerrainMisty    Terrain = "薄雾场地"
+)
+
+// TerrainTurns 场地持续回合数
+const TerrainTurns = 5
+
+// TerrainPowerModifier 场地强化对应属性招式的威力修正
+const TerrainPowerModifier = 1.3
+
+// BoostedType 场地强化的招式属性（着地的宝可梦使用时威力提升），薄雾场地与无场地返回 false
+func (t Terrain) BoostedType() (PokeType, bool) {
+	switch t {
+	case TerrainElectric:
+		return TypeElectric, true
+	case TerrainGrassy:
+		return TypeGrass, true
+	case TerrainPsychic:
+		return TypePsychic, true
+	}
+	return "", false
+}
+
+// Icon 场地图标
+func (t Terrain) Icon() string {
+	switch t {
+	case TerrainElectric:
+		return "⚡"
+	case TerrainGrassy:
+		return "🌿"
+	case TerrainPsychic:
+		return "🔮"
+	case TerrainMisty:
+		return "🌫️"
+	}
+	return ""
+}
+
+// StartMessage 场地展开时的消息
+func (t Terrain) StartMessage() string {
+	switch t {
+	case TerrainElectric:
+		return "⚡ 脚下电光飞溅！"
+	case TerrainGrassy:
+		return "🌿 脚下青草如茵！"
+	case TerrainPsychic:
+		return "🔮 脚下传来了奇妙的感觉！"
+	case TerrainMisty:
+		return "🌫️ 脚下雾气缭绕！"
+	}
+	return ""
+}
+
+// EndMessage 场地消失时的消息
+func (t Terrain) EndMessage() string {
+	switch t {
+	case TerrainElectric:
+		return "⚡ 脚下的电光消失了。"
+	case TerrainGrassy:
+		return "🌿 脚下的青草消失了。"
+	case TerrainPsychic:
+		return "🔮 脚下的奇妙感觉消失了。"
+	case TerrainMisty:
+		return "🌫️ 脚下的雾气消失了。"
+	}
+	return ""
+}
@@ -9,11 +9,110 @@ const (
 	WeatherRain    Weather = "雨天"
 	WeatherSand    Weather = "沙暴"
 	WeatherHail    Weather = "冰雹"
+	WeatherSnow    Weather = "下雪"
 	WeatherHarshSun Weather = "大日照" // 原始固拉多
 	WeatherHeavyRain Weather = "大雨"  // 原始盖欧卡
 	WeatherStrongWinds Weather = "乱流" // 裂空座
 )
 
+// 天气持续回合数
+const (
+	WeatherTurns         = 5 // 技能与特性展开的天气
+	ExtendedWeatherTurns = 8 // 携带对应的天气岩石时
+)
+
+// WeatherBoostModifier 沙暴中岩石属性的特防、下雪中冰属性的防御提升倍率
+const WeatherBoostModifier = 1.5
+
+// IsPrimal 是否为原始天气（大日照、大雨、乱流），只能被其他原始天气覆盖，持有者离场后结束
+func (w Weather) IsPrimal() bool {
+	return w == WeatherHarshSun || w == WeatherHeavyRain || w == WeatherStrongWinds
+}
+
+// IsSunny 是否为晴天（包括大日照）
+func (w Weather) IsSunny() bool {
+	return w == WeatherSun || w == WeatherHarshSun
+}
+
+// IsRainy 是否为雨天（包括大雨）
+func (w Weather) IsRainy() bool {
+	return w == WeatherRain || w == WeatherHeavyRain
+}
+
+// IsSnowy 是否为冰雹或下雪
+func (w Weather) IsSnowy() bool {
+	return w == WeatherHail || w == WeatherSnow
+}
+
+// Icon 天气图标
+func (w Weather) Icon() string {
+	switch w {
+	case WeatherSun:
+		return "☀️"
+	case WeatherRain:
+		return "🌧️"
+	case WeatherSand:
+		return "🏜️"
+	case WeatherHail:
+		return "🌨️"
+	case WeatherSnow:
+		return "☃️"
+	case WeatherHarshSun:
+		return "🔥"
+	case WeatherHeavyRain:
+		return "⛈️"
+	case WeatherStrongWinds:
+		return "🌪️"
+	}
+	return ""
+}
+
+// StartMessage 天气开始时的消息
+func (w Weather) StartMessage() string {
+	switch w {
+	case WeatherSun:
+		return "☀️ 日照变强了！"
+	case WeatherRain:
+		return "🌧️ 开始下雨了！"
+	case WeatherSand:
+		return "🏜️ 开始刮沙暴了！"
+	case WeatherHail:
+		return "🌨️ 开始下冰雹了！"
+	case WeatherSnow:
+		return "☃️ 开始下雪了！"
+	case WeatherHarshSun:
+		return "🔥 日照变得非常强了！"
+	case WeatherHeavyRain:
+		return "⛈️ 开始下起了大雨！"
+	case WeatherStrongWinds:
+		return "🌪️ 吹起了神秘的乱流！"
+	}
+	return ""
+}
+
+// EndMessage 天气结束时的消息
+func (w Weather) EndMessage() string {
+	switch w {
+	case WeatherSun:
+		return "☀️ 日照复原了。"
+	case WeatherRain:
+		return "🌧️ 雨停了。"
+	case WeatherSand:
+		return "🏜️ 沙暴停止了。"
+	case WeatherHail:
+		return "🌨️ 冰雹停止了。"
+	case WeatherSnow:
+		return "☃️ 雪停了。"
+	case WeatherHarshSun:
+		return "🔥 日照复原了。"
+	case WeatherHeavyRain:
+		return "⛈️ 大雨停了。"
+	case WeatherStrongWinds:
+		return "🌪️ 神秘的乱流停止了。"
+	}
+	return ""
+}
+
 // WeatherState 天气状态
 type WeatherState struct {
 	Current   Weather
@@ -28,16 +127,24 @@ func NewWeatherState() *WeatherState {
 	}
 }
 
-// SetWeather 设置天气
-func (w *WeatherState) SetWeather(weather Weather, turns int) {
-	// 原始天气无法被覆盖
-	if w.Current == WeatherHarshSun || w.Current == WeatherHeavyRain || w.Current == WeatherStrongWinds {
-		if weather != WeatherNone {
-			return
-		}
+// SetWeather 设置天气，天气未改变时返回 false
+// 原始天气只能被其他原始天气覆盖（或在持有者离场时清除）
+func (w *WeatherState) SetWeather(weather Weather, turns int) bool {
+	if w.Current == weather {
+		return false
+	}
+	if w.Current.IsPrimal() && weather != WeatherNone && !weather.IsPrimal() {
+		return false
 	}
 	w.Current = weather
 	w.TurnsLeft = turns
+	return true
+}
+
+// Clear 清除天气
+func (w *WeatherState) Clear() {
+	w.Current = WeatherNone
+	w.TurnsLeft = 0
 }
 
 // Tick 天气回合流逝
@@ -59,12 +166,12 @@ func (w *WeatherState) IsActive() bool {
 
 // IsSunny 是否晴天
 func (w *WeatherState) IsSunny() bool {
-	return w.Current == WeatherSun || w.Current == WeatherHarshSun
+	return w.Current.IsSunny()
 }
 
 // IsRainy 是否雨天
 func (w *WeatherState) IsRainy() bool {
-	return w.Current == WeatherRain || w.Current == WeatherHeavyRain
+	return w.Current.IsRainy()
 }
 
 // IsSandy 是否沙暴
@@ -77,6 +184,11 @@ func (w *WeatherState) IsHailing() bool {
 	return w.Current == WeatherHail
 }
 
+// IsSnowy 是否冰雹或下雪
+func (w *WeatherState) IsSnowy() bool {
+	return w.Current.IsSnowy()
+}
+
 // GetFireModifier 获取火系招式修正
 func (w *WeatherState) GetFireModifier() float64 {
 	switch w.Current {
@@ -105,6 +217,31 @@ func (w *WeatherState) GetWaterModifier() float64 {
 	}
 }
 
+// GetDamageModifier 获取天气对招式伤害的修正（0 表示招式在原始天气中失效）
+func (w *WeatherState) GetDamageModifier(moveType PokeType) float64 {
+	switch moveType {
+	case TypeFire:
+		return w.GetFireModifier()
+	case TypeWater:
+		return w.GetWaterModifier()
+	}
+	return 1.0
+}
+
+// GetDefenseModifier 获取天气对防守方防御/特防的修正
+// 沙暴中岩石属性的特防、下雪中冰属性的防御提升 1.5 倍
+func (w *WeatherState) GetDefenseModifier(defenderTypes []PokeType, physical bool) float64 {
+	for _, t := range defenderTypes {
+		if !physical && w.IsSandy() && t == TypeRock {
+			return WeatherBoostModifier
+		}
+		if physical && w.Current == WeatherSnow && t == TypeIce {
+			return WeatherBoostModifier
+		}
+	}
+	return 1.0
+}
+
 // GetWeatherDamageTypes 获取会受到天气伤害的属性（需要排除的）
 func (w *WeatherState) GetWeatherDamageExemptTypes() []PokeType {
 	switch w.Current {
@@ -133,6 +270,8 @@ func (w *WeatherState) GetWeatherName() string {
 		return "🏜️ 沙暴肆虐"
 	case WeatherHail:
 		return "❄️ 冰雹来袭"
+	case WeatherSnow:
+		return "☃️ 下雪"
 	case WeatherHarshSun:
 		return "🔥 强烈的日照"
 	case WeatherHeavyRain:
@@ -0,0 +1,38 @@
+package entity
+
+import "encoding/json"
+
+// gameFields 与 Game 字段相同但不带方法的类型（避免序列化时递归调用 MarshalJSON）
+type gameFields Game
+
+// gameSnapshot 游戏的序列化结构，胜者保存为玩家ID（恢复时重新指向 Players 中的玩家）
+type gameSnapshot struct {
+	gameFields
+	Winner string `json:",omitempty"`
+}
+
+// MarshalJSON 序列化游戏（用于持久化）
+func (g *Game) MarshalJSON() ([]byte, error) {
+	snapshot := gameSnapshot{gameFields: gameFields(*g)}
+	if g.Winner != nil {
+		snapshot.Winner = g.Winner.ID
+	}
+	return json.Marshal(snapshot)
+}
+
+// UnmarshalJSON 从持久化数据恢复游戏
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var snapshot gameSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	*g = Game(snapshot.gameFields)
+	if snapshot.Winner != "" {
+		for _, player := range g.Players {
+			if player.ID == snapshot.Winner {
+				g.Winner = player
+			}
+		}
+	}
+	return nil
+}
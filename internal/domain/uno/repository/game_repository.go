@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/user/dcminigames/internal/domain/uno/entity"
+
+// GameRepository UNO 游戏仓储
+type GameRepository interface {
+	// Save 保存游戏（以频道ID为键，已存在时覆盖）
+	Save(game *entity.Game) error
+	// FindByChannelID 通过频道ID查找游戏
+	FindByChannelID(channelID string) (*entity.Game, error)
+	// Delete 删除游戏
+	Delete(channelID string) error
+	// Exists 检查游戏是否存在
+	Exists(channelID string) bool
+}
@@ -0,0 +1,56 @@
+package file
+
+import (
+	"encoding/json"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/infrastructure/persistence/memory"
+)
+
+// BattleRepository 对战文件仓储
+// 对战保存在内存中供查询，每次保存或删除时同步写入 JSON 文件，启动时通过 Restore 恢复
+// 文件中的宝可梦只保存ID，恢复时通过 lookup 从图鉴重新加载
+type BattleRepository struct {
+	*memory.BattleRepository
+	store  *store
+	lookup entity.PokemonLookup
+}
+
+// NewBattleRepository 创建对战文件仓储
+func NewBattleRepository(dir string, lookup entity.PokemonLookup) (*BattleRepository, error) {
+	store, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &BattleRepository{BattleRepository: memory.NewBattleRepository(), store: store, lookup: lookup}, nil
+}
+
+// Save 保存对战
+func (r *BattleRepository) Save(battle *entity.Battle) error {
+	if err := r.BattleRepository.Save(battle); err != nil {
+		return err
+	}
+	return r.store.save(battle.ChannelID, battle)
+}
+
+// Delete 删除对战
+func (r *BattleRepository) Delete(channelID string) error {
+	if err := r.BattleRepository.Delete(channelID); err != nil {
+		return err
+	}
+	return r.store.delete(channelID)
+}
+
+// Restore 从文件恢复全部对战，返回恢复的数量
+func (r *BattleRepository) Restore() (int, error) {
+	return r.store.loadAll(func(data []byte) error {
+		var battle entity.Battle
+		if err := json.Unmarshal(data, &battle); err != nil {
+			return err
+		}
+		if err := battle.ResolvePokemon(r.lookup); err != nil {
+			return err
+		}
+		return r.BattleRepository.Save(&battle)
+	})
+}
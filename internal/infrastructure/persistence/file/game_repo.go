@@ -0,0 +1,51 @@
+package file
+
+import (
+	"encoding/json"
+
+	"github.com/user/dcminigames/internal/domain/uno/entity"
+	"github.com/user/dcminigames/internal/infrastructure/persistence/memory"
+)
+
+// GameRepository UNO 游戏文件仓储
+// 游戏保存在内存中供查询，每次保存或删除时同步写入 JSON 文件，启动时通过 Restore 恢复
+type GameRepository struct {
+	*memory.GameRepository
+	store *store
+}
+
+// NewGameRepository 创建 UNO 游戏文件仓储
+func NewGameRepository(dir string) (*GameRepository, error) {
+	store, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &GameRepository{GameRepository: memory.NewGameRepository(), store: store}, nil
+}
+
+// Save 保存游戏
+func (r *GameRepository) Save(game *entity.Game) error {
+	if err := r.GameRepository.Save(game); err != nil {
+		return err
+	}
+	return r.store.save(game.ChannelID, game)
+}
+
+// Delete 删除游戏
+func (r *GameRepository) Delete(channelID string) error {
+	if err := r.GameRepository.Delete(channelID); err != nil {
+		return err
+	}
+	return r.store.delete(channelID)
+}
+
+// Restore 从文件恢复全部游戏，返回恢复的数量
+func (r *GameRepository) Restore() (int, error) {
+	return r.store.loadAll(func(data []byte) error {
+		var game entity.Game
+		if err := json.Unmarshal(data, &game); err != nil {
+			return err
+		}
+		return r.GameRepository.Save(&game)
+	})
+}
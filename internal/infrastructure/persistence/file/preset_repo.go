@@ -0,0 +1,52 @@
+package file
+
+import (
+	"encoding/json"
+
+	pokemonapp "github.com/user/dcminigames/internal/application/pokemon"
+)
+
+// PresetRepository 配队预设文件仓储（每个用户的预设保存为一个 JSON 文件）
+type PresetRepository struct {
+	store *store
+}
+
+// userPresets 用户预设文件内容
+type userPresets struct {
+	UserID  string
+	Presets []*pokemonapp.TeamPreset
+}
+
+// NewPresetRepository 创建配队预设文件仓储
+func NewPresetRepository(dir string) (*PresetRepository, error) {
+	store, err := newStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &PresetRepository{store: store}, nil
+}
+
+// Save 保存用户的全部预设（为空时删除文件）
+func (r *PresetRepository) Save(userID string, presets []*pokemonapp.TeamPreset) error {
+	if len(presets) == 0 {
+		return r.store.delete(userID)
+	}
+	return r.store.save(userID, userPresets{UserID: userID, Presets: presets})
+}
+
+// FindAll 从文件读取所有用户的预设
+func (r *PresetRepository) FindAll() (map[string][]*pokemonapp.TeamPreset, error) {
+	all := make(map[string][]*pokemonapp.TeamPreset)
+	_, err := r.store.loadAll(func(data []byte) error {
+		var file userPresets
+		if err := json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+		all[file.UserID] = file.Presets
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
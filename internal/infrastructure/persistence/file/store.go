@@ -0,0 +1,93 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// store JSON 文件存储：每条记录保存为目录下的一个文件
+// 写入时先写临时文件再重命名，避免进程中途退出导致文件损坏
+type store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// newStore 创建文件存储（目录不存在时自动创建）
+func newStore(dir string) (*store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &store{dir: dir}, nil
+}
+
+// path 获取记录对应的文件路径
+func (s *store) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+// save 将记录序列化为 JSON 并写入文件
+func (s *store) save(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("序列化失败: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
+
+// delete 删除记录对应的文件
+func (s *store) delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除文件失败: %w", err)
+	}
+	return nil
+}
+
+// loadAll 读取目录下的全部记录，无法解析的文件会被跳过并记录日志
+func (s *store) loadAll(decode func(data []byte) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, fmt.Errorf("读取存储目录失败: %w", err)
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return loaded, fmt.Errorf("读取文件失败: %w", err)
+		}
+		if err := decode(data); err != nil {
+			log.Printf("跳过无法恢复的数据 %s: %v", path, err)
+			continue
+		}
+		loaded++
+	}
+	return loaded, nil
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"sync"
+
+	pokemonapp "github.com/user/dcminigames/internal/application/pokemon"
+)
+
+// PresetRepository 配队预设内存仓储
+type PresetRepository struct {
+	presets map[string][]*pokemonapp.TeamPreset
+	mu      sync.RWMutex
+}
+
+// NewPresetRepository 创建配队预设仓储
+func NewPresetRepository() *PresetRepository {
+	return &PresetRepository{presets: make(map[string][]*pokemonapp.TeamPreset)}
+}
+
+// Save 保存用户的全部预设
+func (r *PresetRepository) Save(userID string, presets []*pokemonapp.TeamPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(presets) == 0 {
+		delete(r.presets, userID)
+		return nil
+	}
+	r.presets[userID] = presets
+	return nil
+}
+
+// FindAll 获取所有用户的预设
+func (r *PresetRepository) FindAll() (map[string][]*pokemonapp.TeamPreset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make(map[string][]*pokemonapp.TeamPreset, len(r.presets))
+	for userID, presets := range r.presets {
+		all[userID] = presets
+	}
+	return all, nil
+}
@@ -1,9 +1,11 @@
 package pokeapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/csv"
 	"fmt"
+	"log"
 	"net/http"
 	"sort"
 	"strconv"
@@ -20,6 +22,8 @@ const (
 	githubCSVBase = "https://raw.githubusercontent.com/PokeAPI/pokeapi/master/data/v2/csv"
 	// 简体中文语言ID
 	langZhHans = 12
+	// 英文语言ID（Showdown 导入导出）
+	langEnglish = 9
 	// 最大宝可梦ID（第9世代）
 	maxPokemonID = 1025
 )
@@ -27,6 +31,7 @@ const (
 // Client PokeAPI 客户端（使用 GitHub CSV 数据）
 type Client struct {
 	httpClient *http.Client
+	options    Options // 数据源配置（缓存目录、有效期、离线模式）
 	cache      *DataCache
 	loading    bool
 	loadMu     sync.Mutex
@@ -42,30 +47,68 @@ type PokemonAbilityInfo struct {
 // DataCache 数据缓存
 type DataCache struct {
 	mu sync.RWMutex
+	// 图鉴数据（可整体保存为预编译图鉴，见 dex.go）
+	Dex
+	// 共享的技能模板 map[moveID]*Move（只读，对战时由 Battler 复制PP）
+	moveTemplates map[int]*entity.Move
+	// 各可学技能范围内宝可梦的可学技能 map[format]map[pokemonID]*learnset
+	learnsets map[valueobject.LearnsetFormat]map[int]*learnset
+	// 是否已加载
+	Loaded bool
+}
+
+// Dex 图鉴数据（由 CSV 解析得到）
+type Dex struct {
 	// 宝可梦名称 map[id]name
 	PokemonNames map[int]string
+	// 宝可梦英文名称 map[id]name
+	PokemonNamesEn map[int]string
 	// 宝可梦种类 map[id]genus
 	PokemonGenus map[int]string
 	// 宝可梦基础数据 map[id]*Pokemon
 	Pokemon map[int]*entity.Pokemon
 	// 技能名称 map[id]name
 	MoveNames map[int]string
-	// 技能数据 map[id]*Move
-	Moves map[int]*entity.Move
-	// 宝可梦可学技能 map[pokemonID][]moveID
-	PokemonMoves map[int][]int
+	// 技能英文名称 map[id]name
+	MoveNamesEn map[int]string
+	// 技能数据 map[id]*MoveData
+	Moves map[int]*entity.MoveData
+	// 宝可梦可学技能记录 map[pokemonID][]MoveLearnRecord（含版本组与学习方式）
+	PokemonMoves map[int][]MoveLearnRecord
+	// 版本组标识符 map[id]identifier
+	VersionGroups map[int]string
 	// 属性名称 map[id]name
 	TypeNames map[int]string
 	// 特性名称 map[id]name
 	AbilityNames map[int]string
+	// 特性英文名称 map[id]name
+	AbilityNamesEn map[int]string
 	// 特性描述 map[id]description
 	AbilityDescriptions map[int]string
 	// 宝可梦特性 map[pokemonID][]abilityID (旧版兼容)
 	PokemonAbilities map[int][]int
 	// 宝可梦特性详情 map[pokemonID][]PokemonAbilityInfo
 	PokemonAbilityInfos map[int][]PokemonAbilityInfo
-	// 是否已加载
-	Loaded bool
+	// 宝可梦标识符 map[pokemonID]identifier（含非默认形态）
+	PokemonIdentifiers map[int]string
+	// 形态所属的种类 map[pokemonID]speciesID
+	PokemonSpecies map[int]int
+	// 非默认形态的宝可梦数据 map[pokemonID]*Pokemon（名称为"种类名(形态名)"）
+	Forms map[int]*entity.Pokemon
+	// 可选择的非默认形态（地区形态、洛托姆等） map[speciesID][]pokemonID
+	SpeciesForms map[int][]int
+	// 进化前的种类 map[speciesID]fromSpeciesID
+	EvolvesFrom map[int]int
+	// 进化链 map[speciesID]chainID
+	EvolutionChains map[int]int
+	// 进化方式 map[speciesID]Evolution（由上一阶段进化而来的方式）
+	Evolutions map[int]valueobject.Evolution
+	// 超级进化形态 map[speciesID][]*MegaForm
+	MegaForms map[int][]*entity.MegaForm
+	// 道具（超级石、Z纯晶） map[itemID]*Item
+	Items map[int]*valueobject.Item
+	// 道具英文名称（超级石、Z纯晶） map[itemID]name
+	ItemNamesEn map[int]string
 }
 
 // NewClient 创建客户端
@@ -74,45 +117,52 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
-		cache: &DataCache{
-			PokemonNames:        make(map[int]string),
-			PokemonGenus:        make(map[int]string),
-			Pokemon:             make(map[int]*entity.Pokemon),
-			MoveNames:           make(map[int]string),
-			Moves:               make(map[int]*entity.Move),
-			PokemonMoves:        make(map[int][]int),
-			TypeNames:           make(map[int]string),
-			AbilityNames:        make(map[int]string),
-			AbilityDescriptions: make(map[int]string),
-			PokemonAbilities:    make(map[int][]int),
-			PokemonAbilityInfos: make(map[int][]PokemonAbilityInfo),
-		},
+		cache: &DataCache{Dex: newDex()},
+	}
+}
+
+// newDex 创建空的图鉴数据
+func newDex() Dex {
+	return Dex{
+		PokemonNames:        make(map[int]string),
+		PokemonNamesEn:      make(map[int]string),
+		PokemonGenus:        make(map[int]string),
+		Pokemon:             make(map[int]*entity.Pokemon),
+		MoveNames:           make(map[int]string),
+		MoveNamesEn:         make(map[int]string),
+		Moves:               make(map[int]*entity.MoveData),
+		PokemonMoves:        make(map[int][]MoveLearnRecord),
+		VersionGroups:       make(map[int]string),
+		TypeNames:           make(map[int]string),
+		AbilityNames:        make(map[int]string),
+		AbilityNamesEn:      make(map[int]string),
+		AbilityDescriptions: make(map[int]string),
+		PokemonAbilities:    make(map[int][]int),
+		PokemonAbilityInfos: make(map[int][]PokemonAbilityInfo),
+		PokemonIdentifiers:  make(map[int]string),
+		PokemonSpecies:      make(map[int]int),
+		Forms:               make(map[int]*entity.Pokemon),
+		SpeciesForms:        make(map[int][]int),
+		EvolvesFrom:         make(map[int]int),
+		EvolutionChains:     make(map[int]int),
+		Evolutions:          make(map[int]valueobject.Evolution),
+		MegaForms:           make(map[int][]*entity.MegaForm),
+		Items:               make(map[int]*valueobject.Item),
+		ItemNamesEn:         make(map[int]string),
 	}
 }
 
 // 默认客户端实例
 var defaultClient = NewClient()
 
-// fetchCSV 获取 CSV 数据
+// fetchCSV 获取 CSV 数据（按本地缓存、网络、内置快照的顺序获取，见 source.go）
 func (c *Client) fetchCSV(ctx context.Context, filename string) ([][]string, error) {
-	url := githubCSVBase + "/" + filename
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
-	}
-	req.Header.Set("User-Agent", "DcMiniGames/1.0")
-
-	resp, err := c.httpClient.Do(req)
+	data, err := c.readCSV(ctx, filename)
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("请求失败: %d", resp.StatusCode)
-	}
-
-	reader := csv.NewReader(resp.Body)
+	reader := csv.NewReader(bytes.NewReader(data))
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("解析CSV失败: %w", err)
@@ -148,9 +198,40 @@ func (c *Client) LoadAllData(ctx context.Context) error {
 	}
 	c.cache.mu.Unlock()
 
+	// 优先使用预编译图鉴（一次读取，无需解析 CSV）
+	if c.loadDex(false) {
+		return nil
+	}
+
+	if err := c.loadCSVData(ctx); err != nil {
+		// CSV 获取失败时退回过期的图鉴或内置图鉴
+		if c.loadDex(true) {
+			log.Printf("加载 CSV 数据失败，使用已有的图鉴: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	c.cache.mu.Lock()
+	c.cache.prepare()
+	c.cache.mu.Unlock()
+
+	if err := c.saveDex(); err != nil {
+		log.Printf("保存图鉴失败: %v", err)
+	}
+
+	c.cache.mu.Lock()
+	c.cache.Loaded = true
+	c.cache.mu.Unlock()
+
+	return nil
+}
+
+// loadCSVData 从 CSV 解析全部图鉴数据
+func (c *Client) loadCSVData(ctx context.Context) error {
 	// 并行加载数据
 	var wg sync.WaitGroup
-	errChan := make(chan error, 6)
+	errChan := make(chan error, 7)
 
 	// 加载属性名称
 	wg.Add(1)
@@ -161,6 +242,15 @@ func (c *Client) LoadAllData(ctx context.Context) error {
 		}
 	}()
 
+	// 加载版本组
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.loadVersionGroups(ctx); err != nil {
+			errChan <- fmt.Errorf("加载版本组失败: %w", err)
+		}
+	}()
+
 	// 加载宝可梦名称
 	wg.Add(1)
 	go func() {
@@ -198,11 +288,26 @@ func (c *Client) LoadAllData(ctx context.Context) error {
 		}
 	}
 
+	// 加载技能追加效果（需要技能数据）
+	if err := c.loadMoveMeta(ctx); err != nil {
+		return fmt.Errorf("加载技能效果数据失败: %w", err)
+	}
+
 	// 加载宝可梦基础数据（需要属性名称）
 	if err := c.loadPokemonData(ctx); err != nil {
 		return fmt.Errorf("加载宝可梦数据失败: %w", err)
 	}
 
+	// 加载形态名称与可选择的形态（需要宝可梦数据）
+	if err := c.loadForms(ctx); err != nil {
+		return fmt.Errorf("加载形态数据失败: %w", err)
+	}
+
+	// 加载进化关系（需要宝可梦数据与形态）
+	if err := c.loadEvolutions(ctx); err != nil {
+		return fmt.Errorf("加载进化数据失败: %w", err)
+	}
+
 	// 加载宝可梦可学技能
 	if err := c.loadPokemonMoves(ctx); err != nil {
 		return fmt.Errorf("加载宝可梦技能失败: %w", err)
@@ -223,9 +328,10 @@ func (c *Client) LoadAllData(ctx context.Context) error {
 		return fmt.Errorf("加载宝可梦特性失败: %w", err)
 	}
 
-	c.cache.mu.Lock()
-	c.cache.Loaded = true
-	c.cache.mu.Unlock()
+	// 加载超级进化形态与Z纯晶（需要宝可梦数据与特性名称）
+	if err := c.loadMegaForms(ctx); err != nil {
+		return fmt.Errorf("加载超级进化数据失败: %w", err)
+	}
 
 	return nil
 }
@@ -277,6 +383,8 @@ func (c *Client) loadPokemonNames(ctx context.Context) error {
 		if langID == langZhHans && pokemonID > 0 && pokemonID <= maxPokemonID {
 			c.cache.PokemonNames[pokemonID] = name
 			c.cache.PokemonGenus[pokemonID] = genus
+		} else if langID == langEnglish && pokemonID > 0 && pokemonID <= maxPokemonID {
+			c.cache.PokemonNamesEn[pokemonID] = name
 		}
 	}
 	return nil
@@ -302,6 +410,8 @@ func (c *Client) loadMoveNames(ctx context.Context) error {
 
 		if langID == langZhHans && moveID > 0 {
 			c.cache.MoveNames[moveID] = name
+		} else if langID == langEnglish && moveID > 0 {
+			c.cache.MoveNamesEn[moveID] = name
 		}
 	}
 	return nil
@@ -328,6 +438,7 @@ func (c *Client) loadMoves(ctx context.Context) error {
 		pp, _ := strconv.Atoi(record[5])
 		accuracy, _ := strconv.Atoi(record[6])
 		priority, _ := strconv.Atoi(record[7])
+		targetID, _ := strconv.Atoi(record[8])
 		damageClass, _ := strconv.Atoi(record[9])
 
 		if moveID <= 0 {
@@ -351,17 +462,114 @@ func (c *Client) loadMoves(ctx context.Context) error {
 		// 判断是否需要充能（破坏光线等技能）
 		rechargeRequired := isRechargeMove(moveID)
 
-		c.cache.Moves[moveID] = &entity.Move{
+		c.cache.Moves[moveID] = &entity.MoveData{
+			ID:               moveID,
 			Type:             pokeType,
 			Category:         category,
 			Power:            power,
 			Accuracy:         accuracy,
-			PP:               pp,
 			MaxPP:            pp,
 			Priority:         priority,
 			RechargeRequired: rechargeRequired,
+			Target:           entity.MoveTarget(targetID),
+		}
+	}
+	return nil
+}
+
+// loadMoveMeta 加载技能追加效果数据（需要先加载技能数据）
+func (c *Client) loadMoveMeta(ctx context.Context) error {
+	metaRecords, err := c.fetchCSV(ctx, "move_meta.csv")
+	if err != nil {
+		return err
+	}
+
+	statRecords, err := c.fetchCSV(ctx, "move_meta_stat_changes.csv")
+	if err != nil {
+		return err
+	}
+
+	ailmentRecords, err := c.fetchCSV(ctx, "move_meta_ailments.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: id,identifier
+	ailments := make(map[int]string)
+	for _, record := range ailmentRecords {
+		if len(record) < 2 {
+			continue
+		}
+		ailmentID, _ := strconv.Atoi(record[0])
+		ailments[ailmentID] = record[1]
+	}
+
+	// CSV格式: move_id,meta_category_id,meta_ailment_id,min_hits,max_hits,min_turns,max_turns,
+	//          drain,healing,crit_rate,ailment_chance,flinch_chance,stat_chance
+	for _, record := range metaRecords {
+		if len(record) < 13 {
+			continue
+		}
+		moveID, _ := strconv.Atoi(record[0])
+		move := c.cache.Moves[moveID]
+		if move == nil {
+			continue
+		}
+		categoryID, _ := strconv.Atoi(record[1])
+		ailmentID, _ := strconv.Atoi(record[2])
+		minHits, _ := strconv.Atoi(record[3])
+		maxHits, _ := strconv.Atoi(record[4])
+		minTurns, _ := strconv.Atoi(record[5])
+		maxTurns, _ := strconv.Atoi(record[6])
+		drain, _ := strconv.Atoi(record[7])
+		healing, _ := strconv.Atoi(record[8])
+		critRate, _ := strconv.Atoi(record[9])
+		ailmentChance, _ := strconv.Atoi(record[10])
+		flinchChance, _ := strconv.Atoi(record[11])
+		statChance, _ := strconv.Atoi(record[12])
+
+		move.Meta = &entity.MoveMeta{
+			Category:      entity.MoveMetaCategory(categoryID),
+			Ailment:       ailmentToStatus(moveID, ailments[ailmentID]),
+			AilmentChance: ailmentChance,
+			MinHits:       minHits,
+			MaxHits:       maxHits,
+			MinTurns:      minTurns,
+			MaxTurns:      maxTurns,
+			Drain:         drain,
+			Healing:       healing,
+			CritRate:      critRate,
+			FlinchChance:  flinchChance,
+			StatChance:    statChance,
+		}
+	}
+
+	// CSV格式: move_id,stat_id,change
+	for _, record := range statRecords {
+		if len(record) < 3 {
+			continue
 		}
+		moveID, _ := strconv.Atoi(record[0])
+		statID, _ := strconv.Atoi(record[1])
+		change, _ := strconv.Atoi(record[2])
+
+		move := c.cache.Moves[moveID]
+		stat := statIDToKey(statID)
+		if move == nil || stat == "" || change == 0 {
+			continue
+		}
+		if move.Meta == nil {
+			move.Meta = &entity.MoveMeta{Category: entity.MetaNetGoodStats}
+		}
+		if move.Meta.StatChanges == nil {
+			move.Meta.StatChanges = make(map[string]int)
+		}
+		move.Meta.StatChanges[stat] = change
 	}
+
 	return nil
 }
 
@@ -394,10 +602,26 @@ func (c *Client) loadPokemonData(ctx context.Context) error {
 			continue
 		}
 		id, _ := strconv.Atoi(record[0])
+		speciesID, _ := strconv.Atoi(record[2])
 		isDefault := record[7] == "1"
 
-		// 只处理默认形态，且在范围内
-		if id <= 0 || id > maxPokemonID || !isDefault {
+		if id <= 0 || speciesID <= 0 || speciesID > maxPokemonID {
+			continue
+		}
+		c.cache.PokemonIdentifiers[id] = record[1]
+		c.cache.PokemonSpecies[id] = speciesID
+
+		// 非默认形态单独保存（用于超级进化等形态变化）
+		if !isDefault {
+			c.cache.Forms[id] = &entity.Pokemon{
+				ID:        id,
+				Name:      record[1],
+				Types:     []valueobject.PokeType{},
+				SpriteURL: GetSpriteURL(id),
+			}
+			continue
+		}
+		if id > maxPokemonID {
 			continue
 		}
 
@@ -426,7 +650,7 @@ func (c *Client) loadPokemonData(ctx context.Context) error {
 		statID, _ := strconv.Atoi(record[1])
 		baseStat, _ := strconv.Atoi(record[2])
 
-		pokemon := c.cache.Pokemon[pokemonID]
+		pokemon := c.cache.pokemonOrForm(pokemonID)
 		if pokemon == nil {
 			continue
 		}
@@ -456,7 +680,7 @@ func (c *Client) loadPokemonData(ctx context.Context) error {
 		pokemonID, _ := strconv.Atoi(record[0])
 		typeID, _ := strconv.Atoi(record[1])
 
-		pokemon := c.cache.Pokemon[pokemonID]
+		pokemon := c.cache.pokemonOrForm(pokemonID)
 		if pokemon == nil {
 			continue
 		}
@@ -470,58 +694,6 @@ func (c *Client) loadPokemonData(ctx context.Context) error {
 	return nil
 }
 
-// loadPokemonMoves 加载宝可梦可学技能
-func (c *Client) loadPokemonMoves(ctx context.Context) error {
-	records, err := c.fetchCSV(ctx, "pokemon_moves.csv")
-	if err != nil {
-		return err
-	}
-
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	// CSV格式: pokemon_id,version_group_id,move_id,pokemon_move_method_id,level,order
-	// 只取最新版本组的技能（version_group_id 较大的）
-	latestVersion := make(map[int]int) // pokemon_id -> max version_group_id
-	moveSet := make(map[int]map[int]bool) // pokemon_id -> set of move_ids
-
-	for _, record := range records {
-		if len(record) < 4 {
-			continue
-		}
-		pokemonID, _ := strconv.Atoi(record[0])
-		versionGroupID, _ := strconv.Atoi(record[1])
-		moveID, _ := strconv.Atoi(record[2])
-
-		if pokemonID <= 0 || pokemonID > maxPokemonID || moveID <= 0 {
-			continue
-		}
-
-		// 记录最新版本
-		if versionGroupID > latestVersion[pokemonID] {
-			latestVersion[pokemonID] = versionGroupID
-		}
-
-		// 添加技能
-		if moveSet[pokemonID] == nil {
-			moveSet[pokemonID] = make(map[int]bool)
-		}
-		moveSet[pokemonID][moveID] = true
-	}
-
-	// 转换为切片并排序
-	for pokemonID, moves := range moveSet {
-		var moveIDs []int
-		for moveID := range moves {
-			moveIDs = append(moveIDs, moveID)
-		}
-		sort.Ints(moveIDs)
-		c.cache.PokemonMoves[pokemonID] = moveIDs
-	}
-
-	return nil
-}
-
 // loadAbilityNames 加载特性名称
 func (c *Client) loadAbilityNames(ctx context.Context) error {
 	records, err := c.fetchCSV(ctx, "ability_names.csv")
@@ -543,6 +715,8 @@ func (c *Client) loadAbilityNames(ctx context.Context) error {
 
 		if langID == langZhHans && abilityID > 0 {
 			c.cache.AbilityNames[abilityID] = name
+		} else if langID == langEnglish && abilityID > 0 {
+			c.cache.AbilityNamesEn[abilityID] = name
 		}
 	}
 	return nil
@@ -609,7 +783,10 @@ func (c *Client) loadPokemonAbilities(ctx context.Context) error {
 		isHidden := record[2] == "1"
 		slot, _ := strconv.Atoi(record[3])
 
-		if pokemonID <= 0 || pokemonID > maxPokemonID || abilityID <= 0 {
+		if pokemonID <= 0 || abilityID <= 0 {
+			continue
+		}
+		if pokemonID > maxPokemonID && c.cache.Forms[pokemonID] == nil {
 			continue
 		}
 
@@ -648,6 +825,240 @@ func (c *Client) loadPokemonAbilities(ctx context.Context) error {
 	return nil
 }
 
+// pokemonOrForm 获取默认形态或非默认形态的宝可梦数据（调用方需持有锁）
+func (dc *DataCache) pokemonOrForm(pokemonID int) *entity.Pokemon {
+	if pokemon := dc.Pokemon[pokemonID]; pokemon != nil {
+		return pokemon
+	}
+	return dc.Forms[pokemonID]
+}
+
+// zCrystalTypes Z纯晶标识符对应的属性
+var zCrystalTypes = map[string]valueobject.PokeType{
+	"normalium-z":  valueobject.TypeNormal,
+	"firium-z":     valueobject.TypeFire,
+	"waterium-z":   valueobject.TypeWater,
+	"electrium-z":  valueobject.TypeElectric,
+	"grassium-z":   valueobject.TypeGrass,
+	"icium-z":      valueobject.TypeIce,
+	"fightinium-z": valueobject.TypeFighting,
+	"poisonium-z":  valueobject.TypePoison,
+	"groundium-z":  valueobject.TypeGround,
+	"flyinium-z":   valueobject.TypeFlying,
+	"psychium-z":   valueobject.TypePsychic,
+	"buginium-z":   valueobject.TypeBug,
+	"rockium-z":    valueobject.TypeRock,
+	"ghostium-z":   valueobject.TypeGhost,
+	"dragonium-z":  valueobject.TypeDragon,
+	"darkinium-z":  valueobject.TypeDark,
+	"steelium-z":   valueobject.TypeSteel,
+	"fairium-z":    valueobject.TypeFairy,
+}
+
+// loadMegaForms 加载超级进化形态、超级石与Z纯晶
+func (c *Client) loadMegaForms(ctx context.Context) error {
+	formRecords, err := c.fetchCSV(ctx, "pokemon_forms.csv")
+	if err != nil {
+		return err
+	}
+	itemRecords, err := c.fetchCSV(ctx, "items.csv")
+	if err != nil {
+		return err
+	}
+	itemNameRecords, err := c.fetchCSV(ctx, "item_names.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: item_id,local_language_id,name
+	itemNames := make(map[int]string)
+	itemNamesEn := make(map[int]string)
+	for _, record := range itemNameRecords {
+		if len(record) < 3 {
+			continue
+		}
+		itemID, _ := strconv.Atoi(record[0])
+		langID, _ := strconv.Atoi(record[1])
+		if langID == langZhHans && itemID > 0 {
+			itemNames[itemID] = record[2]
+		} else if langID == langEnglish && itemID > 0 {
+			itemNamesEn[itemID] = record[2]
+		}
+	}
+
+	// CSV格式: id,identifier,category_id,cost,fling_power,fling_effect_id
+	stones := make(map[string]int) // 超级石标识符 -> 道具ID
+	for _, record := range itemRecords {
+		if len(record) < 2 {
+			continue
+		}
+		itemID, _ := strconv.Atoi(record[0])
+		identifier := record[1]
+		if itemID <= 0 {
+			continue
+		}
+
+		// Z纯晶同时存在携带版与背包版，只使用携带版
+		if pokeType, ok := zCrystalTypes[strings.TrimSuffix(identifier, "--held")]; ok {
+			c.cache.Items[itemID] = &valueobject.Item{
+				ID:          itemID,
+				Name:        string(pokeType) + "Z",
+				Description: string(pokeType) + "属性的技能可以变为Z招式",
+				Category:    valueobject.ItemCategoryZCrystal,
+			}
+			c.cache.ItemNamesEn[itemID] = itemNamesEn[itemID]
+			continue
+		}
+		if _, _, ok := megaStoneBase(identifier); ok {
+			stones[identifier] = itemID
+		}
+	}
+
+	// CSV格式: id,identifier,form_identifier,pokemon_id,introduced_in_version_group_id,is_default,is_battle_only,is_mega,form_order,order
+	for _, record := range formRecords {
+		if len(record) < 8 {
+			continue
+		}
+		isMega, isPrimal := record[7] == "1", record[2] == "primal"
+		if !isMega && !isPrimal {
+			continue
+		}
+		formIdentifier := record[2]
+		pokemonID, _ := strconv.Atoi(record[3])
+		speciesID := c.cache.PokemonSpecies[pokemonID]
+		form := c.cache.Forms[pokemonID]
+		species := c.cache.Pokemon[speciesID]
+		if form == nil || species == nil {
+			continue
+		}
+		speciesIdentifier := c.cache.PokemonIdentifiers[speciesID]
+
+		megaForm := &entity.MegaForm{
+			PokemonID: pokemonID,
+			Types:     append([]valueobject.PokeType{}, form.Types...),
+			BaseStats: entity.Stats{
+				HP: form.BaseHP, Atk: form.BaseAtk, Def: form.BaseDef,
+				SpAtk: form.BaseSpAtk, SpDef: form.BaseSpDef, Speed: form.BaseSpeed,
+			},
+			SpriteURL: form.SpriteURL,
+		}
+		if infos := c.cache.PokemonAbilityInfos[pokemonID]; len(infos) > 0 {
+			if abilityName := c.cache.AbilityNames[infos[0].AbilityID]; abilityName != "" {
+				megaForm.Ability = &valueobject.Ability{
+					ID:          infos[0].AbilityID,
+					Name:        abilityName,
+					Description: c.cache.AbilityDescriptions[infos[0].AbilityID],
+				}
+			}
+		}
+
+		// 原始回归：携带对应的宝珠出场时自动发生，不占用超级进化
+		if isPrimal {
+			orb, ok := primalOrbs[speciesIdentifier]
+			if !ok {
+				continue
+			}
+			megaForm.Name = "原始" + species.Name
+			megaForm.StoneID = orb.ID
+			megaForm.Primal = true
+			c.cache.MegaForms[speciesID] = append(c.cache.MegaForms[speciesID], megaForm)
+			continue
+		}
+
+		// 没有对应超级石的形态只能通过学会指定技能超级进化（如烈空坐）
+		stoneID := matchMegaStone(speciesIdentifier, formIdentifier, stones)
+		if stoneID == 0 && megaMoves[speciesIdentifier] == 0 {
+			continue
+		}
+
+		name := "超级" + species.Name
+		if strings.HasSuffix(formIdentifier, "-x") {
+			name += "X"
+		} else if strings.HasSuffix(formIdentifier, "-y") {
+			name += "Y"
+		}
+		megaForm.Name = name
+		megaForm.StoneID = stoneID
+		c.cache.MegaForms[speciesID] = append(c.cache.MegaForms[speciesID], megaForm)
+		species.CanMegaEvolve = true
+		if stoneID == 0 {
+			megaForm.MoveID = megaMoves[speciesIdentifier]
+			continue
+		}
+		if species.MegaStoneID == 0 {
+			species.MegaStoneID = stoneID
+		}
+
+		stoneName := itemNames[stoneID]
+		if stoneName == "" {
+			stoneName = name + "石"
+		}
+		c.cache.Items[stoneID] = &valueobject.Item{
+			ID:          stoneID,
+			Name:        stoneName,
+			Description: "携带后可让" + species.Name + "超级进化为" + name,
+			Category:    valueobject.ItemCategoryMega,
+		}
+		c.cache.ItemNamesEn[stoneID] = itemNamesEn[stoneID]
+	}
+
+	return nil
+}
+
+// primalOrbs 原始回归所需的宝珠（按种类标识符）
+var primalOrbs = map[string]valueobject.Item{
+	"groudon": valueobject.ItemRedOrb,
+	"kyogre":  valueobject.ItemBlueOrb,
+}
+
+// megaMoves 不需要超级石、学会指定技能即可超级进化的宝可梦（按种类标识符）
+var megaMoves = map[string]int{
+	"rayquaza": 620, // 画龙点睛
+}
+
+// megaStoneBase 解析超级石标识符（如 charizardite-x），返回去掉"ite"后缀的部分与X/Y后缀
+func megaStoneBase(identifier string) (base, suffix string, ok bool) {
+	for _, suffix := range []string{"-x", "-y", ""} {
+		if base := strings.TrimSuffix(identifier, "ite"+suffix); base != identifier {
+			return base, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+// matchMegaStone 根据标识符为超级进化形态匹配超级石（种类名与超级石名的最长公共前缀）
+func matchMegaStone(speciesIdentifier, formIdentifier string, stones map[string]int) int {
+	formSuffix := ""
+	if strings.HasSuffix(formIdentifier, "-x") {
+		formSuffix = "-x"
+	} else if strings.HasSuffix(formIdentifier, "-y") {
+		formSuffix = "-y"
+	}
+
+	bestID, bestLen := 0, 0
+	for identifier, itemID := range stones {
+		base, suffix, _ := megaStoneBase(identifier)
+		if suffix != formSuffix {
+			continue
+		}
+		prefix := 0
+		for prefix < len(base) && prefix < len(speciesIdentifier) && base[prefix] == speciesIdentifier[prefix] {
+			prefix++
+		}
+		// 超级石名由种类名变形而来（如 blastoisinite、lucarionite），公共前缀需覆盖大部分
+		if prefix < 4 || len(base)-prefix > 3 {
+			continue
+		}
+		if prefix > bestLen || (prefix == bestLen && itemID < bestID) {
+			bestID, bestLen = itemID, prefix
+		}
+	}
+	return bestID
+}
+
 // typeIDToPokeType 将类型ID转换为PokeType
 func typeIDToPokeType(typeID int) valueobject.PokeType {
 	switch typeID {
@@ -692,6 +1103,59 @@ func typeIDToPokeType(typeID int) valueobject.PokeType {
 	}
 }
 
+// statIDToKey 将 PokeAPI 能力ID转换为能力等级键名
+func statIDToKey(statID int) string {
+	switch statID {
+	case 2:
+		return "atk"
+	case 3:
+		return "def"
+	case 4:
+		return "spatk"
+	case 5:
+		return "spdef"
+	case 6:
+		return "speed"
+	case 7:
+		return "accuracy"
+	case 8:
+		return "evasion"
+	}
+	return ""
+}
+
+// ailmentToStatus 将 PokeAPI 状态标识转换为对战中的状态名称
+// 剧毒与中毒在数据中共用 poison，需要按技能区分
+func ailmentToStatus(moveID int, identifier string) string {
+	switch identifier {
+	case "paralysis":
+		return string(entity.StatusParalyze)
+	case "sleep":
+		return string(entity.StatusSleep)
+	case "freeze":
+		return string(entity.StatusFreeze)
+	case "burn":
+		return string(entity.StatusBurn)
+	case "poison":
+		// 剧毒(92)、剧毒牙(305)
+		if moveID == 92 || moveID == 305 {
+			return string(entity.StatusBadPoison)
+		}
+		return string(entity.StatusPoison)
+	case "confusion":
+		return string(entity.VolatileConfusion)
+	case "infatuation":
+		return string(entity.VolatileAttraction)
+	case "torment":
+		return string(entity.VolatileTorment)
+	case "disable":
+		return string(entity.VolatileDisable)
+	case "leech-seed":
+		return string(entity.VolatileLeechSeed)
+	}
+	return ""
+}
+
 // isRechargeMove 判断是否为需要充能的技能
 func isRechargeMove(moveID int) bool {
 	// 需要充能的技能ID列表
@@ -756,8 +1220,14 @@ func IsDataLoaded() bool {
 	return defaultClient.cache.Loaded
 }
 
-// GetPredefinedPokemon 获取宝可梦（兼容旧接口）
+// GetPredefinedPokemon 获取宝可梦（兼容旧接口，可学技能为全国图鉴范围）
 func GetPredefinedPokemon(id int) *entity.Pokemon {
+	return GetPokemonInFormat(id, valueobject.LearnsetNational)
+}
+
+// GetPokemonInFormat 获取宝可梦，可学技能按指定范围列出
+// 宝可梦在该范围内没有可学技能（如无法在朱紫中使用）时返回 nil
+func GetPokemonInFormat(id int, format valueobject.LearnsetFormat) *entity.Pokemon {
 	if !IsDataLoaded() {
 		if err := EnsureDataLoaded(); err != nil {
 			return nil
@@ -765,18 +1235,18 @@ func GetPredefinedPokemon(id int) *entity.Pokemon {
 	}
 
 	defaultClient.cache.mu.RLock()
-	pokemon := defaultClient.cache.Pokemon[id]
-	defaultClient.cache.mu.RUnlock()
+	defer defaultClient.cache.mu.RUnlock()
 
-	if pokemon == nil {
+	pokemon := defaultClient.cache.selectablePokemon(id)
+	if pokemon == nil || !defaultClient.cache.availableIn(id, format) {
 		return nil
 	}
 
 	// 返回副本并填充技能
-	return copyPokemonWithMoves(pokemon)
+	return copyPokemonWithMoves(pokemon, format)
 }
 
-// GetAllPredefinedPokemon 获取所有宝可梦
+// GetAllPredefinedPokemon 获取所有宝可梦（含可选择的形态，按全国图鉴编号排序）
 func GetAllPredefinedPokemon() []*entity.Pokemon {
 	if !IsDataLoaded() {
 		if err := EnsureDataLoaded(); err != nil {
@@ -787,21 +1257,16 @@ func GetAllPredefinedPokemon() []*entity.Pokemon {
 	defaultClient.cache.mu.RLock()
 	defer defaultClient.cache.mu.RUnlock()
 
-	result := make([]*entity.Pokemon, 0, len(defaultClient.cache.Pokemon))
-	for _, p := range defaultClient.cache.Pokemon {
-		result = append(result, copyPokemonWithMoves(p))
+	pokemons := defaultClient.cache.allSelectablePokemon()
+	result := make([]*entity.Pokemon, 0, len(pokemons))
+	for _, p := range pokemons {
+		result = append(result, copyPokemonWithMoves(p, valueobject.LearnsetNational))
 	}
-
-	// 按ID排序
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].ID < result[j].ID
-	})
-
 	return result
 }
 
-// SearchPredefinedPokemon 搜索宝可梦
-func SearchPredefinedPokemon(keyword string) []*entity.Pokemon {
+// SearchPredefinedPokemon 搜索宝可梦（只返回在指定可学技能范围内可用的宝可梦）
+func SearchPredefinedPokemon(keyword string, format valueobject.LearnsetFormat) []*entity.Pokemon {
 	if !IsDataLoaded() {
 		if err := EnsureDataLoaded(); err != nil {
 			return nil
@@ -820,24 +1285,19 @@ func SearchPredefinedPokemon(keyword string) []*entity.Pokemon {
 
 	// 尝试按ID搜索
 	if id, err := strconv.Atoi(keyword); err == nil && id > 0 {
-		if p := defaultClient.cache.Pokemon[id]; p != nil {
-			results = append(results, copyPokemonWithMoves(p))
+		if p := defaultClient.cache.selectablePokemon(id); p != nil && defaultClient.cache.availableIn(id, format) {
+			results = append(results, copyPokemonWithMoves(p, format))
 			return results
 		}
 	}
 
-	// 按名称搜索
-	for _, p := range defaultClient.cache.Pokemon {
-		if strings.Contains(p.Name, keyword) {
-			results = append(results, copyPokemonWithMoves(p))
+	// 按名称搜索（形态名称包含种类名称，如"拉达(阿罗拉的样子)"）
+	for _, p := range defaultClient.cache.allSelectablePokemon() {
+		if strings.Contains(p.Name, keyword) && defaultClient.cache.availableIn(p.ID, format) {
+			results = append(results, copyPokemonWithMoves(p, format))
 		}
 	}
 
-	// 按ID排序
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].ID < results[j].ID
-	})
-
 	// 限制结果数量
 	if len(results) > 25 {
 		results = results[:25]
@@ -848,17 +1308,31 @@ func SearchPredefinedPokemon(keyword string) []*entity.Pokemon {
 
 // GetPokemonByKeyword 通过关键词获取宝可梦
 func GetPokemonByKeyword(keyword string) *entity.Pokemon {
-	results := SearchPredefinedPokemon(keyword)
+	results := SearchPredefinedPokemon(keyword, valueobject.LearnsetNational)
 	if len(results) > 0 {
 		return results[0]
 	}
 	return nil
 }
 
-// copyPokemonWithMoves 复制宝可梦并填充技能和特性
-func copyPokemonWithMoves(p *entity.Pokemon) *entity.Pokemon {
+// defaultMove 没有可学技能时使用的默认技能
+var defaultMove = &entity.Move{
+	MoveData: &entity.MoveData{
+		Name:     "撞击",
+		Type:     valueobject.TypeNormal,
+		Category: entity.CategoryPhysical,
+		Power:    40,
+		Accuracy: 100,
+		MaxPP:    35,
+	},
+	PP: 35,
+}
+
+// copyPokemonWithMoves 复制宝可梦并填充指定范围内的可学技能和特性（调用方需持有读锁）
+func copyPokemonWithMoves(p *entity.Pokemon, format valueobject.LearnsetFormat) *entity.Pokemon {
 	newP := &entity.Pokemon{
 		ID:        p.ID,
+		SpeciesID: p.SpeciesID,
 		Name:      p.Name,
 		Types:     append([]valueobject.PokeType{}, p.Types...),
 		BaseHP:    p.BaseHP,
@@ -868,40 +1342,20 @@ func copyPokemonWithMoves(p *entity.Pokemon) *entity.Pokemon {
 		BaseSpDef: p.BaseSpDef,
 		BaseSpeed: p.BaseSpeed,
 		SpriteURL: p.SpriteURL,
-	}
 
-	// 填充可学技能
-	moveIDs := defaultClient.cache.PokemonMoves[p.ID]
-	for _, moveID := range moveIDs {
-		moveData := defaultClient.cache.Moves[moveID]
-		moveName := defaultClient.cache.MoveNames[moveID]
-		if moveData != nil && moveName != "" {
-			move := &entity.Move{
-				Name:             moveName,
-				Type:             moveData.Type,
-				Category:         moveData.Category,
-				Power:            moveData.Power,
-				Accuracy:         moveData.Accuracy,
-				PP:               moveData.PP,
-				MaxPP:            moveData.MaxPP,
-				Priority:         moveData.Priority,
-				RechargeRequired: moveData.RechargeRequired,
-			}
-			newP.LearnableMoves = append(newP.LearnableMoves, move)
-		}
+		CanMegaEvolve: p.CanMegaEvolve,
+		MegaStoneID:   p.MegaStoneID,
+		MegaForms:     defaultClient.cache.MegaForms[p.ID],
+		CanEvolve:     p.CanEvolve,
 	}
 
-	// 如果没有技能，添加默认技能
-	if len(newP.LearnableMoves) == 0 {
-		newP.LearnableMoves = append(newP.LearnableMoves, &entity.Move{
-			Name:     "撞击",
-			Type:     valueobject.TypeNormal,
-			Category: entity.CategoryPhysical,
-			Power:    40,
-			Accuracy: 100,
-			PP:       35,
-			MaxPP:    35,
-		})
+	// 填充可学技能（共享技能模板，限制容量避免追加时改写共享数组）
+	if learnset := defaultClient.cache.learnsetOf(p.ID, format); learnset != nil {
+		moves := learnset.moves
+		newP.LearnableMoves = moves[:len(moves):len(moves)]
+	} else {
+		// 如果没有技能，使用默认技能
+		newP.LearnableMoves = []*entity.Move{defaultMove}
 	}
 
 	// 填充特性（使用详细信息）
@@ -935,6 +1389,58 @@ func copyPokemonWithMoves(p *entity.Pokemon) *entity.Pokemon {
 	return newP
 }
 
+// GetItem 通过ID获取道具（超级石、Z纯晶）
+func GetItem(id int) *valueobject.Item {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	if item := defaultClient.cache.Items[id]; item != nil {
+		itemCopy := *item
+		return &itemCopy
+	}
+	return nil
+}
+
+// GetMegaStones 获取宝可梦可使用的超级石
+func GetMegaStones(pokemonID int) []*valueobject.Item {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	var stones []*valueobject.Item
+	for _, form := range defaultClient.cache.MegaForms[pokemonID] {
+		if item := defaultClient.cache.Items[form.StoneID]; item != nil {
+			itemCopy := *item
+			stones = append(stones, &itemCopy)
+		}
+	}
+	return stones
+}
+
+// GetZCrystals 获取所有属性的Z纯晶（按道具ID排序）
+func GetZCrystals() []*valueobject.Item {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	var crystals []*valueobject.Item
+	for _, item := range defaultClient.cache.Items {
+		if item.Category == valueobject.ItemCategoryZCrystal {
+			itemCopy := *item
+			crystals = append(crystals, &itemCopy)
+		}
+	}
+	sort.Slice(crystals, func(i, j int) bool {
+		return crystals[i].ID < crystals[j].ID
+	})
+	return crystals
+}
+
+// GetZCrystal 获取指定属性的Z纯晶
+func GetZCrystal(pokeType valueobject.PokeType) *valueobject.Item {
+	for _, crystal := range GetZCrystals() {
+		if crystalType, ok := valueobject.ZCrystalType(crystal); ok && crystalType == pokeType {
+			return crystal
+		}
+	}
+	return nil
+}
+
 // GetTotalPokemonCount 获取宝可梦总数
 func GetTotalPokemonCount() int {
 	if !IsDataLoaded() {
@@ -0,0 +1,209 @@
+package pokeapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+)
+
+// 预编译图鉴
+// 解析全部 CSV（尤其是数十万行的 pokemon_moves.csv）占据了启动的大部分时间与内存。
+// CSV 解析完成后将图鉴数据（宝可梦、形态、进化、技能、可学技能记录、特性、道具等）以 gob 编码、gzip 压缩
+// 保存到缓存目录，之后启动时一次读取即可恢复。`bot snapshot` 命令也会生成内置图鉴。
+// 获取顺序：缓存目录中未过期的图鉴 → CSV（见 source.go）→ 过期的图鉴 → 内置图鉴
+// 离线模式下直接使用已有的图鉴（缓存目录或内置）
+
+const (
+	// dexVersion 图鉴格式版本（Dex 结构或解析规则变化时递增，旧版本的图鉴会被忽略并重新生成）
+	dexVersion = 5
+	// dexFilename 图鉴文件名（缓存目录与内置快照使用同一文件名）
+	dexFilename = "dex.gob.gz"
+)
+
+// dexFile 图鉴文件内容
+type dexFile struct {
+	Version int
+	Dex     Dex
+}
+
+// encodeDex 编码图鉴（gob + gzip）
+func encodeDex(w io.Writer, dex *Dex) error {
+	writer, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(writer).Encode(dexFile{Version: dexVersion, Dex: *dex}); err != nil {
+		return fmt.Errorf("编码图鉴失败: %w", err)
+	}
+	return writer.Close()
+}
+
+// decodeDex 解码图鉴
+func decodeDex(data []byte) (*Dex, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解压图鉴失败: %w", err)
+	}
+	defer reader.Close()
+
+	var file dexFile
+	if err := gob.NewDecoder(reader).Decode(&file); err != nil {
+		return nil, fmt.Errorf("解析图鉴失败: %w", err)
+	}
+	if file.Version != dexVersion {
+		return nil, fmt.Errorf("图鉴版本 %d 与当前版本 %d 不一致", file.Version, dexVersion)
+	}
+	return &file.Dex, nil
+}
+
+// loadDex 从图鉴加载数据，成功时返回 true
+// fallback 为 false 时只使用缓存目录中未过期的图鉴（离线模式下还会使用过期的图鉴与内置图鉴）
+// fallback 为 true 时（CSV 获取失败后）使用任何可用的图鉴
+func (c *Client) loadDex(fallback bool) bool {
+	acceptAny := fallback || c.options.Offline
+
+	if data, fresh := c.readCache(dexFilename); data != nil && (fresh || acceptAny) {
+		dex, err := decodeDex(data)
+		if err == nil {
+			c.useDex(dex)
+			return true
+		}
+		log.Printf("缓存目录中的图鉴无效: %v", err)
+	}
+
+	if !acceptAny {
+		return false
+	}
+	data, err := snapshotFS.ReadFile(path.Join("snapshot", dexFilename))
+	if err != nil {
+		return false
+	}
+	dex, err := decodeDex(data)
+	if err != nil {
+		log.Printf("内置图鉴无效: %v", err)
+		return false
+	}
+	c.useDex(dex)
+	return true
+}
+
+// useDex 使用图鉴替换当前数据并标记为已加载
+func (c *Client) useDex(dex *Dex) {
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	c.cache.Dex = *dex
+	c.cache.prepare()
+	c.cache.Loaded = true
+}
+
+// saveDex 将当前图鉴保存到缓存目录
+func (c *Client) saveDex() error {
+	if c.options.CacheDir == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	c.cache.mu.RLock()
+	err := encodeDex(&buf, &c.cache.Dex)
+	c.cache.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return c.writeCache(dexFilename, buf.Bytes(), "")
+}
+
+// prepare 图鉴加载后的处理：驻留字符串并创建共享的技能模板（调用方需持有写锁）
+func (dc *DataCache) prepare() {
+	dc.Dex.intern()
+	dc.buildLearnsets()
+}
+
+// interner 字符串驻留表（内容相同的字符串共享同一份内存）
+type interner map[string]string
+
+// get 获取驻留的字符串
+// 首次出现时复制一份保存：CSV 字段引用整行记录，复制后整行记录才能被回收
+func (in interner) get(s string) string {
+	if v, ok := in[s]; ok {
+		return v
+	}
+	v := strings.Clone(s)
+	in[v] = v
+	return v
+}
+
+// internAs 驻留字符串类型的值（属性、技能分类等）
+func internAs[T ~string](in interner, s T) T {
+	return T(in.get(string(s)))
+}
+
+// names 驻留名称表中的全部字符串
+func (in interner) names(names map[int]string) {
+	for id, name := range names {
+		names[id] = in.get(name)
+	}
+}
+
+// intern 驻留图鉴中的字符串
+// 宝可梦名称、属性、技能分类、特性描述等在多处重复出现，驻留后只保留一份
+func (d *Dex) intern() {
+	in := make(interner)
+
+	for _, names := range []map[int]string{
+		d.PokemonNames, d.PokemonNamesEn, d.PokemonGenus,
+		d.MoveNames, d.MoveNamesEn, d.TypeNames,
+		d.AbilityNames, d.AbilityNamesEn, d.AbilityDescriptions,
+		d.PokemonIdentifiers, d.ItemNamesEn, d.VersionGroups,
+	} {
+		in.names(names)
+	}
+
+	for _, pokemons := range []map[int]*entity.Pokemon{d.Pokemon, d.Forms} {
+		for _, p := range pokemons {
+			p.Name = in.get(p.Name)
+			for i, t := range p.Types {
+				p.Types[i] = internAs(in, t)
+			}
+		}
+	}
+
+	for _, move := range d.Moves {
+		move.Name = in.get(move.Name)
+		move.Type = internAs(in, move.Type)
+		move.Category = internAs(in, move.Category)
+		if move.Meta != nil {
+			move.Meta.Ailment = in.get(move.Meta.Ailment)
+		}
+	}
+
+	for _, forms := range d.MegaForms {
+		for _, form := range forms {
+			form.Name = in.get(form.Name)
+			for i, t := range form.Types {
+				form.Types[i] = internAs(in, t)
+			}
+			if form.Ability != nil {
+				form.Ability.Name = in.get(form.Ability.Name)
+				form.Ability.Description = in.get(form.Ability.Description)
+			}
+		}
+	}
+
+	for speciesID, evolution := range d.Evolutions {
+		evolution.Name = in.get(evolution.Name)
+		evolution.Item = in.get(evolution.Item)
+		d.Evolutions[speciesID] = evolution
+	}
+
+	for _, item := range d.Items {
+		item.Name = in.get(item.Name)
+		item.Description = in.get(item.Description)
+		item.Category = internAs(in, item.Category)
+	}
+}
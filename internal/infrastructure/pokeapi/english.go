@@ -0,0 +1,114 @@
+package pokeapi
+
+import (
+	"strings"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// 英文名称查询（用于 Showdown 队伍导入导出）
+// 名称统一按 valueobject.ToID 比较，忽略大小写、空格与标点
+
+// findByEnglishName 在英文名称表中查找对应的ID（调用方需持有锁）
+func findByEnglishName(names map[int]string, name string) int {
+	id := valueobject.ToID(name)
+	if id == "" {
+		return 0
+	}
+	for key, english := range names {
+		if valueobject.ToID(english) == id {
+			return key
+		}
+	}
+	return 0
+}
+
+// FindPokemonIDByEnglishName 通过英文名称查找宝可梦ID（可选择的形态返回形态的ID，如 Ninetales-Alola）
+// 返回的 exact 为 false 表示名称是暂不支持的形态（如超级进化等对战专属形态），已回退到对应的种类
+func FindPokemonIDByEnglishName(name string) (id int, exact bool) {
+	if !IsDataLoaded() {
+		if err := EnsureDataLoaded(); err != nil {
+			return 0, false
+		}
+	}
+
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+
+	if id := findByEnglishName(defaultClient.cache.PokemonNamesEn, name); id > 0 {
+		return id, true
+	}
+
+	// 形态名称按标识符匹配，不可选择的形态回退到所属种类
+	if formID := findByEnglishName(defaultClient.cache.PokemonIdentifiers, name); formID > 0 {
+		if defaultClient.cache.selectablePokemon(formID) != nil {
+			return formID, true
+		}
+		if speciesID := defaultClient.cache.PokemonSpecies[formID]; defaultClient.cache.Pokemon[speciesID] != nil {
+			return speciesID, speciesID == formID
+		}
+	}
+
+	// 未收录的形态：去掉 "-" 后的形态后缀再尝试
+	if idx := strings.Index(name, "-"); idx > 0 {
+		if id := findByEnglishName(defaultClient.cache.PokemonNamesEn, name[:idx]); id > 0 {
+			return id, false
+		}
+	}
+	return 0, false
+}
+
+// FindMoveIDByEnglishName 通过英文名称查找技能ID
+func FindMoveIDByEnglishName(name string) int {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return findByEnglishName(defaultClient.cache.MoveNamesEn, name)
+}
+
+// FindAbilityIDByEnglishName 通过英文名称查找特性ID
+func FindAbilityIDByEnglishName(name string) int {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return findByEnglishName(defaultClient.cache.AbilityNamesEn, name)
+}
+
+// FindItemByEnglishName 通过英文名称查找超级石或Z纯晶
+func FindItemByEnglishName(name string) *valueobject.Item {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	if id := findByEnglishName(defaultClient.cache.ItemNamesEn, name); id > 0 {
+		if item := defaultClient.cache.Items[id]; item != nil {
+			copied := *item
+			return &copied
+		}
+	}
+	return nil
+}
+
+// GetPokemonEnglishName 获取宝可梦英文名称
+func GetPokemonEnglishName(id int) string {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return defaultClient.cache.PokemonNamesEn[id]
+}
+
+// GetMoveEnglishName 获取技能英文名称
+func GetMoveEnglishName(id int) string {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return defaultClient.cache.MoveNamesEn[id]
+}
+
+// GetAbilityEnglishName 获取特性英文名称
+func GetAbilityEnglishName(id int) string {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return defaultClient.cache.AbilityNamesEn[id]
+}
+
+// GetItemEnglishName 获取超级石或Z纯晶的英文名称
+func GetItemEnglishName(id int) string {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	return defaultClient.cache.ItemNamesEn[id]
+}
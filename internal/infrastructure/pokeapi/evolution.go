@@ -0,0 +1,152 @@
+package pokeapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// 进化
+// pokemon_species.csv 提供进化前的种类与所属的进化链，pokemon_evolution.csv 提供进化方式（等级、道具等）。
+
+// loadEvolutions 加载进化关系与进化方式，标记还能进化的宝可梦（用于进化奇石）
+func (c *Client) loadEvolutions(ctx context.Context) error {
+	speciesRecords, err := c.fetchCSV(ctx, "pokemon_species.csv")
+	if err != nil {
+		return err
+	}
+	evolutionRecords, err := c.fetchCSV(ctx, "pokemon_evolution.csv")
+	if err != nil {
+		return err
+	}
+	itemNameRecords, err := c.fetchCSV(ctx, "item_names.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: id,identifier,generation_id,evolves_from_species_id,evolution_chain_id,...
+	for _, record := range speciesRecords {
+		if len(record) < 5 {
+			continue
+		}
+		speciesID, _ := strconv.Atoi(record[0])
+		if c.cache.Pokemon[speciesID] == nil {
+			continue
+		}
+		if chainID, _ := strconv.Atoi(record[4]); chainID > 0 {
+			c.cache.EvolutionChains[speciesID] = chainID
+		}
+		fromID, _ := strconv.Atoi(record[3])
+		if c.cache.Pokemon[fromID] == nil {
+			continue
+		}
+		c.cache.EvolvesFrom[speciesID] = fromID
+
+		// 进化前的种类（及其可选择的形态）还能进化
+		c.cache.Pokemon[fromID].CanEvolve = true
+		for _, formID := range c.cache.SpeciesForms[fromID] {
+			c.cache.Forms[formID].CanEvolve = true
+		}
+	}
+
+	// CSV格式: item_id,local_language_id,name
+	itemNames := make(map[int]string)
+	for _, record := range itemNameRecords {
+		if len(record) < 3 {
+			continue
+		}
+		itemID, _ := strconv.Atoi(record[0])
+		langID, _ := strconv.Atoi(record[1])
+		if langID == langZhHans && itemID > 0 {
+			itemNames[itemID] = record[2]
+		}
+	}
+
+	// CSV格式: id,evolved_species_id,evolution_trigger_id,trigger_item_id,minimum_level,gender_id,location_id,
+	// held_item_id,time_of_day,known_move_id,known_move_type_id,minimum_happiness,...
+	// 同一种类有多种进化方式时（如不同世代的进化地点）只保留第一条
+	for _, record := range evolutionRecords {
+		if len(record) < 12 {
+			continue
+		}
+		speciesID, _ := strconv.Atoi(record[1])
+		species := c.cache.Pokemon[speciesID]
+		if species == nil {
+			continue
+		}
+		if _, ok := c.cache.Evolutions[speciesID]; ok {
+			continue
+		}
+		trigger, _ := strconv.Atoi(record[2])
+		itemID, _ := strconv.Atoi(record[3])
+		minLevel, _ := strconv.Atoi(record[4])
+		heldItemID, _ := strconv.Atoi(record[7])
+		minHappiness, _ := strconv.Atoi(record[11])
+		if itemID == 0 {
+			itemID = heldItemID
+		}
+
+		c.cache.Evolutions[speciesID] = valueobject.Evolution{
+			SpeciesID:    speciesID,
+			Name:         species.Name,
+			Trigger:      valueobject.EvolutionTrigger(trigger),
+			MinLevel:     minLevel,
+			Item:         itemNames[itemID],
+			MinHappiness: minHappiness,
+		}
+	}
+
+	return nil
+}
+
+// GetEvolutionChain 获取宝可梦所属的进化链（形态使用所属种类的进化链，不会进化的宝可梦返回 nil）
+func GetEvolutionChain(pokemonID int) valueobject.EvolutionChain {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+	dc := defaultClient.cache
+
+	speciesID := pokemonID
+	if id := dc.PokemonSpecies[pokemonID]; id > 0 {
+		speciesID = id
+	}
+	chainID := dc.EvolutionChains[speciesID]
+	if chainID == 0 {
+		return nil
+	}
+
+	// 按进化阶段（进化前种类的层数）分组
+	var chain valueobject.EvolutionChain
+	for id, otherChainID := range dc.EvolutionChains {
+		if otherChainID != chainID {
+			continue
+		}
+		// 进化链最多三个阶段（限制层数以防数据异常导致死循环）
+		stage := 0
+		for from := dc.EvolvesFrom[id]; from > 0 && stage < 3; from = dc.EvolvesFrom[from] {
+			stage++
+		}
+		for len(chain) <= stage {
+			chain = append(chain, nil)
+		}
+		evolution, ok := dc.Evolutions[id]
+		if !ok {
+			evolution = valueobject.Evolution{SpeciesID: id, Name: dc.Pokemon[id].Name}
+		}
+		chain[stage] = append(chain[stage], evolution)
+	}
+	if len(chain) < 2 {
+		return nil
+	}
+
+	for _, stage := range chain {
+		sort.Slice(stage, func(i, j int) bool {
+			return stage[i].SpeciesID < stage[j].SpeciesID
+		})
+	}
+	return chain
+}
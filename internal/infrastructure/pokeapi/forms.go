@@ -0,0 +1,190 @@
+package pokeapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/ability"
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// 形态
+// pokemon.csv 中的非默认形态（地区形态、洛托姆、达摩模式、超级进化等）保存在 Forms 中。
+// pokemon_forms.csv 与 pokemon_form_names.csv 提供形态的中文名称与是否只在对战中出现：
+// 非对战专属的形态（地区形态、洛托姆等）可以像普通宝可梦一样选择，
+// 对战专属的形态（达摩模式、剑形态等）供形态变化类特性查询种族值与属性。
+
+// formInfo pokemon_forms.csv 中宝可梦的默认外观
+type formInfo struct {
+	pokemonID  int
+	identifier string // 形态标识符（如 alola）
+	battleOnly bool   // 只在对战中出现
+	mega       bool   // 超级进化
+}
+
+// loadForms 加载形态名称，并整理各种类可选择的形态
+func (c *Client) loadForms(ctx context.Context) error {
+	formRecords, err := c.fetchCSV(ctx, "pokemon_forms.csv")
+	if err != nil {
+		return err
+	}
+	nameRecords, err := c.fetchCSV(ctx, "pokemon_form_names.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: id,identifier,form_identifier,pokemon_id,introduced_in_version_group_id,is_default,is_battle_only,is_mega,form_order,order
+	// 同一宝可梦的其他外观（如未知图腾的字母）不影响对战，只使用默认外观
+	forms := make(map[int]formInfo) // 外观ID -> 形态
+	for _, record := range formRecords {
+		if len(record) < 8 || record[5] != "1" {
+			continue
+		}
+		formID, _ := strconv.Atoi(record[0])
+		pokemonID, _ := strconv.Atoi(record[3])
+		if c.cache.Forms[pokemonID] == nil {
+			continue
+		}
+		forms[formID] = formInfo{
+			pokemonID:  pokemonID,
+			identifier: record[2],
+			battleOnly: record[6] == "1",
+			mega:       record[7] == "1",
+		}
+	}
+
+	// CSV格式: pokemon_form_id,local_language_id,form_name,pokemon_name
+	formNames := make(map[int]string)
+	formNamesEn := make(map[int]string)
+	for _, record := range nameRecords {
+		if len(record) < 3 || record[2] == "" {
+			continue
+		}
+		formID, _ := strconv.Atoi(record[0])
+		langID, _ := strconv.Atoi(record[1])
+		if langID == langZhHans {
+			formNames[formID] = record[2]
+		} else if langID == langEnglish {
+			formNamesEn[formID] = record[2]
+		}
+	}
+
+	for formID, info := range forms {
+		form := c.cache.Forms[info.pokemonID]
+		speciesID := c.cache.PokemonSpecies[info.pokemonID]
+		speciesName := c.cache.PokemonNames[speciesID]
+		if speciesName == "" {
+			continue
+		}
+
+		formName := formNames[formID]
+		if formName == "" {
+			formName = formNamesEn[formID]
+		}
+		if formName == "" {
+			formName = info.identifier
+		}
+		form.Name = speciesName + "(" + formName + ")"
+		form.SpeciesID = speciesID
+
+		// 超级进化、极巨化等对战专属形态与霸主宝可梦不可直接选择
+		if info.battleOnly || info.mega || info.identifier == "gmax" || strings.Contains(info.identifier, "totem") {
+			continue
+		}
+		c.cache.SpeciesForms[speciesID] = append(c.cache.SpeciesForms[speciesID], info.pokemonID)
+		if englishName := c.cache.formEnglishName(info.pokemonID); englishName != "" {
+			c.cache.PokemonNamesEn[info.pokemonID] = englishName
+		}
+	}
+
+	for _, ids := range c.cache.SpeciesForms {
+		sort.Ints(ids)
+	}
+	return nil
+}
+
+// formEnglishName 形态的 Showdown 英文名称（种类英文名加形态后缀，如 Ninetales-Alola），调用方需持有锁
+func (dc *DataCache) formEnglishName(pokemonID int) string {
+	speciesID := dc.PokemonSpecies[pokemonID]
+	speciesName := dc.PokemonNamesEn[speciesID]
+	suffix := strings.TrimPrefix(dc.PokemonIdentifiers[pokemonID], dc.PokemonIdentifiers[speciesID]+"-")
+	if speciesName == "" || suffix == "" || suffix == dc.PokemonIdentifiers[pokemonID] {
+		return ""
+	}
+	parts := strings.Split(suffix, "-")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return speciesName + "-" + strings.Join(parts, "-")
+}
+
+// selectablePokemon 获取可选择的宝可梦（默认形态或可选择的非默认形态，调用方需持有读锁）
+func (dc *DataCache) selectablePokemon(pokemonID int) *entity.Pokemon {
+	if pokemon := dc.Pokemon[pokemonID]; pokemon != nil {
+		return pokemon
+	}
+	for _, formID := range dc.SpeciesForms[dc.PokemonSpecies[pokemonID]] {
+		if formID == pokemonID {
+			return dc.Forms[pokemonID]
+		}
+	}
+	return nil
+}
+
+// allSelectablePokemon 获取全部可选择的宝可梦（按全国图鉴编号排序，形态排在所属种类之后，调用方需持有读锁）
+func (dc *DataCache) allSelectablePokemon() []*entity.Pokemon {
+	result := make([]*entity.Pokemon, 0, len(dc.Pokemon))
+	for _, p := range dc.Pokemon {
+		result = append(result, p)
+	}
+	for _, ids := range dc.SpeciesForms {
+		for _, id := range ids {
+			result = append(result, dc.Forms[id])
+		}
+	}
+	sortPokemon(result)
+	return result
+}
+
+// sortPokemon 按全国图鉴编号排序，同一种类的形态按宝可梦ID排序
+func sortPokemon(pokemons []*entity.Pokemon) {
+	sort.Slice(pokemons, func(i, j int) bool {
+		if a, b := pokemons[i].DexNumber(), pokemons[j].DexNumber(); a != b {
+			return a < b
+		}
+		return pokemons[i].ID < pokemons[j].ID
+	})
+}
+
+// GetFormData 获取形态数据（包括对战专属形态，供形态变化类特性查询）
+func GetFormData(pokemonID int) *ability.FormData {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+
+	pokemon := defaultClient.cache.pokemonOrForm(pokemonID)
+	if pokemon == nil {
+		return nil
+	}
+	return &ability.FormData{
+		ID:    pokemon.ID,
+		Name:  pokemon.Name,
+		Types: append([]valueobject.PokeType{}, pokemon.Types...),
+		BaseStats: map[string]int{
+			"hp":    pokemon.BaseHP,
+			"atk":   pokemon.BaseAtk,
+			"def":   pokemon.BaseDef,
+			"spatk": pokemon.BaseSpAtk,
+			"spdef": pokemon.BaseSpDef,
+			"speed": pokemon.BaseSpeed,
+		},
+		SpriteURL: pokemon.SpriteURL,
+	}
+}
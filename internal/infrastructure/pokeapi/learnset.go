@@ -0,0 +1,278 @@
+package pokeapi
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/user/dcminigames/internal/domain/pokemon/entity"
+	"github.com/user/dcminigames/internal/domain/pokemon/valueobject"
+)
+
+// 可学技能
+// pokemon_moves.csv 的每条记录表示「宝可梦在某个版本组通过某种方式学会某个技能」。
+// 图鉴保留全部记录（版本组、学习方式、等级），加载后按可学技能范围（全国图鉴、朱紫等）
+// 生成各宝可梦的可学技能列表，列表中的技能为共享的技能模板。
+
+// MoveLearnRecord 可学技能记录（使用紧凑的整数类型，减少数十万条记录占用的内存）
+type MoveLearnRecord struct {
+	MoveID       uint16
+	VersionGroup uint8
+	Method       uint8
+	Level        uint8
+}
+
+// learnsetVersionGroups 各可学技能范围包含的版本组（PokeAPI version_groups 标识符）
+// 未列出的范围（全国图鉴）包含全部版本组
+var learnsetVersionGroups = map[valueobject.LearnsetFormat][]string{
+	valueobject.LearnsetSV: {"scarlet-violet", "the-teal-mask", "the-indigo-disk"},
+}
+
+// learnset 宝可梦在某个可学技能范围内的可学技能
+type learnset struct {
+	moves  []*entity.Move          // 可学技能（共享的技能模板，按技能ID排序）
+	learns []valueobject.MoveLearn // 学习方式（与 moves 一一对应）
+}
+
+// loadVersionGroups 加载版本组
+func (c *Client) loadVersionGroups(ctx context.Context) error {
+	records, err := c.fetchCSV(ctx, "version_groups.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: id,identifier,generation_id,order
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		id, _ := strconv.Atoi(record[0])
+		if id > 0 {
+			c.cache.VersionGroups[id] = record[1]
+		}
+	}
+	return nil
+}
+
+// loadPokemonMoves 加载宝可梦可学技能记录
+func (c *Client) loadPokemonMoves(ctx context.Context) error {
+	records, err := c.fetchCSV(ctx, "pokemon_moves.csv")
+	if err != nil {
+		return err
+	}
+
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+
+	// CSV格式: pokemon_id,version_group_id,move_id,pokemon_move_method_id,level,order
+	for _, record := range records {
+		if len(record) < 5 {
+			continue
+		}
+		pokemonID, _ := strconv.Atoi(record[0])
+		versionGroupID, _ := strconv.Atoi(record[1])
+		moveID, _ := strconv.Atoi(record[2])
+		methodID, _ := strconv.Atoi(record[3])
+		level, _ := strconv.Atoi(record[4])
+
+		if pokemonID <= 0 || moveID <= 0 || moveID > math.MaxUint16 {
+			continue
+		}
+		if pokemonID > maxPokemonID && c.cache.Forms[pokemonID] == nil {
+			continue
+		}
+		if versionGroupID <= 0 || versionGroupID > math.MaxUint8 || methodID <= 0 || methodID > math.MaxUint8 {
+			continue
+		}
+		if level < 0 || level > math.MaxUint8 {
+			level = 0
+		}
+
+		c.cache.PokemonMoves[pokemonID] = append(c.cache.PokemonMoves[pokemonID], MoveLearnRecord{
+			MoveID:       uint16(moveID),
+			VersionGroup: uint8(versionGroupID),
+			Method:       uint8(methodID),
+			Level:        uint8(level),
+		})
+	}
+	return nil
+}
+
+// buildLearnsets 创建共享的技能模板与各范围内宝可梦的可学技能（图鉴加载后调用，调用方需持有写锁）
+// 每个技能只创建一份模板，所有宝可梦的可学技能都引用同一份，对战时只复制PP
+func (dc *DataCache) buildLearnsets() {
+	dc.moveTemplates = make(map[int]*entity.Move, len(dc.Moves))
+	for moveID, data := range dc.Moves {
+		name := dc.MoveNames[moveID]
+		if data == nil || name == "" {
+			continue
+		}
+		data.Name = name
+		dc.moveTemplates[moveID] = &entity.Move{MoveData: data, PP: data.MaxPP}
+	}
+
+	// 相同的学习方式组合共享同一个切片
+	methodSets := make(map[uint64][]valueobject.MoveLearnMethod)
+
+	dc.learnsets = make(map[valueobject.LearnsetFormat]map[int]*learnset, len(valueobject.LearnsetFormats))
+	for _, format := range valueobject.LearnsetFormats {
+		groups := dc.versionGroupsOf(format)
+		sets := make(map[int]*learnset)
+		for pokemonID, records := range dc.PokemonMoves {
+			if set := dc.buildLearnset(records, groups, methodSets); set != nil {
+				sets[pokemonID] = set
+			}
+		}
+		dc.learnsets[format] = sets
+	}
+}
+
+// versionGroupsOf 获取可学技能范围包含的版本组（nil 表示全部版本组）
+func (dc *DataCache) versionGroupsOf(format valueobject.LearnsetFormat) map[uint8]bool {
+	identifiers, ok := learnsetVersionGroups[format]
+	if !ok {
+		return nil
+	}
+	groups := make(map[uint8]bool)
+	for id, identifier := range dc.VersionGroups {
+		for _, want := range identifiers {
+			if identifier == want && id <= math.MaxUint8 {
+				groups[uint8(id)] = true
+			}
+		}
+	}
+	return groups
+}
+
+// buildLearnset 按版本组筛选可学技能记录，合并同一技能的学习方式（没有可学技能时返回 nil）
+func (dc *DataCache) buildLearnset(records []MoveLearnRecord, groups map[uint8]bool, methodSets map[uint64][]valueobject.MoveLearnMethod) *learnset {
+	type learnEntry struct {
+		methods uint64 // 学习方式位掩码
+		level   int    // 升级学会的最低等级
+	}
+	entries := make(map[int]*learnEntry)
+	for _, record := range records {
+		if groups != nil && !groups[record.VersionGroup] {
+			continue
+		}
+		moveID := int(record.MoveID)
+		if dc.moveTemplates[moveID] == nil || record.Method >= 64 {
+			continue
+		}
+		entry := entries[moveID]
+		if entry == nil {
+			entry = &learnEntry{}
+			entries[moveID] = entry
+		}
+		entry.methods |= 1 << record.Method
+		if valueobject.MoveLearnMethod(record.Method) == valueobject.LearnLevelUp {
+			level := int(record.Level)
+			if entry.level == 0 || (level > 0 && level < entry.level) {
+				entry.level = level
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	moveIDs := make([]int, 0, len(entries))
+	for moveID := range entries {
+		moveIDs = append(moveIDs, moveID)
+	}
+	sort.Ints(moveIDs)
+
+	set := &learnset{
+		moves:  make([]*entity.Move, 0, len(moveIDs)),
+		learns: make([]valueobject.MoveLearn, 0, len(moveIDs)),
+	}
+	for _, moveID := range moveIDs {
+		entry := entries[moveID]
+		set.moves = append(set.moves, dc.moveTemplates[moveID])
+		set.learns = append(set.learns, valueobject.MoveLearn{
+			MoveID:  moveID,
+			Methods: methodList(methodSets, entry.methods),
+			Level:   entry.level,
+		})
+	}
+	return set
+}
+
+// methodList 将学习方式位掩码转换为列表（相同组合复用同一个切片）
+func methodList(methodSets map[uint64][]valueobject.MoveLearnMethod, mask uint64) []valueobject.MoveLearnMethod {
+	if methods, ok := methodSets[mask]; ok {
+		return methods
+	}
+	var methods []valueobject.MoveLearnMethod
+	for method := 0; method < 64; method++ {
+		if mask&(1<<method) != 0 {
+			methods = append(methods, valueobject.MoveLearnMethod(method))
+		}
+	}
+	methodSets[mask] = methods
+	return methods
+}
+
+// learnsetOf 获取宝可梦在可学技能范围内的可学技能（调用方需持有读锁）
+// 没有单独可学技能记录的形态使用所属种类的可学技能
+func (dc *DataCache) learnsetOf(pokemonID int, format valueobject.LearnsetFormat) *learnset {
+	if set := dc.learnsets[format][pokemonID]; set != nil {
+		return set
+	}
+	if _, hasOwn := dc.PokemonMoves[pokemonID]; hasOwn {
+		return nil
+	}
+	if speciesID := dc.PokemonSpecies[pokemonID]; speciesID != pokemonID {
+		return dc.learnsets[format][speciesID]
+	}
+	return nil
+}
+
+// availableIn 宝可梦在可学技能范围内是否可用（全国图鉴总是可用，调用方需持有读锁）
+func (dc *DataCache) availableIn(pokemonID int, format valueobject.LearnsetFormat) bool {
+	if format == valueobject.LearnsetNational {
+		return true
+	}
+	return dc.learnsetOf(pokemonID, format) != nil
+}
+
+// GetMoveLearn 获取宝可梦在可学技能范围内学会某个技能的方式
+func GetMoveLearn(pokemonID, moveID int, format valueobject.LearnsetFormat) (valueobject.MoveLearn, bool) {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+
+	set := defaultClient.cache.learnsetOf(pokemonID, format)
+	if set == nil {
+		return valueobject.MoveLearn{}, false
+	}
+	return set.find(moveID)
+}
+
+// CanLearnMove 宝可梦在可学技能范围内能否学会技能
+// 全国图鉴中没有技能数据的宝可梦只能使用默认技能，不做限制
+func CanLearnMove(pokemonID, moveID int, format valueobject.LearnsetFormat) bool {
+	defaultClient.cache.mu.RLock()
+	defer defaultClient.cache.mu.RUnlock()
+
+	set := defaultClient.cache.learnsetOf(pokemonID, format)
+	if set == nil {
+		return format == valueobject.LearnsetNational
+	}
+	_, ok := set.find(moveID)
+	return ok
+}
+
+// find 查找学会某个技能的方式
+func (s *learnset) find(moveID int) (valueobject.MoveLearn, bool) {
+	idx := sort.Search(len(s.learns), func(i int) bool {
+		return s.learns[i].MoveID >= moveID
+	})
+	if idx < len(s.learns) && s.learns[idx].MoveID == moveID {
+		return s.learns[idx], true
+	}
+	return valueobject.MoveLearn{}, false
+}
@@ -0,0 +1,103 @@
+package pokeapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// snapshotFS 内置的 CSV 快照（gzip 压缩，由 `bot snapshot` 命令生成）
+//
+//go:embed snapshot
+var snapshotFS embed.FS
+
+// readSnapshot 从内置快照读取 CSV 文件内容
+func readSnapshot(filename string) ([]byte, error) {
+	file, err := snapshotFS.Open(path.Join("snapshot", filename+".gz"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("解压快照失败: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// HasSnapshot 检查内置快照是否包含全部 CSV 文件
+func HasSnapshot() bool {
+	for _, filename := range csvFiles {
+		if _, err := snapshotFS.Open(path.Join("snapshot", filename+".gz")); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// HasEmbeddedData 检查程序是否内置了可离线使用的数据（完整的 CSV 快照或预编译图鉴）
+func HasEmbeddedData() bool {
+	if _, err := snapshotFS.Open(path.Join("snapshot", dexFilename)); err == nil {
+		return true
+	}
+	return HasSnapshot()
+}
+
+// BakeSnapshot 获取全部 CSV 文件并以 gzip 格式写入快照目录，同时生成内置图鉴（重新编译后内置到程序中）
+// 数据来源与正常加载一致：优先联网获取最新数据，失败时使用本地缓存
+func BakeSnapshot(ctx context.Context, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建快照目录失败: %w", err)
+	}
+
+	for _, filename := range csvFiles {
+		data, err := defaultClient.readCSV(ctx, filename)
+		if err != nil {
+			return fmt.Errorf("获取 %s 失败: %w", filename, err)
+		}
+
+		var buf bytes.Buffer
+		writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return fmt.Errorf("压缩 %s 失败: %w", filename, err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("压缩 %s 失败: %w", filename, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, filename+".gz"), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("写入快照失败: %w", err)
+		}
+	}
+	return bakeDex(ctx, dir)
+}
+
+// bakeDex 从 CSV 解析图鉴并写入快照目录（作为内置图鉴）
+func bakeDex(ctx context.Context, dir string) error {
+	client := NewClient()
+	client.options = defaultClient.options
+	if err := client.loadCSVData(ctx); err != nil {
+		return fmt.Errorf("解析图鉴失败: %w", err)
+	}
+	client.cache.prepare()
+
+	var buf bytes.Buffer
+	if err := encodeDex(&buf, &client.cache.Dex); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, dexFilename), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("写入图鉴失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CSV 数据源
+// 获取顺序：未过期的本地缓存 → 网络（带 ETag 校验，未变化时沿用缓存）→ 过期的本地缓存 → 内置快照
+// 离线模式下不访问网络，只使用本地缓存与内置快照
+
+// Options 数据源配置
+type Options struct {
+	CacheDir string        // CSV 缓存目录（空字符串表示不缓存）
+	MaxAge   time.Duration // 缓存有效期，过期后联网校验（0 表示每次启动都校验）
+	Offline  bool          // 离线模式（不访问网络）
+}
+
+// csvFiles 加载数据所需的全部 CSV 文件（生成内置快照时使用，新增 CSV 时需同步添加）
+var csvFiles = []string{
+	"type_names.csv",
+	"pokemon_species_names.csv",
+	"move_names.csv",
+	"moves.csv",
+	"move_meta.csv",
+	"move_meta_stat_changes.csv",
+	"move_meta_ailments.csv",
+	"pokemon.csv",
+	"pokemon_stats.csv",
+	"pokemon_types.csv",
+	"pokemon_species.csv",
+	"pokemon_evolution.csv",
+	"pokemon_moves.csv",
+	"version_groups.csv",
+	"ability_names.csv",
+	"ability_flavor_text.csv",
+	"pokemon_abilities.csv",
+	"pokemon_forms.csv",
+	"pokemon_form_names.csv",
+	"items.csv",
+	"item_names.csv",
+}
+
+// Configure 设置默认客户端的数据源（需在加载数据前调用）
+func Configure(options Options) {
+	defaultClient.options = options
+}
+
+// readCSV 获取 CSV 文件内容
+func (c *Client) readCSV(ctx context.Context, filename string) ([]byte, error) {
+	cached, fresh := c.readCache(filename)
+	if cached != nil && (fresh || c.options.Offline) {
+		return cached, nil
+	}
+
+	var downloadErr error
+	if !c.options.Offline {
+		data, err := c.download(ctx, filename, cached != nil)
+		if err == nil {
+			if data == nil {
+				// 远端未变化，沿用缓存
+				return cached, nil
+			}
+			return data, nil
+		}
+		downloadErr = err
+	}
+
+	if cached != nil {
+		log.Printf("下载 %s 失败，使用过期的本地缓存: %v", filename, downloadErr)
+		return cached, nil
+	}
+	if data, err := readSnapshot(filename); err == nil {
+		if downloadErr != nil {
+			log.Printf("下载 %s 失败，使用内置快照: %v", filename, downloadErr)
+		}
+		return data, nil
+	}
+	if downloadErr != nil {
+		return nil, downloadErr
+	}
+	return nil, fmt.Errorf("离线模式下没有 %s 的本地缓存或内置快照", filename)
+}
+
+// cachePath 获取缓存文件路径
+func (c *Client) cachePath(filename string) string {
+	return filepath.Join(c.options.CacheDir, filename)
+}
+
+// readCache 读取本地缓存，返回内容（不存在时为 nil）与是否在有效期内
+func (c *Client) readCache(filename string) ([]byte, bool) {
+	if c.options.CacheDir == "" {
+		return nil, false
+	}
+	path := c.cachePath(filename)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, c.options.MaxAge > 0 && time.Since(info.ModTime()) < c.options.MaxAge
+}
+
+// download 从 GitHub 下载 CSV 并写入缓存
+// revalidate 为 true 时带上缓存的 ETag，远端未变化（304）时返回 nil 并刷新缓存时间
+func (c *Client) download(ctx context.Context, filename string, revalidate bool) ([]byte, error) {
+	url := githubCSVBase + "/" + filename
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", "DcMiniGames/1.0")
+
+	etagPath := c.cachePath(filename) + ".etag"
+	if revalidate {
+		if etag, err := os.ReadFile(etagPath); err == nil {
+			req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && revalidate {
+		now := time.Now()
+		_ = os.Chtimes(c.cachePath(filename), now, now)
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求失败: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if c.options.CacheDir != "" {
+		if err := c.writeCache(filename, data, resp.Header.Get("ETag")); err != nil {
+			log.Printf("写入 %s 缓存失败: %v", filename, err)
+		}
+	}
+	return data, nil
+}
+
+// writeCache 写入缓存文件与 ETag
+func (c *Client) writeCache(filename string, data []byte, etag string) error {
+	if err := os.MkdirAll(c.options.CacheDir, 0o755); err != nil {
+		return err
+	}
+	path := c.cachePath(filename)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if etag == "" {
+		if err := os.Remove(path + ".etag"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path+".etag", []byte(etag), 0o644)
+}
@@ -3,7 +3,6 @@ package commands
 import (
 	"fmt"
 	"log"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -32,6 +31,23 @@ func (c *PokemonCommands) Commands() []*discordgo.ApplicationCommand {
 		{
 			Name:        "pokemon",
 			Description: "宝可梦对战",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "panel",
+					Description: "打开对战面板",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "import",
+					Description: "导入 Showdown 格式的队伍",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "导出当前队伍为 Showdown 格式",
+				},
+			},
 		},
 	}
 }
@@ -41,7 +57,18 @@ func (c *PokemonCommands) HandleInteraction(s *discordgo.Session, i *discordgo.I
 	if i.Type == discordgo.InteractionApplicationCommand {
 		data := i.ApplicationCommandData()
 		if data.Name == "pokemon" {
-			c.showPanel(i)
+			subcommand := "panel"
+			if len(data.Options) > 0 {
+				subcommand = data.Options[0].Name
+			}
+			switch subcommand {
+			case "import":
+				c.handleImportModal(i)
+			case "export":
+				c.handleExport(i)
+			default:
+				c.showPanel(i)
+			}
 		}
 	} else if i.Type == discordgo.InteractionMessageComponent {
 		c.handleComponent(i)
@@ -51,11 +78,25 @@ func (c *PokemonCommands) HandleInteraction(s *discordgo.Session, i *discordgo.I
 			c.handleSearchModal(i)
 		} else if data.CustomID == "pkm:savepreset_modal" {
 			c.handleSavePresetSubmit(i)
+		} else if data.CustomID == "pkm:saveteam_modal" {
+			c.handleSaveTeamSubmit(i)
+		} else if data.CustomID == "pkm:import_modal" {
+			c.handleImportSubmit(i)
 		} else if strings.HasPrefix(data.CustomID, "pkm:searchmove_modal:") {
 			parts := strings.Split(data.CustomID, ":")
 			if len(parts) >= 3 {
 				c.handleSearchMoveModalSubmit(i, parts[2])
 			}
+		} else if strings.HasPrefix(data.CustomID, "pkm:searchitem_modal:") {
+			parts := strings.Split(data.CustomID, ":")
+			if len(parts) >= 3 {
+				c.handleSearchItemModalSubmit(i, parts[2])
+			}
+		} else if strings.HasPrefix(data.CustomID, "pkm:spread_modal:") {
+			parts := strings.Split(data.CustomID, ":")
+			if len(parts) >= 3 {
+				c.handleSpreadModalSubmit(i, parts[2])
+			}
 		}
 	}
 }
@@ -73,7 +114,7 @@ func (c *PokemonCommands) showPanel(i *discordgo.InteractionCreate) {
 		// 没有进行中的对战
 		embed = &discordgo.MessageEmbed{
 			Title:       "⚔️ 宝可梦对战",
-			Description: "当前没有进行中的对战\n选择对战模式创建新对战：\n\n**🎮 PVP 对战**\n• 单挑 (1v1) / 3v3 / 6v6\n\n**🤖 人机对战** (Debug 模式)\n• 与 AI 训练师对战，方便调试",
+			Description: "当前没有进行中的对战\n选择对战模式创建新对战：\n\n**🎮 PVP 对战**\n• 单挑 (1v1) / 3v3 / 6v6\n• 双打 (4v4)：每方同时上场 2 只\n\n**📜 规则对战**\n• 平坦单打 / 平坦双打 / VGC，启用等级上限与各项条款\n\n**🤖 人机对战** (Debug 模式)\n• 与 AI 训练师对战，方便调试",
 			Color:       0xFFCB05,
 			Thumbnail: &discordgo.MessageEmbedThumbnail{
 				URL: "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/25.gif",
@@ -85,6 +126,7 @@ func (c *PokemonCommands) showPanel(i *discordgo.InteractionCreate) {
 					discordgo.Button{Label: "⚔️ 单挑 (1v1)", Style: discordgo.SuccessButton, CustomID: "pkm:create:1"},
 					discordgo.Button{Label: "⚔️ 3v3 单打", Style: discordgo.PrimaryButton, CustomID: "pkm:create:3"},
 					discordgo.Button{Label: "⚔️ 6v6 单打", Style: discordgo.DangerButton, CustomID: "pkm:create:6"},
+					discordgo.Button{Label: "👥 4v4 双打", Style: discordgo.PrimaryButton, CustomID: "pkm:create:4:double"},
 				},
 			},
 			discordgo.ActionsRow{
@@ -92,6 +134,12 @@ func (c *PokemonCommands) showPanel(i *discordgo.InteractionCreate) {
 					discordgo.Button{Label: "🤖 人机 1v1", Style: discordgo.SecondaryButton, CustomID: "pkm:ai:1"},
 					discordgo.Button{Label: "🤖 人机 3v3", Style: discordgo.SecondaryButton, CustomID: "pkm:ai:3"},
 					discordgo.Button{Label: "🤖 人机 6v6", Style: discordgo.SecondaryButton, CustomID: "pkm:ai:6"},
+					discordgo.Button{Label: "🤖 人机双打", Style: discordgo.SecondaryButton, CustomID: "pkm:ai:4:double"},
+				},
+			},
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: "📜 规则对战", Style: discordgo.PrimaryButton, CustomID: "pkm:rules"},
 				},
 			},
 		}
@@ -113,10 +161,10 @@ func (c *PokemonCommands) buildBattlePanel(battle *entity.Battle, userID string)
 
 	switch battle.State {
 	case entity.BattleStateWaiting:
-		modeName := battle.TeamSize.GetDisplayName()
+		modeName := battle.GetDisplayName()
 		embed = &discordgo.MessageEmbed{
 			Title:       fmt.Sprintf("⚔️ 宝可梦对战 - %s", modeName),
-			Description: fmt.Sprintf("对战ID: `%s`\n模式: **%s**\n\n**玩家1:** %s\n**玩家2:** 等待中...", battle.ID[:8], modeName, battle.Player1.Username),
+			Description: fmt.Sprintf("对战ID: `%s`\n模式: **%s**\n规则: %s\n\n**玩家1:** %s\n**玩家2:** 等待中...", battle.ID[:8], modeName, battle.GetRuleSummary(), battle.Player1.Username),
 			Color:       0xFFCB05,
 		}
 		var buttons []discordgo.MessageComponent
@@ -145,11 +193,23 @@ func (c *PokemonCommands) buildBattlePanel(battle *entity.Battle, userID string)
 		}
 		components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
 
+	case entity.BattleStatePreview:
+		embed = c.buildPreviewEmbed(battle)
+		var buttons []discordgo.MessageComponent
+		if isInBattle && !player.BringConfirmed {
+			buttons = append(buttons, discordgo.Button{Label: "👀 选择出战", Style: discordgo.PrimaryButton, CustomID: "pkm:preview"})
+		}
+		buttons = append(buttons, discordgo.Button{Label: "🔄 刷新", Style: discordgo.SecondaryButton, CustomID: "pkm:refresh"})
+		if isHost {
+			buttons = append(buttons, discordgo.Button{Label: "❌ 取消", Style: discordgo.DangerButton, CustomID: "pkm:end"})
+		}
+		components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+
 	case entity.BattleStateBattling:
 		embed = c.buildBattleStatusEmbed(battle, userID)
 		var buttons []discordgo.MessageComponent
 		if isInBattle {
-			if player.Action == nil {
+			if battle.IsPlayerTurn(userID) {
 				buttons = append(buttons, discordgo.Button{Label: "⚡ 技能", Style: discordgo.PrimaryButton, CustomID: "pkm:moves"})
 				// 3v3/6v6 模式下显示换人按钮
 				if battle.TeamSize > 1 && player.HasSwitchableTeamMember() {
@@ -163,6 +223,20 @@ func (c *PokemonCommands) buildBattlePanel(battle *entity.Battle, userID string)
 		buttons = append(buttons, discordgo.Button{Label: "🔃 刷新", Style: discordgo.SecondaryButton, CustomID: "pkm:refresh"})
 		components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
 
+	case entity.BattleStateReplacing:
+		embed = c.buildBattleStatusEmbed(battle, userID)
+		var buttons []discordgo.MessageComponent
+		if isInBattle {
+			if battle.NeedsReplacement(userID) {
+				buttons = append(buttons, discordgo.Button{Label: "🔄 选择替补", Style: discordgo.PrimaryButton, CustomID: "pkm:replace"})
+				buttons = append(buttons, discordgo.Button{Label: "🏳️ 认输", Style: discordgo.DangerButton, CustomID: "pkm:forfeit"})
+			} else {
+				buttons = append(buttons, discordgo.Button{Label: "⏳ 等待对手选择替补...", Style: discordgo.SecondaryButton, CustomID: "pkm:waiting", Disabled: true})
+			}
+		}
+		buttons = append(buttons, discordgo.Button{Label: "🔃 刷新", Style: discordgo.SecondaryButton, CustomID: "pkm:refresh"})
+		components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+
 	case entity.BattleStateFinished:
 		winnerName := "无"
 		if battle.Winner != nil {
@@ -190,9 +264,9 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 	p1 := battle.Player1
 	p2 := battle.Player2
 
-	// 构建HP条
-	p1HP := c.buildHPBar(p1.Pokemon)
-	p2HP := c.buildHPBar(p2.Pokemon)
+	// 构建双方场上信息
+	p1Name, p1Value := c.buildSideField(battle, p1, "🔴")
+	p2Name, p2Value := c.buildSideField(battle, p2, "🔵")
 
 	// 获取最近的战斗日志
 	logs := ""
@@ -208,8 +282,12 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 	status := ""
 	player := battle.GetPlayer(userID)
 	if player != nil {
-		if player.Action == nil {
+		if battle.IsPlayerTurn(userID) {
 			status = "💡 请选择你的行动！"
+		} else if battle.NeedsReplacement(userID) {
+			status = "💡 请选择替补上场的宝可梦！"
+		} else if battle.State == entity.BattleStateReplacing {
+			status = "⏳ 等待对手选择替补..."
 		} else {
 			status = "⏳ 等待对手行动..."
 		}
@@ -219,8 +297,8 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 		Title: fmt.Sprintf("⚔️ 回合 %d", battle.CurrentTurn),
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:   fmt.Sprintf("🔴 %s 的 %s", p1.Username, p1.Pokemon.Pokemon.Name),
-				Value:  fmt.Sprintf("Lv.%d %s\n%s", p1.Pokemon.Level, pokeapi.GetPokemonTypeString(p1.Pokemon.Pokemon.Types), p1HP),
+				Name:   p1Name,
+				Value:  p1Value,
 				Inline: true,
 			},
 			{
@@ -229,8 +307,8 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 				Inline: true,
 			},
 			{
-				Name:   fmt.Sprintf("🔵 %s 的 %s", p2.Username, p2.Pokemon.Pokemon.Name),
-				Value:  fmt.Sprintf("Lv.%d %s\n%s", p2.Pokemon.Level, pokeapi.GetPokemonTypeString(p2.Pokemon.Pokemon.Types), p2HP),
+				Name:   p2Name,
+				Value:  p2Value,
 				Inline: true,
 			},
 		},
@@ -243,6 +321,10 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 		},
 	}
 
+	if field := buildFieldStatus(battle); field != "" {
+		embed.Description = field
+	}
+
 	if logs != "" {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:  "📜 战斗日志",
@@ -257,6 +339,51 @@ func (c *PokemonCommands) buildBattleStatusEmbed(battle *entity.Battle, userID s
 	return embed
 }
 
+// buildFieldStatus 构建全场状态（天气、场地）的显示文本，没有时返回空字符串
+func buildFieldStatus(battle *entity.Battle) string {
+	var parts []string
+	if weather := battle.GetWeatherSummary(); weather != "" {
+		parts = append(parts, "🌤️ **天气**: "+weather)
+	}
+	if terrain := battle.GetTerrainSummary(); terrain != "" {
+		parts = append(parts, "🌐 **场地**: "+terrain)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// buildSideField 构建一方场上宝可梦的显示字段（双打时列出两个位置，末尾显示场地状态）
+func (c *PokemonCommands) buildSideField(battle *entity.Battle, player *entity.BattlePlayer, marker string) (string, string) {
+	var name, value string
+	if !battle.IsDoubles() {
+		name = fmt.Sprintf("%s %s 的 %s", marker, player.Username, player.Pokemon.Pokemon.Name)
+		value = fmt.Sprintf("Lv.%d %s\n%s", player.Pokemon.Level, battlerTypeString(player.Pokemon), c.buildHPBar(player.Pokemon))
+	} else {
+		var parts []string
+		for _, battler := range player.ActiveBattlers() {
+			parts = append(parts, fmt.Sprintf("**%s** Lv.%d %s\n%s", battler.Pokemon.Name, battler.Level, battlerTypeString(battler), c.buildHPBar(battler)))
+		}
+		name = fmt.Sprintf("%s %s", marker, player.Username)
+		value = strings.Join(parts, "\n\n")
+	}
+
+	if summary := player.GetSideConditionSummary(); summary != "" {
+		value += "\n" + summary
+	}
+	return name, value
+}
+
+// battlerTypeString 获取宝可梦当前属性文本（太晶化时标注）
+func battlerTypeString(battler *entity.Battler) string {
+	typeStr := pokeapi.GetPokemonTypeString(battler.Types)
+	if battler.IsTerastalized {
+		typeStr = "💎" + typeStr
+	}
+	if battler.IsDynamaxed {
+		typeStr += fmt.Sprintf(" 🔴极巨化(剩余%d回合)", battler.DynamaxTurns)
+	}
+	return typeStr
+}
+
 // buildHPBar 构建HP条
 func (c *PokemonCommands) buildHPBar(battler *entity.Battler) string {
 	percent := battler.GetHPPercent()
@@ -270,7 +397,11 @@ func (c *PokemonCommands) buildHPBar(battler *entity.Battler) string {
 	}
 
 	bar := strings.Repeat("🟩", filled) + strings.Repeat("⬜", barLength-filled)
-	return fmt.Sprintf("%s\n❤️ %d/%d (%.0f%%)", bar, battler.CurrentHP, battler.MaxHP, percent)
+	hpText := fmt.Sprintf("%s\n❤️ %d/%d (%.0f%%)", bar, battler.CurrentHP, battler.MaxHP, percent)
+	if battler.Status != entity.StatusNone {
+		hpText += fmt.Sprintf(" [%s]", battler.Status)
+	}
+	return hpText
 }
 
 // handleComponent 处理组件交互
@@ -300,7 +431,7 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 				teamSize = size
 			}
 		}
-		c.handleCreate(i, channelID, userID, username, teamSize)
+		c.handleCreate(i, channelID, userID, username, teamSize, parseBattleMode(parts))
 	case "ai":
 		teamSize := 1
 		if len(parts) >= 3 {
@@ -308,13 +439,21 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 				teamSize = size
 			}
 		}
-		c.handleCreateAI(i, channelID, userID, username, teamSize)
+		c.handleCreateAI(i, channelID, userID, username, teamSize, parseBattleMode(parts))
+	case "rules":
+		c.handleShowRulesets(i)
+	case "ruleset":
+		if len(parts) >= 4 {
+			c.handleCreateWithRuleset(i, channelID, userID, username, parts[2], parts[3] == "ai")
+		}
 	case "join":
 		c.handleJoin(i, channelID, userID, username)
 	case "select":
 		c.handleSelectMenu(i)
 	case "search":
 		c.handleSearch(i)
+	case "import":
+		c.handleImportModal(i)
 	case "browse":
 		pageStr := "1"
 		if len(parts) >= 3 {
@@ -341,6 +480,42 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 		if len(parts) >= 4 {
 			c.handleSetAbility(i, channelID, userID, parts[2], parts[3])
 		}
+	case "config":
+		if len(parts) >= 3 {
+			c.handleShowConfig(i, channelID, userID, parts[2])
+		}
+	case "item":
+		if len(parts) >= 3 {
+			filter, pageStr := "all", "1"
+			if len(parts) >= 5 {
+				filter, pageStr = parts[3], parts[4]
+			}
+			c.handleItemSelect(i, channelID, userID, parts[2], filter, pageStr)
+		}
+	case "searchitem":
+		if len(parts) >= 3 {
+			c.handleSearchItemModal(i, parts[2])
+		}
+	case "gimmickitem":
+		if len(parts) >= 3 {
+			c.handleGimmickItemSelect(i, channelID, userID, parts[2])
+		}
+	case "setitem":
+		if len(parts) >= 4 {
+			c.handleSetItem(i, channelID, userID, parts[2], parts[3])
+		}
+	case "spread":
+		if len(parts) >= 3 {
+			c.handleSpreadPanel(i, channelID, userID, parts[2])
+		}
+	case "setspread":
+		if len(parts) >= 4 {
+			c.handleSetSpread(i, channelID, userID, parts[2], parts[3])
+		}
+	case "spreadedit":
+		if len(parts) >= 3 {
+			c.handleSpreadModal(i, channelID, userID, parts[2])
+		}
 	case "confirm":
 		if len(parts) >= 3 {
 			c.handleConfirmPokemon(i, channelID, userID, parts[2])
@@ -357,6 +532,10 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 		if len(parts) >= 5 {
 			c.handleSetMove(i, channelID, userID, parts[2], parts[3], parts[4])
 		}
+	case "learnset":
+		if len(parts) >= 3 {
+			c.handleCycleLearnset(i, channelID, userID, parts[2])
+		}
 	case "confirmmoves":
 		if len(parts) >= 3 {
 			c.handleConfirmMoves(i, channelID, userID, parts[2])
@@ -370,22 +549,64 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 			c.handleSelectSearchedMove(i, channelID, userID, parts[2], parts[3])
 		}
 	case "presets":
-		c.handleShowPresets(i, userID)
+		c.handleShowPresets(i, channelID, userID)
+	case "preset":
+		if len(parts) >= 3 {
+			selected := "0"
+			if len(parts) >= 4 {
+				selected = parts[3]
+			}
+			c.handlePresetDetail(i, channelID, userID, parts[2], selected)
+		}
 	case "loadpreset":
+		if len(parts) >= 4 {
+			c.handleLoadPreset(i, channelID, userID, parts[2], parts[3])
+		}
+	case "loadteam":
 		if len(parts) >= 3 {
-			c.handleLoadPreset(i, channelID, userID, parts[2])
+			c.handleLoadPresetTeam(i, channelID, userID, parts[2])
 		}
 	case "savepreset":
 		c.handleSavePresetModal(i, channelID, userID)
+	case "saveteam":
+		c.handleSaveTeamModal(i, channelID, userID)
+	case "presetadd":
+		if len(parts) >= 3 {
+			c.handleAddToPreset(i, channelID, userID, parts[2])
+		}
+	case "presetmove":
+		if len(parts) >= 5 {
+			c.handleMovePresetMember(i, channelID, userID, parts[2], parts[3], parts[4])
+		}
+	case "presetrm":
+		if len(parts) >= 4 {
+			c.handleRemovePresetMember(i, channelID, userID, parts[2], parts[3])
+		}
+	case "presetdup":
+		if len(parts) >= 3 {
+			c.handleDuplicatePreset(i, channelID, userID, parts[2])
+		}
+	case "presettag":
+		if len(parts) >= 3 {
+			c.handleCyclePresetFormat(i, channelID, userID, parts[2])
+		}
 	case "delpreset":
 		if len(parts) >= 3 {
-			c.handleDeletePreset(i, userID, parts[2])
+			c.handleDeletePreset(i, channelID, userID, parts[2])
 		}
 	case "moves":
 		c.handleShowMoves(i, channelID, userID)
 	case "move":
 		if len(parts) >= 3 {
-			c.handleUseMove(i, channelID, userID, parts[2])
+			c.handleUseMove(i, channelID, userID, parts[2], parseGimmick(parts, 3))
+		}
+	case "target":
+		if len(parts) >= 4 {
+			c.handleSelectTarget(i, channelID, userID, parts[2], parts[3], parseGimmick(parts, 4))
+		}
+	case "gimmick":
+		if len(parts) >= 3 {
+			c.handleToggleGimmick(i, channelID, userID, parseGimmick(parts, 2))
 		}
 	case "forfeit":
 		c.handleForfeit(i, channelID, userID)
@@ -395,6 +616,8 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 		c.handleEnd(i, channelID, userID)
 	case "switch":
 		c.handleShowSwitchMenu(i, channelID, userID)
+	case "replace":
+		c.handleShowReplacementMenu(i, channelID, userID)
 	case "doswitch":
 		if len(parts) >= 3 {
 			c.handleDoSwitch(i, channelID, userID, parts[2])
@@ -403,11 +626,21 @@ func (c *PokemonCommands) handleComponent(i *discordgo.InteractionCreate) {
 		if len(parts) >= 3 {
 			c.handleForceSwitch(i, channelID, userID, parts[2])
 		}
+	case "preview":
+		c.handleShowBringMenu(i, channelID, userID, false)
+	case "bring":
+		if len(parts) >= 3 {
+			c.handleToggleBring(i, channelID, userID, parts[2])
+		}
+	case "bringreset":
+		c.handleResetBring(i, channelID, userID)
+	case "bringok":
+		c.handleConfirmBring(i, channelID, userID)
 	}
 }
 
 // handleCreate 创建对战
-func (c *PokemonCommands) handleCreate(i *discordgo.InteractionCreate, channelID, userID, username string, teamSize int) {
+func (c *PokemonCommands) handleCreate(i *discordgo.InteractionCreate, channelID, userID, username string, teamSize int, mode valueobject.BattleMode) {
 	// 先结束可能存在的旧对战
 	c.handler.EndBattle(channelID)
 
@@ -416,23 +649,25 @@ func (c *PokemonCommands) handleCreate(i *discordgo.InteractionCreate, channelID
 	switch teamSize {
 	case 3:
 		ts = entity.TeamSize3v3
+	case 4:
+		ts = entity.TeamSize4v4
 	case 6:
 		ts = entity.TeamSize6v6
 	default:
 		ts = entity.TeamSize1v1
 	}
 
-	battle, err := c.handler.CreateBattleWithTeamSize(channelID, userID, username, ts)
+	battle, err := c.handler.CreateBattleWithMode(channelID, userID, username, ts, mode)
 	if err != nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
 	}
-	modeName := ts.GetDisplayName()
+	modeName := battle.GetDisplayName()
 	c.bot.RespondPublic(i.Interaction, fmt.Sprintf("⚔️ **%s** 创建了 **%s** 宝可梦对战！\n对战ID: `%s`\n使用 `/pokemon` 加入对战", username, modeName, battle.ID[:8]))
 }
 
 // handleCreateAI 创建人机对战
-func (c *PokemonCommands) handleCreateAI(i *discordgo.InteractionCreate, channelID, userID, username string, teamSize int) {
+func (c *PokemonCommands) handleCreateAI(i *discordgo.InteractionCreate, channelID, userID, username string, teamSize int, mode valueobject.BattleMode) {
 	// 先结束可能存在的旧对战
 	c.handler.EndBattle(channelID)
 
@@ -441,13 +676,15 @@ func (c *PokemonCommands) handleCreateAI(i *discordgo.InteractionCreate, channel
 	switch teamSize {
 	case 3:
 		ts = entity.TeamSize3v3
+	case 4:
+		ts = entity.TeamSize4v4
 	case 6:
 		ts = entity.TeamSize6v6
 	default:
 		ts = entity.TeamSize1v1
 	}
 
-	battle, err := c.handler.CreateAIBattle(channelID, userID, username, ts)
+	battle, err := c.handler.CreateAIBattleWithMode(channelID, userID, username, ts, mode)
 	if err != nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
@@ -464,10 +701,74 @@ func (c *PokemonCommands) handleCreateAI(i *discordgo.InteractionCreate, channel
 		aiPokemonInfo = fmt.Sprintf("\n🤖 AI 已选择: **%s**", strings.Join(names, "、"))
 	}
 
-	modeName := ts.GetDisplayName()
+	modeName := battle.GetDisplayName()
 	c.bot.RespondPublic(i.Interaction, fmt.Sprintf("🤖 **%s** 创建了 **%s** 人机对战！\n对战ID: `%s`%s\n\n请选择你的宝可梦开始对战！", username, modeName, battle.ID[:8], aiPokemonInfo))
 }
 
+// handleShowRulesets 显示规则对战的规则预设菜单（私密）
+func (c *PokemonCommands) handleShowRulesets(i *discordgo.InteractionCreate) {
+	var lines []string
+	var rows []discordgo.MessageComponent
+	for _, id := range valueobject.RulesetIDs {
+		config := valueobject.ConfigForRuleset(id)
+		name := valueobject.RulesetDisplayName(id)
+		lines = append(lines, fmt.Sprintf("**%s** — %s · 带 %d 只 · Lv.%d · 📚 %s\n条款: %s",
+			name, config.Mode.DisplayName(), config.BringCount, config.LevelCap, config.Learnset.DisplayName(), strings.Join(config.ClauseNames(), "、")))
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "⚔️ " + name, Style: discordgo.PrimaryButton, CustomID: "pkm:ruleset:" + id + ":pvp"},
+				discordgo.Button{Label: "🤖 人机 " + name, Style: discordgo.SecondaryButton, CustomID: "pkm:ruleset:" + id + ":ai"},
+			},
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📜 规则对战",
+		Description: "选择规则创建对战，不符合规则的队伍将无法提交：\n\n" + strings.Join(lines, "\n\n"),
+		Color:       0xFFCB05,
+	}
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
+
+// handleCreateWithRuleset 按规则预设创建对战
+func (c *PokemonCommands) handleCreateWithRuleset(i *discordgo.InteractionCreate, channelID, userID, username, rulesetID string, isAI bool) {
+	config := valueobject.ConfigForRuleset(rulesetID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未知的对战规则")
+		return
+	}
+
+	// 先结束可能存在的旧对战
+	c.handler.EndBattle(channelID)
+
+	var battle *entity.Battle
+	var err error
+	if isAI {
+		battle, err = c.handler.CreateAIBattleWithConfig(channelID, userID, username, config)
+	} else {
+		battle, err = c.handler.CreateBattleWithConfig(channelID, userID, username, config)
+	}
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	rulesetName := valueobject.RulesetDisplayName(rulesetID)
+	if isAI {
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("🤖 **%s** 创建了 **%s** 人机对战！\n对战ID: `%s`\n规则: %s\n\n请选择你的宝可梦开始对战！", username, rulesetName, battle.ID[:8], battle.GetRuleSummary()))
+		return
+	}
+	c.bot.RespondPublic(i.Interaction, fmt.Sprintf("⚔️ **%s** 创建了 **%s** 宝可梦对战！\n对战ID: `%s`\n规则: %s\n使用 `/pokemon` 加入对战", username, rulesetName, battle.ID[:8], battle.GetRuleSummary()))
+}
+
+// parseBattleMode 从按钮 ID 中解析对战模式（pkm:create:<队伍大小>:<模式>）
+func parseBattleMode(parts []string) valueobject.BattleMode {
+	if len(parts) >= 4 && parts[3] == string(valueobject.ModeDouble) {
+		return valueobject.ModeDouble
+	}
+	return valueobject.ModeSingle
+}
+
 // handleJoin 加入对战
 func (c *PokemonCommands) handleJoin(i *discordgo.InteractionCreate, channelID, userID, username string) {
 	if err := c.handler.JoinBattle(channelID, userID, username); err != nil {
@@ -526,6 +827,8 @@ func (c *PokemonCommands) handleSelectMenu(i *discordgo.InteractionCreate) {
 	actionButtons := []discordgo.MessageComponent{
 		discordgo.Button{Label: "🔍 搜索宝可梦", Style: discordgo.PrimaryButton, CustomID: "pkm:search"},
 		discordgo.Button{Label: "📖 浏览图鉴", Style: discordgo.SecondaryButton, CustomID: "pkm:browse:1"},
+		discordgo.Button{Label: "📥 导入队伍", Style: discordgo.SecondaryButton, CustomID: "pkm:import"},
+		discordgo.Button{Label: "📋 配队预设", Style: discordgo.SecondaryButton, CustomID: "pkm:presets"},
 	}
 
 	rows := []discordgo.MessageComponent{
@@ -537,6 +840,124 @@ func (c *PokemonCommands) handleSelectMenu(i *discordgo.InteractionCreate) {
 	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
 }
 
+// handleImportModal 显示导入队伍模态框
+func (c *PokemonCommands) handleImportModal(i *discordgo.InteractionCreate) {
+	err := c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "pkm:import_modal",
+			Title:    "📥 导入 Showdown 队伍",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "team",
+							Label:       "粘贴 Showdown 格式的队伍",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "Garchomp @ Choice Scarf\nAbility: Rough Skin\nEVs: 252 Atk / 4 SpD / 252 Spe\nJolly Nature\n- Earthquake",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   4000,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("显示导入队伍模态框失败: %v", err)
+	}
+}
+
+// handleImportSubmit 处理导入队伍模态框提交
+func (c *PokemonCommands) handleImportSubmit(i *discordgo.InteractionCreate) {
+	channelID := i.ChannelID
+	userID := i.Member.User.ID
+
+	data := i.ModalSubmitData()
+	var text string
+	for _, row := range data.Components {
+		if ar, ok := row.(*discordgo.ActionsRow); ok {
+			for _, comp := range ar.Components {
+				if ti, ok := comp.(*discordgo.TextInput); ok && ti.CustomID == "team" {
+					text = ti.Value
+				}
+			}
+		}
+	}
+
+	builds, warnings, err := c.handler.ImportTeam(channelID, userID, text)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 导入失败："+err.Error())
+		return
+	}
+
+	c.respondTeamAdded(i, channelID, userID, fmt.Sprintf("📥 已导入 %d 只宝可梦", len(builds)), builds, warnings)
+}
+
+// respondTeamAdded 整队加入后回复加入结果，并在对战开始或进入预览时发送对应面板
+func (c *PokemonCommands) respondTeamAdded(i *discordgo.InteractionCreate, channelID, userID, title string, builds []*entity.PokemonBuild, warnings []string) {
+	battle, _ := c.handler.GetBattle(channelID)
+	if battle == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
+		return
+	}
+	player := battle.GetPlayer(userID)
+	if player == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
+		return
+	}
+
+	var desc strings.Builder
+	for _, build := range builds {
+		desc.WriteString(fmt.Sprintf("• **%s** Lv.%d", build.Pokemon.Name, build.Level))
+		if build.Item != nil {
+			desc.WriteString(" @ " + build.Item.Name)
+		}
+		desc.WriteString("\n")
+	}
+	if len(warnings) > 0 {
+		desc.WriteString("\n⚠️ **提示**\n")
+		for _, warning := range warnings {
+			desc.WriteString("• " + warning + "\n")
+		}
+	}
+	desc.WriteString(fmt.Sprintf("\n📋 队伍进度: %d/%d", len(player.Roster), int(battle.TeamSize)))
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: desc.String(),
+		Color:       0x2ECC71,
+	}
+	c.bot.RespondWithEmbed(i.Interaction, embed, nil, true)
+
+	switch battle.State {
+	case entity.BattleStateBattling:
+		// 双方都已准备好，对战开始
+		c.sendBattlePanel(i, channelID)
+	case entity.BattleStatePreview:
+		// 双方队伍登记完毕，进入队伍预览
+		c.sendPreviewPanel(channelID)
+	}
+}
+
+// handleExport 导出当前队伍为 Showdown 格式
+func (c *PokemonCommands) handleExport(i *discordgo.InteractionCreate) {
+	text, err := c.handler.ExportTeam(i.ChannelID, i.Member.User.ID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📤 导出队伍",
+		Description: "```\n" + text + "```",
+		Color:       0x3498DB,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "💡 复制后可直接粘贴到 Pokémon Showdown 的队伍编辑器"},
+	}
+	c.bot.RespondWithEmbed(i.Interaction, embed, nil, true)
+}
+
 // handleSearch 处理搜索请求（显示模态框）
 func (c *PokemonCommands) handleSearch(i *discordgo.InteractionCreate) {
 	err := c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -585,9 +1006,14 @@ func (c *PokemonCommands) handleSearchModal(i *discordgo.InteractionCreate) {
 		return
 	}
 
-	// 搜索宝可梦
-	results := pokeapi.SearchPredefinedPokemon(keyword)
+	// 搜索当前对战可学技能范围内的宝可梦
+	format := c.handler.LearnsetFormatFor(i.ChannelID)
+	results := c.handler.SearchPokemon(keyword, format)
 	if len(results) == 0 {
+		if format != valueobject.LearnsetNational {
+			c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("❌ 「%s」范围内未找到匹配「%s」的宝可梦", format.DisplayName(), keyword))
+			return
+		}
 		c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("❌ 未找到匹配「%s」的宝可梦", keyword))
 		return
 	}
@@ -604,9 +1030,9 @@ func (c *PokemonCommands) handleSearchModal(i *discordgo.InteractionCreate) {
 	var buttons []discordgo.MessageComponent
 	for _, p := range results {
 		typeStr := pokeapi.GetPokemonTypeString(p.Types)
-		desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n", p.ID, p.Name, typeStr))
+		desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n", p.DexNumber(), p.Name, typeStr))
 		buttons = append(buttons, discordgo.Button{
-			Label:    fmt.Sprintf("#%d %s", p.ID, p.Name),
+			Label:    fmt.Sprintf("#%d %s", p.DexNumber(), p.Name),
 			Style:    discordgo.PrimaryButton,
 			CustomID: fmt.Sprintf("pkm:choose:%d", p.ID),
 		})
@@ -645,10 +1071,8 @@ func (c *PokemonCommands) handleBrowse(i *discordgo.InteractionCreate, pageStr s
 		page = 1
 	}
 
+	// 已按全国图鉴编号排序，形态排在所属种类之后
 	pokemons := c.handler.GetAvailablePokemon()
-	sort.Slice(pokemons, func(a, b int) bool {
-		return pokemons[a].ID < pokemons[b].ID
-	})
 
 	// 每页10个
 	perPage := 10
@@ -672,9 +1096,9 @@ func (c *PokemonCommands) handleBrowse(i *discordgo.InteractionCreate, pageStr s
 	var buttons []discordgo.MessageComponent
 	for _, p := range pagePokemons {
 		typeStr := pokeapi.GetPokemonTypeString(p.Types)
-		desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n", p.ID, p.Name, typeStr))
+		desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n", p.DexNumber(), p.Name, typeStr))
 		buttons = append(buttons, discordgo.Button{
-			Label:    fmt.Sprintf("#%d %s", p.ID, p.Name),
+			Label:    fmt.Sprintf("#%d %s", p.DexNumber(), p.Name),
 			Style:    discordgo.PrimaryButton,
 			CustomID: fmt.Sprintf("pkm:choose:%d", p.ID),
 		})
@@ -719,7 +1143,13 @@ func (c *PokemonCommands) handleChoosePokemon(i *discordgo.InteractionCreate, ch
 		return
 	}
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	// 默认使用对战规则的可学技能范围，宝可梦在该范围内不可用时使用全国图鉴
+	format := c.handler.LearnsetFormatFor(channelID)
+	pokemon := c.handler.GetPokemonInFormat(pokemonID, format)
+	if pokemon == nil {
+		format = valueobject.LearnsetNational
+		pokemon = c.handler.GetPokemonInFormat(pokemonID, format)
+	}
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -731,6 +1161,7 @@ func (c *PokemonCommands) handleChoosePokemon(i *discordgo.InteractionCreate, ch
 		Nature:      valueobject.NatureHardy,
 		AbilitySlot: 0,
 		MoveIndices: []int{0, 1, 2, 3},
+		Format:      format,
 	}
 	c.handler.SetConfig(channelID, userID, config)
 
@@ -744,9 +1175,12 @@ func (c *PokemonCommands) showConfigPanel(i *discordgo.InteractionCreate, channe
 	
 	// 构建描述
 	var desc strings.Builder
-	desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n\n", pokemon.ID, pokemon.Name, typeStr))
+	desc.WriteString(fmt.Sprintf("**#%03d %s** (%s)\n\n", pokemon.DexNumber(), pokemon.Name, typeStr))
 	desc.WriteString(fmt.Sprintf("📊 **种族值**: HP %d / 攻 %d / 防 %d / 特攻 %d / 特防 %d / 速 %d\n\n",
 		pokemon.BaseHP, pokemon.BaseAtk, pokemon.BaseDef, pokemon.BaseSpAtk, pokemon.BaseSpDef, pokemon.BaseSpeed))
+	if chain := c.handler.GetEvolutionChain(pokemon.ID); chain != nil {
+		desc.WriteString(fmt.Sprintf("🧬 **进化**: %s\n\n", chain))
+	}
 	
 	// 当前配置
 	natureMod := valueobject.GetNatureModifier(config.Nature)
@@ -763,8 +1197,24 @@ func (c *PokemonCommands) showConfigPanel(i *discordgo.InteractionCreate, channe
 		// 默认显示第一个特性
 		desc.WriteString(fmt.Sprintf("✨ **特性**: %s\n", pokemon.Abilities[0].Name))
 	}
+
+	// 显示携带道具
+	if item := c.handler.GetItem(config.ItemID); item != nil {
+		desc.WriteString(fmt.Sprintf("🎒 **道具**: %s\n", item.Name))
+	} else {
+		desc.WriteString("🎒 **道具**: 无\n")
+	}
+	
+	// 显示努力值与能力值预览
+	if config.EVs != nil || config.IVs != nil {
+		desc.WriteString(fmt.Sprintf("💪 **努力值**: %s · 🧬 **个体值**: %s\n", formatStatSpread(configEVs(config)), formatStatSpread(configIVs(config))))
+	}
+	if stats, err := c.handler.PreviewStats(config, 50); err == nil {
+		desc.WriteString(fmt.Sprintf("📈 **能力值** (Lv.50): HP %d / 攻 %d / 防 %d / 特攻 %d / 特防 %d / 速 %d\n",
+			stats.HP, stats.Atk, stats.Def, stats.SpAtk, stats.SpDef, stats.Speed))
+	}
 	
-	desc.WriteString("\n**技能**:\n")
+	desc.WriteString(fmt.Sprintf("\n**技能** (📚 %s):\n", config.Format.DisplayName()))
 	for idx, moveIdx := range config.MoveIndices {
 		if moveIdx < len(pokemon.LearnableMoves) {
 			m := pokemon.LearnableMoves[moveIdx]
@@ -781,17 +1231,23 @@ func (c *PokemonCommands) showConfigPanel(i *discordgo.InteractionCreate, channe
 	}
 
 	// 配置按钮
+	configButtons := []discordgo.MessageComponent{
+		discordgo.Button{Label: "🎭 选择性格", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:nature:%d", pokemon.ID)},
+		discordgo.Button{Label: "✨ 选择特性", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:ability:%d", pokemon.ID)},
+		discordgo.Button{Label: "⚔️ 选择技能", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:cfgmoves:%d:1", pokemon.ID)},
+		discordgo.Button{Label: "🎒 选择道具", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:item:%d:all:1", pokemon.ID)},
+	}
+	// 允许超级进化或Z招式时可以携带超级石/Z纯晶
+	if battle, err := c.handler.GetBattle(channelID); err == nil &&
+		(battle.GimmickAllowed(valueobject.GimmickMegaEvo) || battle.GimmickAllowed(valueobject.GimmickZMove)) {
+		configButtons = append(configButtons, discordgo.Button{Label: "💠 特殊道具", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:gimmickitem:%d", pokemon.ID)})
+	}
 	rows := []discordgo.MessageComponent{
-		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{
-				discordgo.Button{Label: "🎭 选择性格", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:nature:%d", pokemon.ID)},
-				discordgo.Button{Label: "✨ 选择特性", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:ability:%d", pokemon.ID)},
-				discordgo.Button{Label: "⚔️ 选择技能", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:cfgmoves:%d:1", pokemon.ID)},
-			},
-		},
+		discordgo.ActionsRow{Components: configButtons},
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
 				discordgo.Button{Label: "✅ 确认选择", Style: discordgo.SuccessButton, CustomID: fmt.Sprintf("pkm:confirm:%d", pokemon.ID)},
+				discordgo.Button{Label: "📈 努力值/个体值", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:spread:%d", pokemon.ID)},
 				discordgo.Button{Label: "💾 保存预设", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:savepreset:%d", pokemon.ID)},
 				discordgo.Button{Label: "🔙 返回选择", Style: discordgo.SecondaryButton, CustomID: "pkm:select"},
 			},
@@ -849,40 +1305,143 @@ func (c *PokemonCommands) handleShowMoves(i *discordgo.InteractionCreate, channe
 		return
 	}
 
-	if player.Action != nil {
+	if !battle.IsPlayerTurn(userID) {
 		c.bot.RespondEphemeral(i.Interaction, "⏳ 你已选择行动，等待对手...")
 		return
 	}
 
-	// 构建技能按钮
-	var buttons []discordgo.MessageComponent
-	for idx, move := range player.Pokemon.Moves {
-		ppInfo := fmt.Sprintf("%d/%d", move.PP, move.MaxPP)
-		label := fmt.Sprintf("%s (%s) %s", move.Name, move.Type, ppInfo)
-		disabled := !move.CanUse()
+	embed, rows := c.buildMoveMenu(battle, player, "选择要使用的技能", "")
+	if rows == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可用的技能")
+		return
+	}
 
-		style := discordgo.PrimaryButton
-		if move.Category == entity.CategoryPhysical {
-			style = discordgo.DangerButton
-		} else if move.Category == entity.CategoryStatus {
-			style = discordgo.SecondaryButton
-		}
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
 
-		buttons = append(buttons, discordgo.Button{
-			Label:    label,
-			Style:    style,
-			CustomID: fmt.Sprintf("pkm:move:%d", idx),
-			Disabled: disabled,
-		})
+// handleToggleGimmick 在技能菜单中切换本回合发动的特殊系统
+func (c *PokemonCommands) handleToggleGimmick(i *discordgo.InteractionCreate, channelID, userID string, gimmick valueobject.GimmickSystem) {
+	battle, err := c.handler.GetBattle(channelID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
+		return
 	}
 
-	// 根据按钮数量动态构建行，避免数组越界
-	var rows []discordgo.MessageComponent
-	if len(buttons) == 0 {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可用的技能")
+	player := battle.GetPlayer(userID)
+	if player == nil || !battle.IsPlayerTurn(userID) {
+		c.bot.RespondEphemeral(i.Interaction, "⏳ 你已选择行动，等待对手...")
 		return
-	} else if len(buttons) <= 2 {
-		rows = []discordgo.MessageComponent{
+	}
+
+	description := "选择要使用的技能"
+	if gimmick != "" {
+		description = fmt.Sprintf("将在使用技能前发动 **%s**\n选择要使用的技能", gimmick.DisplayName())
+	}
+	embed, rows := c.buildMoveMenu(battle, player, description, gimmick)
+	if rows == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可用的技能")
+		return
+	}
+
+	c.bot.UpdateWithEmbed(i.Interaction, embed, rows)
+}
+
+// parseGimmick 解析按钮 ID 中的特殊系统参数（不存在时为空）
+func parseGimmick(parts []string, index int) valueobject.GimmickSystem {
+	if len(parts) > index && parts[index] != string(valueobject.GimmickNone) {
+		return valueobject.GimmickSystem(parts[index])
+	}
+	return ""
+}
+
+// gimmickSuffix 生成按钮 ID 中的特殊系统后缀
+func gimmickSuffix(gimmick valueobject.GimmickSystem) string {
+	if gimmick == "" {
+		return ""
+	}
+	return ":" + string(gimmick)
+}
+
+// buildGimmickButton 构建特殊系统切换按钮，当前宝可梦无法发动时返回 nil
+func (c *PokemonCommands) buildGimmickButton(battle *entity.Battle, player *entity.BattlePlayer, slot int, battler *entity.Battler, gimmick, active valueobject.GimmickSystem) *discordgo.Button {
+	if ok, _ := battle.CanUseGimmick(player, slot, gimmick); !ok {
+		return nil
+	}
+
+	var label string
+	switch gimmick {
+	case valueobject.GimmickTerastal:
+		label = fmt.Sprintf("💎 太晶化 (%s)", battler.TeraType)
+	case valueobject.GimmickDynamax:
+		label = "🔴 极巨化"
+	case valueobject.GimmickMegaEvo:
+		label = "🧬 超级进化"
+	case valueobject.GimmickZMove:
+		crystalType, _ := battler.ZCrystalType()
+		label = fmt.Sprintf("⚡ Z招式 (%s)", crystalType)
+	default:
+		label = gimmick.DisplayName()
+	}
+
+	if gimmick == active {
+		return &discordgo.Button{Label: label + " ✓", Style: discordgo.SuccessButton, CustomID: "pkm:gimmick:" + string(valueobject.GimmickNone)}
+	}
+	return &discordgo.Button{Label: label, Style: discordgo.SecondaryButton, CustomID: "pkm:gimmick:" + string(gimmick)}
+}
+
+// buildMoveMenu 构建当前需要行动的宝可梦的技能菜单（双打时附带换人按钮）
+// gimmick 为本回合已选择发动的特殊系统，会附加到技能按钮 ID 中
+func (c *PokemonCommands) buildMoveMenu(battle *entity.Battle, player *entity.BattlePlayer, description string, gimmick valueobject.GimmickSystem) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	slot := battle.GetPendingSlot(player)
+	battler := player.GetSlot(slot)
+	if battler == nil {
+		return nil, nil
+	}
+
+	// 构建技能按钮
+	var buttons []discordgo.MessageComponent
+	for idx, move := range battler.Moves {
+		ppInfo := fmt.Sprintf("%d/%d", move.PP, move.MaxPP)
+		label := fmt.Sprintf("%s (%s) %s", move.Name, move.Type, ppInfo)
+		// 极巨化时显示对应的极巨招式
+		if battler.IsDynamaxed || gimmick == valueobject.GimmickDynamax {
+			label = fmt.Sprintf("%s ← %s %s", entity.MaxMoveFor(move).Name, move.Name, ppInfo)
+		}
+		disabled := !move.CanUse()
+		// 讲究系列锁定的技能以外、突击背心下的变化技能不可选
+		if ok, _ := battle.ItemAllowsMove(battler, move); !ok {
+			disabled = true
+		}
+		// 选择Z招式时显示对应的Z招式，属性不符的技能不可选
+		if gimmick == valueobject.GimmickZMove {
+			if battler.CanZMove(move) {
+				label = fmt.Sprintf("%s ← %s %s", entity.ZMoveFor(move).Name, move.Name, ppInfo)
+			} else {
+				disabled = true
+			}
+		}
+
+		style := discordgo.PrimaryButton
+		if move.Category == entity.CategoryPhysical {
+			style = discordgo.DangerButton
+		} else if move.Category == entity.CategoryStatus {
+			style = discordgo.SecondaryButton
+		}
+
+		buttons = append(buttons, discordgo.Button{
+			Label:    label,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:move:%d", idx) + gimmickSuffix(gimmick),
+			Disabled: disabled,
+		})
+	}
+
+	// 根据按钮数量动态构建行，避免数组越界
+	var rows []discordgo.MessageComponent
+	if len(buttons) == 0 {
+		return nil, nil
+	} else if len(buttons) <= 2 {
+		rows = []discordgo.MessageComponent{
 			discordgo.ActionsRow{Components: buttons},
 		}
 	} else if len(buttons) <= 4 {
@@ -898,34 +1457,164 @@ func (c *PokemonCommands) handleShowMoves(i *discordgo.InteractionCreate, channe
 		}
 	}
 
+	// 特殊系统与换人（双打时在技能菜单中提供换人入口）
+	var extra []discordgo.MessageComponent
+	for _, g := range []valueobject.GimmickSystem{valueobject.GimmickMegaEvo, valueobject.GimmickZMove, valueobject.GimmickTerastal, valueobject.GimmickDynamax} {
+		if button := c.buildGimmickButton(battle, player, slot, battler, g, gimmick); button != nil {
+			extra = append(extra, *button)
+		}
+	}
+	if battle.IsDoubles() && player.HasSwitchableTeamMember() {
+		extra = append(extra, discordgo.Button{Label: "🔄 换人", Style: discordgo.SecondaryButton, CustomID: "pkm:switch"})
+	}
+	if len(extra) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: extra})
+	}
+
+	title := fmt.Sprintf("⚡ %s 的技能", battler.Pokemon.Name)
+	if battle.IsDoubles() {
+		title = fmt.Sprintf("⚡ %d 号位 %s 的技能", slot+1, battler.Pokemon.Name)
+	}
 	embed := &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("⚡ %s 的技能", player.Pokemon.Pokemon.Name),
-		Description: "选择要使用的技能",
+		Title:       title,
+		Description: description,
 		Color:       0xFFCB05,
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
-			URL: player.Pokemon.Pokemon.GetSpriteURL(),
+			URL: battler.Pokemon.GetSpriteURL(),
 		},
 	}
 
-	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+	return embed, rows
 }
 
 // handleUseMove 使用技能
-func (c *PokemonCommands) handleUseMove(i *discordgo.InteractionCreate, channelID, userID, moveIndexStr string) {
+func (c *PokemonCommands) handleUseMove(i *discordgo.InteractionCreate, channelID, userID, moveIndexStr string, gimmick valueobject.GimmickSystem) {
+	moveIndex, err := strconv.Atoi(moveIndexStr)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的技能")
+		return
+	}
+
+	// 双打单体技能需要先选择目标
+	if battle, err := c.handler.GetBattle(channelID); err == nil {
+		if player := battle.GetPlayer(userID); player != nil {
+			battler := player.GetSlot(battle.GetPendingSlot(player))
+			if battler != nil && moveIndex >= 0 && moveIndex < len(battler.Moves) {
+				move := battler.ResolveMove(battler.Moves[moveIndex])
+				if gimmick == valueobject.GimmickDynamax {
+					move = entity.MaxMoveFor(battler.Moves[moveIndex])
+				} else if gimmick == valueobject.GimmickZMove && battler.CanZMove(move) {
+					move = entity.ZMoveFor(move)
+				}
+				if battle.MoveNeedsTarget(move) {
+					c.showTargetMenu(i, battle, player, battler, moveIndex, gimmick)
+					return
+				}
+			}
+		}
+	}
+
+	logs, err := c.handler.UseMoveWithTarget(channelID, userID, moveIndex, 0, false, gimmick)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	c.respondAfterAction(i, channelID, userID, logs, "✅ 已选择技能，等待对手行动...")
+}
+
+// showTargetMenu 显示双打技能的目标选择菜单
+func (c *PokemonCommands) showTargetMenu(i *discordgo.InteractionCreate, battle *entity.Battle, player *entity.BattlePlayer, battler *entity.Battler, moveIndex int, gimmick valueobject.GimmickSystem) {
+	opponent := battle.GetOpponent(player.ID)
+	if opponent == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对手不存在")
+		return
+	}
+
+	var buttons []discordgo.MessageComponent
+	for slot := 0; slot < battle.ActiveSlotCount(); slot++ {
+		target := opponent.GetSlot(slot)
+		if target == nil || !target.IsAlive() {
+			continue
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("🎯 %s (%.0f%%)", target.Pokemon.Name, target.GetHPPercent()),
+			Style:    discordgo.DangerButton,
+			CustomID: fmt.Sprintf("pkm:target:%d:f%d", moveIndex, slot) + gimmickSuffix(gimmick),
+		})
+	}
+	for _, ally := range player.ActiveBattlers() {
+		if ally == battler || !ally.IsAlive() {
+			continue
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("🤝 %s (%.0f%%)", ally.Pokemon.Name, ally.GetHPPercent()),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("pkm:target:%d:ally", moveIndex) + gimmickSuffix(gimmick),
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "🔙 返回",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "pkm:moves",
+	})
+
+	move := battler.Moves[moveIndex]
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎯 %s 的 %s", battler.Pokemon.Name, move.Name),
+		Description: "选择技能的目标：",
+		Color:       0xFFCB05,
+	}
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: buttons},
+	}, true)
+}
+
+// handleSelectTarget 选择双打技能目标后使用技能
+func (c *PokemonCommands) handleSelectTarget(i *discordgo.InteractionCreate, channelID, userID, moveIndexStr, targetStr string, gimmick valueobject.GimmickSystem) {
 	moveIndex, err := strconv.Atoi(moveIndexStr)
 	if err != nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的技能")
 		return
 	}
 
-	logs, err := c.handler.UseMove(channelID, userID, moveIndex)
+	// 目标格式：f<对手位置> 或 ally
+	targetSlot := 0
+	targetAlly := targetStr == "ally"
+	if !targetAlly {
+		targetSlot, err = strconv.Atoi(strings.TrimPrefix(targetStr, "f"))
+		if err != nil {
+			c.bot.RespondEphemeral(i.Interaction, "❌ 无效的目标")
+			return
+		}
+	}
+
+	logs, err := c.handler.UseMoveWithTarget(channelID, userID, moveIndex, targetSlot, targetAlly, gimmick)
 	if err != nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
 	}
 
-	// 检查是否为人机对战，如果是则触发 AI 行动
+	c.respondAfterAction(i, channelID, userID, logs, "✅ 已选择技能，等待对手行动...")
+}
+
+// respondAfterAction 玩家选择行动后的响应：人机对战触发 AI 行动，回合结束时发送日志，
+// 双打中还有宝可梦未行动时继续显示技能菜单
+func (c *PokemonCommands) respondAfterAction(i *discordgo.InteractionCreate, channelID, userID string, logs []string, waitingMsg string) {
 	battle, _ := c.handler.GetBattle(channelID)
+
+	// 双打：继续为下一只宝可梦选择行动
+	if battle != nil && len(logs) == 0 && battle.IsDoubles() && battle.IsPlayerTurn(userID) {
+		player := battle.GetPlayer(userID)
+		embed, rows := c.buildMoveMenu(battle, player, "✅ 已记录上一只宝可梦的行动\n请继续选择技能", "")
+		if rows != nil {
+			c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+			return
+		}
+	}
+
+	// 检查是否为人机对战，如果是则触发 AI 行动
 	if battle != nil && battle.IsAIBattle && len(logs) == 0 {
 		// 玩家已行动，触发 AI 行动并执行回合
 		aiLogs, _ := c.handler.ExecuteAITurn(channelID)
@@ -947,7 +1636,7 @@ func (c *PokemonCommands) handleUseMove(i *discordgo.InteractionCreate, channelI
 		}
 	} else {
 		// 等待对手（普通 PVP 模式）
-		c.bot.RespondEphemeral(i.Interaction, "✅ 已选择技能，等待对手行动...")
+		c.bot.RespondEphemeral(i.Interaction, waitingMsg)
 	}
 }
 
@@ -996,15 +1685,19 @@ func (c *PokemonCommands) handleEnd(i *discordgo.InteractionCreate, channelID, u
 // sendBattlePanel 发送对战面板到频道
 func (c *PokemonCommands) sendBattlePanel(i *discordgo.InteractionCreate, channelID string) {
 	battle, err := c.handler.GetBattle(channelID)
-	if err != nil || battle.State != entity.BattleStateBattling {
+	if err != nil || (battle.State != entity.BattleStateBattling && battle.State != entity.BattleStateReplacing) {
 		return
 	}
 
 	embed := c.buildBattleStatusEmbed(battle, "")
+	actionButton := discordgo.Button{Label: "⚡ 技能", Style: discordgo.PrimaryButton, CustomID: "pkm:moves"}
+	if battle.State == entity.BattleStateReplacing {
+		actionButton = discordgo.Button{Label: "🔄 选择替补", Style: discordgo.PrimaryButton, CustomID: "pkm:replace"}
+	}
 	components := []discordgo.MessageComponent{
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
-				discordgo.Button{Label: "⚡ 技能", Style: discordgo.PrimaryButton, CustomID: "pkm:moves"},
+				actionButton,
 				discordgo.Button{Label: "🔄 刷新", Style: discordgo.SecondaryButton, CustomID: "pkm:refresh"},
 			},
 		},
@@ -1052,7 +1745,7 @@ func (c *PokemonCommands) handleNatureSelect(i *discordgo.InteractionCreate, cha
 
 	rows = append(rows, discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
-			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:choose:%d", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
 		},
 	})
 
@@ -1079,7 +1772,7 @@ func (c *PokemonCommands) handleSetNature(i *discordgo.InteractionCreate, channe
 	config.Nature = nature
 	c.handler.SetConfig(channelID, userID, config)
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -1091,7 +1784,7 @@ func (c *PokemonCommands) handleSetNature(i *discordgo.InteractionCreate, channe
 // handleAbilitySelect 显示特性选择菜单
 func (c *PokemonCommands) handleAbilitySelect(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -1129,7 +1822,7 @@ func (c *PokemonCommands) handleAbilitySelect(i *discordgo.InteractionCreate, ch
 
 	rows = append(rows, discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
-			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:choose:%d", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
 		},
 	})
 
@@ -1160,7 +1853,7 @@ func (c *PokemonCommands) handleSetAbility(i *discordgo.InteractionCreate, chann
 	}
 	c.handler.SetConfig(channelID, userID, config)
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -1169,228 +1862,407 @@ func (c *PokemonCommands) handleSetAbility(i *discordgo.InteractionCreate, chann
 	c.showConfigPanel(i, channelID, userID, pokemon, config)
 }
 
-// handleConfirmPokemon 确认选择宝可梦
-func (c *PokemonCommands) handleConfirmPokemon(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
-	pokemonID, _ := strconv.Atoi(pokemonIDStr)
-
-	pokemon := c.handler.GetPokemonByID(pokemonID)
-	if pokemon == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
-		return
-	}
-
-	level := 50
-
-	if err := c.handler.SelectPokemon(channelID, userID, pokemonID, level); err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
-		return
-	}
-
-	battle, _ := c.handler.GetBattle(channelID)
-	if battle == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
-		return
-	}
-
-	player := battle.GetPlayer(userID)
-	if player == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
-		return
-	}
-
-	teamSize := int(battle.TeamSize)
-	currentCount := len(player.Team)
-
-	if battle.State == entity.BattleStateBattling {
-		// 双方都已准备好，对战开始
-		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 选择了 **%s**！\n\n⚔️ 双方准备完毕，对战开始！", i.Member.User.Username, pokemon.Name))
-		c.sendBattlePanel(i, channelID)
-	} else if currentCount < teamSize {
-		// 队伍未满，继续选择
-		c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("✅ 已添加 **%s** 到队伍！\n\n📋 队伍进度: %d/%d\n请继续选择下一只宝可梦", pokemon.Name, currentCount, teamSize))
-	} else if player.Ready {
-		// 队伍已满且已准备
-		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 的队伍已准备完毕！等待对手...", i.Member.User.Username))
-	} else {
-		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 选择了 **%s**！等待对手选择...", i.Member.User.Username, pokemon.Name))
-	}
+// itemFilters 道具选择界面的分类筛选
+var itemFilters = []struct {
+	Key        string
+	Label      string
+	Categories []valueobject.ItemCategory
+}{
+	{"all", "📦 全部", nil},
+	{"choice", "🎯 讲究", []valueobject.ItemCategory{valueobject.ItemCategoryChoice}},
+	{"boost", "💪 强化", []valueobject.ItemCategory{valueobject.ItemCategoryBoost, valueobject.ItemCategoryOrb}},
+	{"held", "🛡️ 携带", []valueobject.ItemCategory{valueobject.ItemCategoryHeld}},
+	{"berry", "🍓 树果", []valueobject.ItemCategory{valueobject.ItemCategoryBerry}},
 }
 
-// handleConfigMoves 显示技能配置界面（支持分页）
-func (c *PokemonCommands) handleConfigMoves(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, pageStr string) {
+// itemsPerPage 道具选择界面每页显示的道具数（3 行按钮）
+const itemsPerPage = 15
+
+// handleItemSelect 显示携带道具选择界面（按分类筛选，支持分页）
+func (c *PokemonCommands) handleItemSelect(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, filter, pageStr string) {
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
 	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
-	}
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
-	if pokemon == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
 		return
 	}
 
-	config := c.handler.GetConfig(channelID, userID)
-	if config == nil {
-		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
-		c.handler.SetConfig(channelID, userID, config)
+	var categories []valueobject.ItemCategory
+	for _, f := range itemFilters {
+		if f.Key == filter {
+			categories = f.Categories
+		}
 	}
+	items := valueobject.GetItemsByCategory(categories...)
 
-	// 每页显示12个技能（3行x4个）
-	perPage := 12
-	totalMoves := len(pokemon.LearnableMoves)
-	totalPages := (totalMoves + perPage - 1) / perPage
+	totalPages := (len(items) + itemsPerPage - 1) / itemsPerPage
 	if totalPages < 1 {
 		totalPages = 1
 	}
+	if page < 1 {
+		page = 1
+	}
 	if page > totalPages {
 		page = totalPages
 	}
+	start := (page - 1) * itemsPerPage
+	end := start + itemsPerPage
+	if end > len(items) {
+		end = len(items)
+	}
 
-	start := (page - 1) * perPage
-	end := start + perPage
-	if end > totalMoves {
-		end = totalMoves
+	// 分类筛选按钮
+	var filterButtons []discordgo.MessageComponent
+	for _, f := range itemFilters {
+		style := discordgo.SecondaryButton
+		if f.Key == filter {
+			style = discordgo.SuccessButton
+		}
+		filterButtons = append(filterButtons, discordgo.Button{
+			Label:    f.Label,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:item:%d:%s:1", pokemonID, f.Key),
+		})
 	}
 
 	var desc strings.Builder
-	desc.WriteString(fmt.Sprintf("## 🎯 %s 技能配置\n\n", pokemon.Name))
-	desc.WriteString(fmt.Sprintf("**已选技能 (%d/4)：**\n", len(config.MoveIndices)))
-	if len(config.MoveIndices) == 0 {
-		desc.WriteString("_未选择技能（将使用默认技能）_\n")
-	} else {
-		for slot, idx := range config.MoveIndices {
-			if idx < len(pokemon.LearnableMoves) {
-				move := pokemon.LearnableMoves[idx]
-				powerStr := "-"
-				if move.Power > 0 {
-					powerStr = fmt.Sprintf("%d", move.Power)
-				}
-				desc.WriteString(fmt.Sprintf("%d. %s (%s) 威力:%s\n", slot+1, move.Name, move.Type, powerStr))
-			}
-		}
+	desc.WriteString(c.itemSelectHeader(channelID, config))
+	for _, item := range items[start:end] {
+		desc.WriteString(fmt.Sprintf("• **%s** — %s\n", item.Name, item.Description))
 	}
-	desc.WriteString(fmt.Sprintf("\n📖 可学技能: %d个 (第%d/%d页)", totalMoves, page, totalPages))
+
+	rows := []discordgo.MessageComponent{discordgo.ActionsRow{Components: filterButtons}}
+	rows = append(rows, c.buildItemButtons(channelID, userID, pokemonID, config, items[start:end])...)
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "◀️", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:item:%d:%s:%d", pokemonID, filter, page-1), Disabled: page <= 1},
+			discordgo.Button{Label: "▶️", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:item:%d:%s:%d", pokemonID, filter, page+1), Disabled: page >= totalPages},
+			discordgo.Button{Label: "🔍 搜索道具", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:searchitem:%d", pokemonID)},
+			discordgo.Button{Label: "🚫 不携带", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:setitem:%d:0", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
+		},
+	})
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "⚡ 技能配置",
+		Title:       "🎒 选择携带道具",
 		Description: desc.String(),
-		Color:       0x3498DB,
-		Footer:      &discordgo.MessageEmbedFooter{Text: "点击技能选择/取消，绿色为已选"},
+		Color:       0xFFCB05,
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("第 %d/%d 页", page, totalPages)},
 	}
 
-	var rows []discordgo.MessageComponent
-	var buttons []discordgo.MessageComponent
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
 
-	// 显示当前页的技能
-	for idx := start; idx < end; idx++ {
-		move := pokemon.LearnableMoves[idx]
-		style := discordgo.SecondaryButton
-		for _, si := range config.MoveIndices {
-			if si == idx {
-				style = discordgo.SuccessButton
+// itemSelectHeader 构建道具选择界面的说明（当前道具与道具条款提示）
+func (c *PokemonCommands) itemSelectHeader(channelID string, config *pokemon_app.PokemonConfig) string {
+	var desc strings.Builder
+	if item := c.handler.GetItem(config.ItemID); item != nil {
+		desc.WriteString(fmt.Sprintf("当前道具: **%s**\n", item.Name))
+	} else {
+		desc.WriteString("当前道具: 无\n")
+	}
+	if battle, err := c.handler.GetBattle(channelID); err == nil && battle.Config.ItemClause {
+		desc.WriteString("📜 道具条款：队伍中已携带的道具不能重复选择\n")
+	}
+	desc.WriteString("\n")
+	return desc.String()
+}
+
+// heldItemTaken 道具条款下该道具是否已被队友携带（正在重新配置的同种类队伍成员自身的道具不算）
+func (c *PokemonCommands) heldItemTaken(battle *entity.Battle, userID string, pokemonID, itemID int) bool {
+	if battle == nil {
+		return false
+	}
+	var editing *entity.PokemonBuild
+	pokemon := c.handler.GetPokemonByID(pokemonID)
+	if player := battle.GetPlayer(userID); player != nil && pokemon != nil {
+		for _, member := range player.Team {
+			if member.Build.Pokemon.DexNumber() == pokemon.DexNumber() {
+				editing = member.Build
 				break
 			}
 		}
-		// 截断过长的技能名
-		label := move.Name
-		if len(label) > 12 {
-			label = label[:12] + "…"
-		}
-		buttons = append(buttons, discordgo.Button{
-			Label:    label,
-			Style:    style,
-			CustomID: fmt.Sprintf("pkm:setmove:%d:%d:%d", pokemonID, idx, page),
-		})
-		if len(buttons) == 4 {
-			rows = append(rows, discordgo.ActionsRow{Components: buttons})
-			buttons = nil
+	}
+	return battle.HeldItemTaken(userID, itemID, editing)
+}
+
+// buildItemButtons 构建道具按钮（每行 5 个），道具条款下队友已携带的道具不可选
+func (c *PokemonCommands) buildItemButtons(channelID, userID string, pokemonID int, config *pokemon_app.PokemonConfig, items []valueobject.Item) []discordgo.MessageComponent {
+	battle, _ := c.handler.GetBattle(channelID)
+
+	var rows []discordgo.MessageComponent
+	var currentRow []discordgo.MessageComponent
+	for _, item := range items {
+		label := item.Name
+		style := discordgo.PrimaryButton
+		if item.ID == config.ItemID {
+			label = "✓ " + label
+			style = discordgo.SuccessButton
+		}
+		currentRow = append(currentRow, discordgo.Button{
+			Label:    label,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:setitem:%d:%d", pokemonID, item.ID),
+			Disabled: c.heldItemTaken(battle, userID, pokemonID, item.ID),
+		})
+		if len(currentRow) == 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: currentRow})
+			currentRow = nil
 		}
 	}
-	if len(buttons) > 0 {
-		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	if len(currentRow) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: currentRow})
 	}
+	return rows
+}
 
-	// 分页和操作按钮
-	var navButtons []discordgo.MessageComponent
-	if page > 1 {
-		navButtons = append(navButtons, discordgo.Button{
-			Label:    "⬅️",
-			Style:    discordgo.SecondaryButton,
-			CustomID: fmt.Sprintf("pkm:cfgmoves:%d:%d", pokemonID, page-1),
-		})
+// handleSearchItemModal 显示道具搜索模态框
+func (c *PokemonCommands) handleSearchItemModal(i *discordgo.InteractionCreate, pokemonIDStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+
+	err := c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("pkm:searchitem_modal:%d", pokemonID),
+			Title:    "🔍 搜索道具",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "keyword",
+							Label:       "输入道具名称或效果关键字",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "例如：讲究、宝珠、回复...",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   20,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("显示道具搜索模态框失败: %v", err)
 	}
-	navButtons = append(navButtons, discordgo.Button{
-		Label:    "🔍 搜索",
-		Style:    discordgo.PrimaryButton,
-		CustomID: fmt.Sprintf("pkm:searchmove:%d", pokemonID),
+}
+
+// handleSearchItemModalSubmit 处理道具搜索模态框提交
+func (c *PokemonCommands) handleSearchItemModalSubmit(i *discordgo.InteractionCreate, pokemonIDStr string) {
+	channelID := i.ChannelID
+	userID := i.Member.User.ID
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
+	}
+
+	// 获取搜索关键字
+	data := i.ModalSubmitData()
+	var keyword string
+	for _, row := range data.Components {
+		if ar, ok := row.(*discordgo.ActionsRow); ok {
+			for _, comp := range ar.Components {
+				if ti, ok := comp.(*discordgo.TextInput); ok && ti.CustomID == "keyword" {
+					keyword = ti.Value
+				}
+			}
+		}
+	}
+
+	items := valueobject.SearchItems(keyword)
+	if len(items) == 0 {
+		c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("❌ 未找到包含 \"%s\" 的道具", keyword))
+		return
+	}
+
+	// 限制最多显示 20 个结果
+	if len(items) > 20 {
+		items = items[:20]
+	}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("🔍 搜索 \"%s\" 的结果 (%d 个)\n\n", keyword, len(items)))
+	desc.WriteString(c.itemSelectHeader(channelID, config))
+	for _, item := range items {
+		desc.WriteString(fmt.Sprintf("• **%s** — %s\n", item.Name, item.Description))
+	}
+
+	rows := c.buildItemButtons(channelID, userID, pokemonID, config, items)
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "⬅️ 返回道具列表", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:item:%d:all:1", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
+		},
 	})
-	if page < totalPages {
-		navButtons = append(navButtons, discordgo.Button{
-			Label:    "➡️",
-			Style:    discordgo.SecondaryButton,
-			CustomID: fmt.Sprintf("pkm:cfgmoves:%d:%d", pokemonID, page+1),
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔍 道具搜索",
+		Description: desc.String(),
+		Color:       0x3498DB,
+	}
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
+
+// handleGimmickItemSelect 显示特殊道具选择菜单（超级石、Z纯晶）
+func (c *PokemonCommands) handleGimmickItemSelect(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+	battle, err := c.handler.GetBattle(channelID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
+		return
+	}
+
+	var items []*valueobject.Item
+	if battle.GimmickAllowed(valueobject.GimmickMegaEvo) {
+		items = append(items, pokeapi.GetMegaStones(pokemonID)...)
+	}
+	if battle.GimmickAllowed(valueobject.GimmickZMove) {
+		items = append(items, pokeapi.GetZCrystals()...)
+	}
+
+	var buttons []discordgo.MessageComponent
+	for _, item := range items {
+		style := discordgo.PrimaryButton
+		if item.Category == valueobject.ItemCategoryMega {
+			style = discordgo.SuccessButton
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    item.Name,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:setitem:%d:%d", pokemonID, item.ID),
+			Disabled: c.heldItemTaken(battle, userID, pokemonID, item.ID),
 		})
 	}
-	navButtons = append(navButtons, discordgo.Button{
-		Label:    "🔙 返回",
-		Style:    discordgo.SecondaryButton,
-		CustomID: fmt.Sprintf("pkm:choose:%d", pokemonID),
-	})
-	navButtons = append(navButtons, discordgo.Button{
-		Label:    "✅ 确认",
-		Style:    discordgo.SuccessButton,
-		CustomID: fmt.Sprintf("pkm:confirmmoves:%d", pokemonID),
-	})
-	rows = append(rows, discordgo.ActionsRow{Components: navButtons})
 
-	// 确保不超过 Discord 的 5 行限制
-	if len(rows) > 5 {
-		rows = rows[:5]
+	// 每行 5 个按钮，最后一行留给「不携带」与返回
+	var rows []discordgo.MessageComponent
+	for j := 0; j < len(buttons) && len(rows) < 4; j += 5 {
+		end := j + 5
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[j:end]})
 	}
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "🚫 不携带", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:setitem:%d:0", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
+		},
+	})
 
-	if err := c.bot.RespondWithEmbed(i.Interaction, embed, rows, true); err != nil {
-		// 如果响应失败，可能是交互已过期，尝试发送新消息
-		log.Printf("技能配置响应失败: %v", err)
+	embed := &discordgo.MessageEmbed{
+		Title:       "💠 选择特殊道具",
+		Description: "🧬 超级石：携带对应的超级石可以在对战中超级进化\n⚡ Z纯晶：同属性的技能可以变为Z招式",
+		Color:       0xFFCB05,
 	}
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
 }
 
-// handleSetMove 设置/取消技能
-func (c *PokemonCommands) handleSetMove(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, moveIdxStr, pageStr string) {
+// handleSetItem 设置携带道具
+func (c *PokemonCommands) handleSetItem(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, itemIDStr string) {
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
-	moveIdx, _ := strconv.Atoi(moveIdxStr)
+	itemID, _ := strconv.Atoi(itemIDStr)
 
 	config := c.handler.GetConfig(channelID, userID)
 	if config == nil {
-		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
 	}
 
-	found := -1
-	for j, idx := range config.MoveIndices {
-		if idx == moveIdx {
-			found = j
-			break
-		}
+	// 道具条款：队伍中不能有重复的道具
+	if battle, err := c.handler.GetBattle(channelID); err == nil && c.heldItemTaken(battle, userID, pokemonID, itemID) {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 道具条款：队伍中已有宝可梦携带该道具")
+		return
 	}
 
-	if found >= 0 {
-		config.MoveIndices = append(config.MoveIndices[:found], config.MoveIndices[found+1:]...)
-	} else if len(config.MoveIndices) < 4 {
-		config.MoveIndices = append(config.MoveIndices, moveIdx)
-	} else {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 最多只能选择4个技能")
+	config.ItemID = itemID
+	c.handler.SetConfig(channelID, userID, config)
+
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
+	if pokemon == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
 	}
 
-	c.handler.SetConfig(channelID, userID, config)
-	c.handleConfigMoves(i, channelID, userID, pokemonIDStr, pageStr)
+	c.showConfigPanel(i, channelID, userID, pokemon, config)
 }
 
-// handleConfirmMoves 确认技能选择，返回配置面板
-func (c *PokemonCommands) handleConfirmMoves(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+// spreadPresets 常用努力值/个体值快捷配置
+var spreadPresets = []struct {
+	Key   string
+	Label string
+	EVs   *entity.Stats // nil 表示不修改努力值
+	IVs   *entity.Stats // nil 表示不修改个体值
+}{
+	{"physical", "⚔️ 物攻速攻", &entity.Stats{HP: 4, Atk: 252, Speed: 252}, nil},
+	{"special", "🔮 特攻速攻", &entity.Stats{HP: 4, SpAtk: 252, Speed: 252}, nil},
+	{"physdef", "🛡️ 物理耐久", &entity.Stats{HP: 252, Def: 252, SpDef: 4}, nil},
+	{"specdef", "💠 特殊耐久", &entity.Stats{HP: 252, Def: 4, SpDef: 252}, nil},
+	{"bulky", "🧱 双耐久", &entity.Stats{HP: 252, Def: 128, SpDef: 128}, nil},
+	{"trickroom", "🐢 0速个体", nil, &entity.Stats{HP: 31, Atk: 31, Def: 31, SpAtk: 31, SpDef: 31, Speed: 0}},
+	{"noatk", "🪶 0攻个体", nil, &entity.Stats{HP: 31, Atk: 0, Def: 31, SpAtk: 31, SpDef: 31, Speed: 31}},
+}
+
+// statLabels 六维名称（HP/攻击/防御/特攻/特防/速度）
+var statLabels = [6]string{"HP", "攻击", "防御", "特攻", "特防", "速度"}
+
+// configEVs 获取配置的努力值（未设置时为全0）
+func configEVs(config *pokemon_app.PokemonConfig) entity.Stats {
+	if config.EVs != nil {
+		return *config.EVs
+	}
+	return entity.Stats{}
+}
+
+// configIVs 获取配置的个体值（未设置时为6V）
+func configIVs(config *pokemon_app.PokemonConfig) entity.Stats {
+	if config.IVs != nil {
+		return *config.IVs
+	}
+	return entity.Stats{HP: 31, Atk: 31, Def: 31, SpAtk: 31, SpDef: 31, Speed: 31}
+}
+
+// formatStatSpread 格式化为 "252/0/0/0/4/252"
+func formatStatSpread(stats entity.Stats) string {
+	values := stats.Values()
+	parts := make([]string, len(values))
+	for idx, v := range values {
+		parts[idx] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, "/")
+}
+
+// parseStatSpread 解析 "252/0/0/0/4/252" 格式（也支持空格或逗号分隔）
+func parseStatSpread(input string) (entity.Stats, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == '/' || r == ',' || r == '，' || r == ' '
+	})
+	if len(fields) != 6 {
+		return entity.Stats{}, fmt.Errorf("需要按 HP/攻击/防御/特攻/特防/速度 填写 6 个数值")
+	}
+	var values [6]int
+	for idx, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return entity.Stats{}, fmt.Errorf("%s 的数值 \"%s\" 无效", statLabels[idx], field)
+		}
+		values[idx] = v
+	}
+	return entity.Stats{HP: values[0], Atk: values[1], Def: values[2], SpAtk: values[3], SpDef: values[4], Speed: values[5]}, nil
+}
+
+// handleSpreadPanel 显示努力值/个体值编辑面板（含能力值预览）
+func (c *PokemonCommands) handleSpreadPanel(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -1398,160 +2270,1137 @@ func (c *PokemonCommands) handleConfirmMoves(i *discordgo.InteractionCreate, cha
 
 	config := c.handler.GetConfig(channelID, userID)
 	if config == nil {
-		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
-		c.handler.SetConfig(channelID, userID, config)
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
 	}
 
-	c.showConfigPanel(i, channelID, userID, pokemon, config)
+	stats, err := c.handler.PreviewStats(config, 50)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	evs := configEVs(config)
+	ivs := configIVs(config)
+	natureMod := valueobject.GetNatureModifier(config.Nature)
+	natureValues := [6]float64{1.0, natureMod.Atk, natureMod.Def, natureMod.SpAtk, natureMod.SpDef, natureMod.Speed}
+	baseValues := [6]int{pokemon.BaseHP, pokemon.BaseAtk, pokemon.BaseDef, pokemon.BaseSpAtk, pokemon.BaseSpDef, pokemon.BaseSpeed}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("**#%03d %s** · 🎭 %s (%s)\n\n", pokemon.DexNumber(), pokemon.Name, config.Nature, formatNatureEffect(natureMod)))
+	desc.WriteString(fmt.Sprintf("💪 **努力值**: %s (总计 %d/510)\n", formatStatSpread(evs), evs.Total()))
+	desc.WriteString(fmt.Sprintf("🧬 **个体值**: %s\n\n", formatStatSpread(ivs)))
+	desc.WriteString("📈 **能力值预览** (Lv.50)\n")
+	for idx, value := range stats.Values() {
+		mark := ""
+		if natureValues[idx] > 1.0 {
+			mark = " ↑"
+		} else if natureValues[idx] < 1.0 {
+			mark = " ↓"
+		}
+		desc.WriteString(fmt.Sprintf("`%s` 种族 %d · 努力 %d · 个体 %d → **%d**%s\n",
+			statLabels[idx], baseValues[idx], evs.Values()[idx], ivs.Values()[idx], value, mark))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📈 努力值 / 个体值",
+		Description: desc.String(),
+		Color:       0xFFCB05,
+		Thumbnail:   &discordgo.MessageEmbedThumbnail{URL: c.handler.GetSpriteURL(pokemon.ID)},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "💡 点击快捷配置或「手动编辑」，努力值单项 0-252、总计 510，个体值 0-31"},
+	}
+
+	var presetButtons []discordgo.MessageComponent
+	for _, preset := range spreadPresets {
+		presetButtons = append(presetButtons, discordgo.Button{
+			Label:    preset.Label,
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("pkm:setspread:%d:%s", pokemonID, preset.Key),
+		})
+	}
+
+	var rows []discordgo.MessageComponent
+	for start := 0; start < len(presetButtons); start += 5 {
+		end := start + 5
+		if end > len(presetButtons) {
+			end = len(presetButtons)
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: presetButtons[start:end]})
+	}
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "✏️ 手动编辑", Style: discordgo.SuccessButton, CustomID: fmt.Sprintf("pkm:spreadedit:%d", pokemonID)},
+			discordgo.Button{Label: "♻️ 重置", Style: discordgo.DangerButton, CustomID: fmt.Sprintf("pkm:setspread:%d:reset", pokemonID)},
+			discordgo.Button{Label: "🔙 返回配置", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:config:%d", pokemonID)},
+		},
+	})
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
 }
 
-// handleShowPresets 显示预设列表
-func (c *PokemonCommands) handleShowPresets(i *discordgo.InteractionCreate, userID string) {
-	presets := c.handler.GetPresets(userID)
+// handleSetSpread 应用快捷努力值/个体值配置
+func (c *PokemonCommands) handleSetSpread(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, key string) {
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
+	}
+
+	if key == "reset" {
+		config.EVs = nil
+		config.IVs = nil
+	} else {
+		found := false
+		for _, preset := range spreadPresets {
+			if preset.Key != key {
+				continue
+			}
+			if preset.EVs != nil {
+				evs := *preset.EVs
+				config.EVs = &evs
+			}
+			if preset.IVs != nil {
+				ivs := *preset.IVs
+				config.IVs = &ivs
+			}
+			found = true
+			break
+		}
+		if !found {
+			c.bot.RespondEphemeral(i.Interaction, "❌ 无效的快捷配置")
+			return
+		}
+	}
+	c.handler.SetConfig(channelID, userID, config)
+
+	c.handleSpreadPanel(i, channelID, userID, pokemonIDStr)
+}
+
+// handleSpreadModal 显示努力值/个体值编辑模态框
+func (c *PokemonCommands) handleSpreadModal(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
+	}
+
+	err := c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("pkm:spread_modal:%d", pokemonID),
+			Title:    "✏️ 编辑努力值 / 个体值",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "evs",
+							Label:       "努力值 HP/攻击/防御/特攻/特防/速度",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "例如：4/252/0/0/0/252",
+							Value:       formatStatSpread(configEVs(config)),
+							Required:    true,
+							MaxLength:   40,
+						},
+					},
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "ivs",
+							Label:       "个体值 HP/攻击/防御/特攻/特防/速度",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "例如：31/31/31/31/31/0",
+							Value:       formatStatSpread(configIVs(config)),
+							Required:    true,
+							MaxLength:   40,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("显示努力值编辑模态框失败: %v", err)
+	}
+}
+
+// handleSpreadModalSubmit 处理努力值/个体值编辑模态框提交
+func (c *PokemonCommands) handleSpreadModalSubmit(i *discordgo.InteractionCreate, pokemonIDStr string) {
+	channelID := i.ChannelID
+	userID := i.Member.User.ID
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
+	}
+
+	data := i.ModalSubmitData()
+	var evsInput, ivsInput string
+	for _, row := range data.Components {
+		if ar, ok := row.(*discordgo.ActionsRow); ok {
+			for _, comp := range ar.Components {
+				if ti, ok := comp.(*discordgo.TextInput); ok {
+					switch ti.CustomID {
+					case "evs":
+						evsInput = ti.Value
+					case "ivs":
+						ivsInput = ti.Value
+					}
+				}
+			}
+		}
+	}
+
+	evs, err := parseStatSpread(evsInput)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 努力值格式错误："+err.Error())
+		return
+	}
+	if err := evs.ValidateEVs(); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	ivs, err := parseStatSpread(ivsInput)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 个体值格式错误："+err.Error())
+		return
+	}
+	if err := ivs.ValidateIVs(); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	config.EVs = &evs
+	config.IVs = &ivs
+	c.handler.SetConfig(channelID, userID, config)
+
+	c.handleSpreadPanel(i, channelID, userID, pokemonIDStr)
+}
+
+// handleConfirmPokemon 确认选择宝可梦
+func (c *PokemonCommands) handleConfirmPokemon(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+
+	pokemon := c.handler.GetPokemonByID(pokemonID)
+	if pokemon == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
+		return
+	}
+
+	level := 50
+
+	if err := c.handler.SelectPokemon(channelID, userID, pokemonID, level); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	battle, _ := c.handler.GetBattle(channelID)
+	if battle == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
+		return
+	}
+
+	player := battle.GetPlayer(userID)
+	if player == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
+		return
+	}
+
+	teamSize := int(battle.TeamSize)
+	currentCount := len(player.Team)
+
+	if battle.State == entity.BattleStateBattling {
+		// 双方都已准备好，对战开始
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 选择了 **%s**！\n\n⚔️ 双方准备完毕，对战开始！", i.Member.User.Username, pokemon.Name))
+		c.sendBattlePanel(i, channelID)
+	} else if battle.State == entity.BattleStatePreview {
+		// 双方队伍登记完毕，进入队伍预览
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 选择了 **%s**！\n\n👀 双方队伍登记完毕，进入队伍预览！请点击「选择出战」挑选 %d 只宝可梦。", i.Member.User.Username, pokemon.Name, battle.BringCount()))
+		c.sendPreviewPanel(channelID)
+	} else if currentCount < teamSize {
+		// 队伍未满，继续选择
+		c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("✅ 已添加 **%s** 到队伍！\n\n📋 队伍进度: %d/%d\n请继续选择下一只宝可梦", pokemon.Name, currentCount, teamSize))
+	} else if player.Ready {
+		// 队伍已满且已准备
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 的队伍已准备完毕！等待对手...", i.Member.User.Username))
+	} else {
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 选择了 **%s**！等待对手选择...", i.Member.User.Username, pokemon.Name))
+	}
+}
+
+// handleConfigMoves 显示技能配置界面（支持分页）
+func (c *PokemonCommands) handleConfigMoves(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, pageStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+	page, _ := strconv.Atoi(pageStr)
+	if page < 1 {
+		page = 1
+	}
+
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
+	if pokemon == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
+		return
+	}
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
+		c.handler.SetConfig(channelID, userID, config)
+	}
+
+	// 每页显示12个技能（3行x4个）
+	perPage := 12
+	totalMoves := len(pokemon.LearnableMoves)
+	totalPages := (totalMoves + perPage - 1) / perPage
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > totalMoves {
+		end = totalMoves
+	}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("## 🎯 %s 技能配置\n\n", pokemon.Name))
+	desc.WriteString(fmt.Sprintf("**已选技能 (%d/4)：**\n", len(config.MoveIndices)))
+	if len(config.MoveIndices) == 0 {
+		desc.WriteString("_未选择技能（将使用默认技能）_\n")
+	} else {
+		for slot, idx := range config.MoveIndices {
+			if idx < len(pokemon.LearnableMoves) {
+				move := pokemon.LearnableMoves[idx]
+				powerStr := "-"
+				if move.Power > 0 {
+					powerStr = fmt.Sprintf("%d", move.Power)
+				}
+				desc.WriteString(fmt.Sprintf("%d. %s (%s) 威力:%s\n", slot+1, move.Name, move.Type, powerStr))
+			}
+		}
+	}
+	desc.WriteString(fmt.Sprintf("\n📖 可学技能（📚 %s）: %d个 (第%d/%d页)\n", config.Format.DisplayName(), totalMoves, page, totalPages))
+
+	// 当前页技能的学习方式
+	for idx := start; idx < end; idx++ {
+		move := pokemon.LearnableMoves[idx]
+		desc.WriteString(fmt.Sprintf("• %s — %s\n", move.Name, c.describeMoveLearn(pokemonID, move.ID, config.Format)))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "⚡ 技能配置",
+		Description: desc.String(),
+		Color:       0x3498DB,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "点击技能选择/取消，绿色为已选"},
+	}
+
+	var rows []discordgo.MessageComponent
+	var buttons []discordgo.MessageComponent
+
+	// 显示当前页的技能
+	for idx := start; idx < end; idx++ {
+		move := pokemon.LearnableMoves[idx]
+		style := discordgo.SecondaryButton
+		for _, si := range config.MoveIndices {
+			if si == idx {
+				style = discordgo.SuccessButton
+				break
+			}
+		}
+		// 截断过长的技能名
+		label := move.Name
+		if len(label) > 12 {
+			label = label[:12] + "…"
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    label,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:setmove:%d:%d:%d", pokemonID, idx, page),
+		})
+		if len(buttons) == 4 {
+			rows = append(rows, discordgo.ActionsRow{Components: buttons})
+			buttons = nil
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+
+	// 分页和操作按钮
+	var navButtons []discordgo.MessageComponent
+	if page > 1 {
+		navButtons = append(navButtons, discordgo.Button{
+			Label:    "⬅️",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("pkm:cfgmoves:%d:%d", pokemonID, page-1),
+		})
+	}
+	navButtons = append(navButtons, discordgo.Button{
+		Label:    "🔍 搜索",
+		Style:    discordgo.PrimaryButton,
+		CustomID: fmt.Sprintf("pkm:searchmove:%d", pokemonID),
+	})
+	if page < totalPages {
+		navButtons = append(navButtons, discordgo.Button{
+			Label:    "➡️",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("pkm:cfgmoves:%d:%d", pokemonID, page+1),
+		})
+	}
+	navButtons = append(navButtons, discordgo.Button{
+		Label:    "🔙 返回",
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("pkm:choose:%d", pokemonID),
+	})
+	navButtons = append(navButtons, discordgo.Button{
+		Label:    "✅ 确认",
+		Style:    discordgo.SuccessButton,
+		CustomID: fmt.Sprintf("pkm:confirmmoves:%d", pokemonID),
+	})
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    fmt.Sprintf("📚 技能范围: %s（点击切换）", config.Format.DisplayName()),
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("pkm:learnset:%d", pokemonID),
+			},
+		},
+	})
+	rows = append(rows, discordgo.ActionsRow{Components: navButtons})
+
+	// 确保不超过 Discord 的 5 行限制
+	if len(rows) > 5 {
+		rows = rows[:5]
+	}
+
+	if err := c.bot.RespondWithEmbed(i.Interaction, embed, rows, true); err != nil {
+		// 如果响应失败，可能是交互已过期，尝试发送新消息
+		log.Printf("技能配置响应失败: %v", err)
+	}
+}
+
+// describeMoveLearn 描述宝可梦学会技能的方式（如"Lv.26 / 招式学习器"）
+func (c *PokemonCommands) describeMoveLearn(pokemonID, moveID int, format valueobject.LearnsetFormat) string {
+	if learn, ok := c.handler.GetMoveLearn(pokemonID, moveID, format); ok {
+		return learn.Description()
+	}
+	return "-"
+}
+
+// handleCycleLearnset 切换可学技能范围（全国图鉴/朱紫），跳过宝可梦无法使用的范围
+func (c *PokemonCommands) handleCycleLearnset(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 配置已过期，请重新选择宝可梦")
+		return
+	}
+
+	changed := false
+	format := config.Format
+	for range valueobject.LearnsetFormats {
+		format = format.Next()
+		if format == config.Format {
+			break
+		}
+		if err := c.handler.ChangeConfigFormat(config, format); err == nil {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 该宝可梦没有其他可用的技能范围")
+		return
+	}
+
+	c.handler.SetConfig(channelID, userID, config)
+	c.handleConfigMoves(i, channelID, userID, pokemonIDStr, "1")
+}
+
+// handleSetMove 设置/取消技能
+func (c *PokemonCommands) handleSetMove(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr, moveIdxStr, pageStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+	moveIdx, _ := strconv.Atoi(moveIdxStr)
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
+	}
+
+	found := -1
+	for j, idx := range config.MoveIndices {
+		if idx == moveIdx {
+			found = j
+			break
+		}
+	}
+
+	if found >= 0 {
+		config.MoveIndices = append(config.MoveIndices[:found], config.MoveIndices[found+1:]...)
+	} else if len(config.MoveIndices) < 4 {
+		config.MoveIndices = append(config.MoveIndices, moveIdx)
+	} else {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 最多只能选择4个技能")
+		return
+	}
+
+	c.handler.SetConfig(channelID, userID, config)
+	c.handleConfigMoves(i, channelID, userID, pokemonIDStr, pageStr)
+}
+
+// handleConfirmMoves 确认技能选择，返回配置面板
+func (c *PokemonCommands) handleConfirmMoves(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	c.handleShowConfig(i, channelID, userID, pokemonIDStr)
+}
+
+// configPokemon 按玩家配置的可学技能范围获取宝可梦（没有配置时为全国图鉴）
+func (c *PokemonCommands) configPokemon(channelID, userID string, pokemonID int) *entity.Pokemon {
+	format := valueobject.LearnsetNational
+	if config := c.handler.GetConfig(channelID, userID); config != nil {
+		format = config.Format
+	}
+	return c.handler.GetPokemonInFormat(pokemonID, format)
+}
+
+// handleShowConfig 返回配置面板（保留当前配置）
+func (c *PokemonCommands) handleShowConfig(i *discordgo.InteractionCreate, channelID, userID, pokemonIDStr string) {
+	pokemonID, _ := strconv.Atoi(pokemonIDStr)
+
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
+	if pokemon == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
+		return
+	}
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		config = &pokemon_app.PokemonConfig{PokemonID: pokemonID, MoveIndices: []int{}}
+		c.handler.SetConfig(channelID, userID, config)
+	}
+
+	c.showConfigPanel(i, channelID, userID, pokemon, config)
+}
+
+// handleShowPresets 显示预设列表
+func (c *PokemonCommands) handleShowPresets(i *discordgo.InteractionCreate, channelID, userID string) {
+	presets := c.handler.GetPresets(userID)
+	battle, _ := c.handler.GetBattle(channelID)
+
+	var desc strings.Builder
+	desc.WriteString("## 📋 我的配队预设\n\n")
+
+	if len(presets) == 0 {
+		desc.WriteString("_暂无保存的预设_\n\n")
+		desc.WriteString("在配置宝可梦时点击「保存预设」，或在选择宝可梦后点击「保存当前队伍」来保存整支队伍。")
+	} else {
+		for _, p := range presets {
+			mark := ""
+			if battle != nil && p.MatchesBattle(battle) {
+				mark = " ✅"
+			}
+			desc.WriteString(fmt.Sprintf("**%s** `[%s]` 🏷️ %s%s\n", p.Name, p.ID, p.FormatDisplayName(), mark))
+			names := make([]string, 0, len(p.Members))
+			for _, member := range p.Members {
+				names = append(names, member.PokemonName)
+			}
+			desc.WriteString(fmt.Sprintf("  %d/6: %s\n", len(p.Members), strings.Join(names, " / ")))
+		}
+		if battle != nil {
+			desc.WriteString("\n✅ 表示适用于当前对战的规则")
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📋 配队预设",
+		Description: desc.String(),
+		Color:       0x9B59B6,
+	}
+
+	var buttons []discordgo.MessageComponent
+	for _, p := range presets {
+		buttons = append(buttons, discordgo.Button{
+			Label:    p.Name,
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("pkm:preset:%s", p.ID),
+		})
+	}
+
+	var components []discordgo.MessageComponent
+	for start := 0; start < len(buttons); start += 5 {
+		end := start + 5
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		components = append(components, discordgo.ActionsRow{Components: buttons[start:end]})
+	}
+	components = append(components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "💾 保存当前队伍", Style: discordgo.SuccessButton, CustomID: "pkm:saveteam"},
+			discordgo.Button{Label: "🔙 返回选择", Style: discordgo.SecondaryButton, CustomID: "pkm:select"},
+		},
+	})
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, components, true)
+}
+
+// describePresetMember 描述预设中的一只宝可梦（道具、性格、特性、努力值、技能）
+func (c *PokemonCommands) describePresetMember(idx int, member *pokemon_app.PresetMember) string {
+	config := member.Config
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%d. %s**", idx+1, member.PokemonName))
+	if item := c.handler.GetItem(config.ItemID); item != nil {
+		sb.WriteString(" @ " + item.Name)
+	}
+	sb.WriteString(fmt.Sprintf(" · %s", config.Nature))
+
+	pokemon := c.handler.GetPokemonInFormat(config.PokemonID, config.Format)
+	if pokemon != nil {
+		if config.AbilitySlot == -1 && pokemon.HiddenAbility != nil {
+			sb.WriteString(" · " + pokemon.HiddenAbility.Name)
+		} else if config.AbilitySlot >= 0 && config.AbilitySlot < len(pokemon.Abilities) {
+			sb.WriteString(" · " + pokemon.Abilities[config.AbilitySlot].Name)
+		}
+	}
+	if config.TeraType != "" {
+		sb.WriteString(fmt.Sprintf(" · 太晶%s", config.TeraType))
+	}
+	sb.WriteString("\n")
+	if config.EVs != nil {
+		sb.WriteString(fmt.Sprintf("  💪 %s", formatStatSpread(*config.EVs)))
+		if config.IVs != nil {
+			sb.WriteString(fmt.Sprintf(" · 🧬 %s", formatStatSpread(*config.IVs)))
+		}
+		sb.WriteString("\n")
+	}
+	if pokemon != nil {
+		moves := make([]string, 0, len(config.MoveIndices))
+		for _, moveIdx := range config.MoveIndices {
+			if moveIdx >= 0 && moveIdx < len(pokemon.LearnableMoves) {
+				moves = append(moves, pokemon.LearnableMoves[moveIdx].Name)
+			}
+		}
+		if len(moves) > 0 {
+			sb.WriteString("  ⚔️ " + strings.Join(moves, " / ") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// handlePresetDetail 显示预设详情（成员顺序调整、载入、复制、标签）
+func (c *PokemonCommands) handlePresetDetail(i *discordgo.InteractionCreate, channelID, userID, presetID, selectedStr string) {
+	preset := c.handler.GetPreset(userID, presetID)
+	if preset == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 预设不存在")
+		return
+	}
+	selected, _ := strconv.Atoi(selectedStr)
+	if selected < 0 || selected >= len(preset.Members) {
+		selected = 0
+	}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("🏷️ 适用规则: **%s** · 成员 %d/6\n\n", preset.FormatDisplayName(), len(preset.Members)))
+	for idx, member := range preset.Members {
+		desc.WriteString(c.describePresetMember(idx, member))
+	}
+	if len(preset.Members) == 0 {
+		desc.WriteString("_预设中没有宝可梦_\n")
+	}
+	if battle, err := c.handler.GetBattle(channelID); err == nil && !preset.MatchesBattle(battle) {
+		desc.WriteString(fmt.Sprintf("\n⚠️ 当前对战的规则为 %s，与预设标签不一致", valueobject.RulesetDisplayName(battle.Config.RulesetID())))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📋 " + preset.Name,
+		Description: desc.String(),
+		Color:       0x9B59B6,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "💡 先点击成员选中，再调整顺序、载入编辑或移除；「载入整队」按顺序加入当前对战"},
+	}
+
+	// 成员按钮（选中的成员高亮）
+	var memberButtons []discordgo.MessageComponent
+	for idx, member := range preset.Members {
+		style := discordgo.SecondaryButton
+		if idx == selected {
+			style = discordgo.PrimaryButton
+		}
+		memberButtons = append(memberButtons, discordgo.Button{
+			Label:    fmt.Sprintf("%d. %s", idx+1, member.PokemonName),
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:preset:%s:%d", preset.ID, idx),
+		})
+	}
+
+	var rows []discordgo.MessageComponent
+	for start := 0; start < len(memberButtons); start += 5 {
+		end := start + 5
+		if end > len(memberButtons) {
+			end = len(memberButtons)
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: memberButtons[start:end]})
+	}
+
+	hasMembers := len(preset.Members) > 0
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "⬆️ 上移", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:presetmove:%s:%d:up", preset.ID, selected), Disabled: !hasMembers || selected == 0},
+			discordgo.Button{Label: "⬇️ 下移", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:presetmove:%s:%d:down", preset.ID, selected), Disabled: !hasMembers || selected >= len(preset.Members)-1},
+			discordgo.Button{Label: "✏️ 载入编辑", Style: discordgo.PrimaryButton, CustomID: fmt.Sprintf("pkm:loadpreset:%s:%d", preset.ID, selected), Disabled: !hasMembers},
+			discordgo.Button{Label: "➖ 移除", Style: discordgo.DangerButton, CustomID: fmt.Sprintf("pkm:presetrm:%s:%d", preset.ID, selected), Disabled: !hasMembers},
+			discordgo.Button{Label: "➕ 加入当前配置", Style: discordgo.SuccessButton, CustomID: fmt.Sprintf("pkm:presetadd:%s", preset.ID), Disabled: len(preset.Members) >= 6},
+		},
+	})
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "📥 载入整队", Style: discordgo.SuccessButton, CustomID: fmt.Sprintf("pkm:loadteam:%s", preset.ID), Disabled: !hasMembers},
+			discordgo.Button{Label: "🏷️ 切换标签", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:presettag:%s", preset.ID)},
+			discordgo.Button{Label: "📄 复制", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("pkm:presetdup:%s", preset.ID)},
+			discordgo.Button{Label: "🗑️ 删除", Style: discordgo.DangerButton, CustomID: fmt.Sprintf("pkm:delpreset:%s", preset.ID)},
+			discordgo.Button{Label: "🔙 返回列表", Style: discordgo.SecondaryButton, CustomID: "pkm:presets"},
+		},
+	})
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
+
+// handleLoadPreset 加载预设中的一只宝可梦到当前配置并打开配置面板
+func (c *PokemonCommands) handleLoadPreset(i *discordgo.InteractionCreate, channelID, userID, presetID, indexStr string) {
+	index, _ := strconv.Atoi(indexStr)
+	if err := c.handler.LoadPresetToConfig(channelID, userID, presetID, index); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	config := c.handler.GetConfig(channelID, userID)
+	pokemon := c.handler.GetPokemonInFormat(config.PokemonID, config.Format)
+	if pokemon == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
+		return
+	}
+
+	c.showConfigPanel(i, channelID, userID, pokemon, config)
+}
+
+// handleLoadPresetTeam 将预设中的整支队伍加入当前对战
+func (c *PokemonCommands) handleLoadPresetTeam(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
+	preset := c.handler.GetPreset(userID, presetID)
+	if preset == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 预设不存在")
+		return
+	}
+
+	loaded, err := c.handler.LoadPresetTeam(channelID, userID, presetID, 50)
+	if loaded == 0 && err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	var warnings []string
+	if err != nil {
+		warnings = append(warnings, "未能全部载入："+err.Error())
+	} else if loaded < len(preset.Members) {
+		warnings = append(warnings, fmt.Sprintf("队伍空位不足，只载入了前 %d 只", loaded))
+	}
+
+	var builds []*entity.PokemonBuild
+	if battle, err := c.handler.GetBattle(channelID); err == nil {
+		if player := battle.GetPlayer(userID); player != nil && len(player.Roster) >= loaded {
+			for _, member := range player.Roster[len(player.Roster)-loaded:] {
+				builds = append(builds, member.Build)
+			}
+		}
+	}
+
+	c.respondTeamAdded(i, channelID, userID, fmt.Sprintf("📋 已从「%s」载入 %d 只宝可梦", preset.Name, loaded), builds, warnings)
+}
+
+// handleAddToPreset 将当前配置加入预设
+func (c *PokemonCommands) handleAddToPreset(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 请先选择并配置宝可梦")
+		return
+	}
+	preset, err := c.handler.AddToPreset(userID, presetID, config)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handlePresetDetail(i, channelID, userID, preset.ID, strconv.Itoa(len(preset.Members)-1))
+}
+
+// handleMovePresetMember 调整预设成员顺序
+func (c *PokemonCommands) handleMovePresetMember(i *discordgo.InteractionCreate, channelID, userID, presetID, indexStr, direction string) {
+	index, _ := strconv.Atoi(indexStr)
+	delta := 1
+	if direction == "up" {
+		delta = -1
+	}
+	if err := c.handler.MovePresetMember(userID, presetID, index, delta); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handlePresetDetail(i, channelID, userID, presetID, strconv.Itoa(index+delta))
+}
+
+// handleRemovePresetMember 从预设中移除一只宝可梦
+func (c *PokemonCommands) handleRemovePresetMember(i *discordgo.InteractionCreate, channelID, userID, presetID, indexStr string) {
+	index, _ := strconv.Atoi(indexStr)
+	if err := c.handler.RemovePresetMember(userID, presetID, index); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handlePresetDetail(i, channelID, userID, presetID, strconv.Itoa(index-1))
+}
+
+// handleDuplicatePreset 复制预设
+func (c *PokemonCommands) handleDuplicatePreset(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
+	preset, err := c.handler.DuplicatePreset(userID, presetID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handlePresetDetail(i, channelID, userID, preset.ID, "0")
+}
+
+// handleCyclePresetFormat 切换预设的适用规则标签
+func (c *PokemonCommands) handleCyclePresetFormat(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
+	if _, err := c.handler.CyclePresetFormat(userID, presetID); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handlePresetDetail(i, channelID, userID, presetID, "0")
+}
+
+// showPresetNameModal 显示填写预设名称的模态框
+func (c *PokemonCommands) showPresetNameModal(i *discordgo.InteractionCreate, customID, defaultName string) {
+	c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: customID,
+			Title:    "保存配队预设",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "preset_name",
+							Label:       "预设名称",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "输入预设名称...",
+							Value:       defaultName,
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   20,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// handleSavePresetModal 显示保存预设的模态框（保存当前配置）
+func (c *PokemonCommands) handleSavePresetModal(i *discordgo.InteractionCreate, channelID, userID string) {
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 请先选择宝可梦")
+		return
+	}
+
+	pokemon := c.handler.GetPokemonByID(config.PokemonID)
+	defaultName := ""
+	if pokemon != nil {
+		defaultName = pokemon.Name
+	}
+
+	c.showPresetNameModal(i, "pkm:savepreset_modal", defaultName)
+}
+
+// handleSaveTeamModal 显示保存预设的模态框（保存当前对战中的整支队伍）
+func (c *PokemonCommands) handleSaveTeamModal(i *discordgo.InteractionCreate, channelID, userID string) {
+	battle, err := c.handler.GetBattle(channelID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 没有进行中的对战")
+		return
+	}
+	player := battle.GetPlayer(userID)
+	if player == nil || len(player.Roster) == 0 {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 你还没有选择宝可梦")
+		return
+	}
+
+	c.showPresetNameModal(i, "pkm:saveteam_modal", player.Roster[0].Pokemon.Name+"队")
+}
+
+// handleDeletePreset 删除预设
+func (c *PokemonCommands) handleDeletePreset(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
+	if err := c.handler.DeletePreset(userID, presetID); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+	c.handleShowPresets(i, channelID, userID)
+}
+
+// presetNameFromModal 读取模态框中的预设名称
+func presetNameFromModal(i *discordgo.InteractionCreate) string {
+	data := i.ModalSubmitData()
+	for _, row := range data.Components {
+		if actionRow, ok := row.(*discordgo.ActionsRow); ok {
+			for _, comp := range actionRow.Components {
+				if input, ok := comp.(*discordgo.TextInput); ok && input.CustomID == "preset_name" {
+					return strings.TrimSpace(input.Value)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// handleSavePresetSubmit 处理保存预设模态框提交（当前配置）
+func (c *PokemonCommands) handleSavePresetSubmit(i *discordgo.InteractionCreate) {
+	channelID := i.ChannelID
+	userID := i.Member.User.ID
+
+	presetName := presetNameFromModal(i)
+	if presetName == "" {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 预设名称不能为空")
+		return
+	}
+
+	config := c.handler.GetConfig(channelID, userID)
+	if config == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 请先选择宝可梦")
+		return
+	}
+
+	preset, err := c.handler.SavePreset(userID, presetName, config)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	c.handlePresetDetail(i, channelID, userID, preset.ID, "0")
+}
+
+// handleSaveTeamSubmit 处理保存预设模态框提交（整支队伍）
+func (c *PokemonCommands) handleSaveTeamSubmit(i *discordgo.InteractionCreate) {
+	channelID := i.ChannelID
+	userID := i.Member.User.ID
+
+	presetName := presetNameFromModal(i)
+	if presetName == "" {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 预设名称不能为空")
+		return
+	}
+
+	preset, err := c.handler.SaveTeamAsPreset(channelID, userID, presetName)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	c.handlePresetDetail(i, channelID, userID, preset.ID, "0")
+}
+
+// buildPreviewEmbed 构建队伍预览 Embed（展示双方报名的全部宝可梦）
+func (c *PokemonCommands) buildPreviewEmbed(battle *entity.Battle) *discordgo.MessageEmbed {
+	var fields []*discordgo.MessageEmbedField
+	for _, player := range []*entity.BattlePlayer{battle.Player1, battle.Player2} {
+		var lines []string
+		for _, battler := range player.Roster {
+			lines = append(lines, fmt.Sprintf("**%s** Lv.%d %s", battler.Pokemon.Name, battler.Level, pokeapi.GetPokemonTypeString(battler.Pokemon.Types)))
+		}
+		status := "⏳ 选择中"
+		if player.BringConfirmed {
+			status = "✅ 已确认"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s（%s）", player.Username, status),
+			Value:  strings.Join(lines, "\n"),
+			Inline: true,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "👀 队伍预览 - " + battle.GetDisplayName(),
+		Description: fmt.Sprintf("从 %d 只宝可梦中选择 **%d** 只出战，第一只为首发。\n双方确认后同时公开！", battle.TeamSize, battle.BringCount()),
+		Fields:      fields,
+		Color:       0xFFCB05,
+	}
+}
+
+// sendPreviewPanel 发送队伍预览面板到频道
+func (c *PokemonCommands) sendPreviewPanel(channelID string) {
+	battle, err := c.handler.GetBattle(channelID)
+	if err != nil || battle.State != entity.BattleStatePreview {
+		return
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "👀 选择出战", Style: discordgo.PrimaryButton, CustomID: "pkm:preview"},
+				discordgo.Button{Label: "🔄 刷新", Style: discordgo.SecondaryButton, CustomID: "pkm:refresh"},
+			},
+		},
+	}
+
+	c.bot.SendChannelEmbed(channelID, c.buildPreviewEmbed(battle), components)
+}
 
-	var desc strings.Builder
-	desc.WriteString("## 📋 我的配队预设\n\n")
+// handleShowBringMenu 显示出战选择菜单（仅自己可见）
+func (c *PokemonCommands) handleShowBringMenu(i *discordgo.InteractionCreate, channelID, userID string, update bool) {
+	battle, err := c.handler.GetBattle(channelID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
+		return
+	}
+	if battle.State != entity.BattleStatePreview {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 当前不是队伍预览阶段")
+		return
+	}
 
-	if len(presets) == 0 {
-		desc.WriteString("_暂无保存的预设_\n\n")
-		desc.WriteString("在选择宝可梦配置时点击「保存预设」来保存当前配置。")
-	} else {
-		for _, p := range presets {
-			desc.WriteString(fmt.Sprintf("**%s** `[%s]`\n", p.Name, p.ID))
-			desc.WriteString(fmt.Sprintf("  宝可梦: %s | 性格: %s\n", p.PokemonName, p.Nature))
-		}
+	player := battle.GetPlayer(userID)
+	if player == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
+		return
+	}
+	if player.BringConfirmed {
+		c.bot.RespondEphemeral(i.Interaction, "⏳ 你已确认出战宝可梦，等待对手...")
+		return
 	}
 
-	embed := &discordgo.MessageEmbed{
-		Title:       "📋 配队预设",
-		Description: desc.String(),
-		Color:       0x9B59B6,
+	// 选择顺序（第一只为首发）
+	order := make(map[int]int)
+	for pos, idx := range player.BringSelection {
+		order[idx] = pos + 1
 	}
 
 	var buttons []discordgo.MessageComponent
-	for _, p := range presets {
+	for idx, battler := range player.Roster {
+		label := battler.Pokemon.Name
+		style := discordgo.SecondaryButton
+		if pos, ok := order[idx]; ok {
+			label = fmt.Sprintf("%d. %s", pos, battler.Pokemon.Name)
+			style = discordgo.SuccessButton
+		}
 		buttons = append(buttons, discordgo.Button{
-			Label:    p.Name,
-			Style:    discordgo.PrimaryButton,
-			CustomID: fmt.Sprintf("pkm:loadpreset:%s", p.ID),
+			Label:    label,
+			Style:    style,
+			CustomID: fmt.Sprintf("pkm:bring:%d", idx),
 		})
-		if len(buttons) >= 5 {
-			break
-		}
 	}
 
-	var components []discordgo.MessageComponent
-	if len(buttons) > 0 {
-		components = append(components, discordgo.ActionsRow{Components: buttons})
+	var rows []discordgo.MessageComponent
+	for j := 0; j < len(buttons); j += 5 {
+		end := j + 5
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[j:end]})
 	}
-
-	c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds:     []*discordgo.MessageEmbed{embed},
-			Components: components,
-			Flags:      discordgo.MessageFlagsEphemeral,
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "✅ 确认出战", Style: discordgo.SuccessButton, CustomID: "pkm:bringok", Disabled: len(player.BringSelection) != battle.BringCount()},
+			discordgo.Button{Label: "↩️ 重选", Style: discordgo.SecondaryButton, CustomID: "pkm:bringreset"},
 		},
 	})
-}
 
-// handleLoadPreset 加载预设
-func (c *PokemonCommands) handleLoadPreset(i *discordgo.InteractionCreate, channelID, userID, presetID string) {
-	if err := c.handler.LoadPresetToConfig(channelID, userID, presetID); err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
-		return
+	// 对手的全部宝可梦
+	opponent := battle.GetOpponent(userID)
+	var opponentLines []string
+	for _, battler := range opponent.Roster {
+		opponentLines = append(opponentLines, fmt.Sprintf("%s %s", battler.Pokemon.Name, pokeapi.GetPokemonTypeString(battler.Pokemon.Types)))
 	}
 
-	config := c.handler.GetConfig(channelID, userID)
-	pokemon := c.handler.GetPokemonByID(config.PokemonID)
-
-	c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("✅ 已加载预设！宝可梦: %s", pokemon.Name))
-}
-
-// handleSavePresetModal 显示保存预设的模态框
-func (c *PokemonCommands) handleSavePresetModal(i *discordgo.InteractionCreate, channelID, userID string) {
-	config := c.handler.GetConfig(channelID, userID)
-	if config == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 请先选择宝可梦")
-		return
+	var selected []string
+	for _, idx := range player.BringSelection {
+		selected = append(selected, player.Roster[idx].Pokemon.Name)
+	}
+	selectedText := "（未选择）"
+	if len(selected) > 0 {
+		selectedText = strings.Join(selected, " → ")
 	}
 
-	pokemon := c.handler.GetPokemonByID(config.PokemonID)
-	defaultName := ""
-	if pokemon != nil {
-		defaultName = pokemon.Name
+	leadHint := "第一只为首发"
+	if battle.IsDoubles() {
+		leadHint = "前两只为首发"
 	}
 
-	c.bot.Session().InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseModal,
-		Data: &discordgo.InteractionResponseData{
-			CustomID: "pkm:savepreset_modal",
-			Title:    "保存配队预设",
-			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{
-						discordgo.TextInput{
-							CustomID:    "preset_name",
-							Label:       "预设名称",
-							Style:       discordgo.TextInputShort,
-							Placeholder: "输入预设名称...",
-							Value:       defaultName,
-							Required:    true,
-							MinLength:   1,
-							MaxLength:   20,
-						},
-					},
-				},
-			},
+	embed := &discordgo.MessageEmbed{
+		Title:       "👀 选择出战宝可梦",
+		Description: fmt.Sprintf("按出场顺序点击选择 **%d** 只宝可梦（%s），再次点击取消。\n\n📋 已选 (%d/%d): %s", battle.BringCount(), leadHint, len(selected), battle.BringCount(), selectedText),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "🔍 对手的队伍", Value: strings.Join(opponentLines, "\n")},
 		},
-	})
+		Color: 0x3498DB,
+	}
+
+	if update {
+		c.bot.UpdateWithEmbed(i.Interaction, embed, rows)
+		return
+	}
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
 }
 
-// handleDeletePreset 删除预设
-func (c *PokemonCommands) handleDeletePreset(i *discordgo.InteractionCreate, userID, presetID string) {
-	if err := c.handler.DeletePreset(userID, presetID); err != nil {
+// handleToggleBring 选择/取消出战宝可梦
+func (c *PokemonCommands) handleToggleBring(i *discordgo.InteractionCreate, channelID, userID, indexStr string) {
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的选择")
+		return
+	}
+	if err := c.handler.ToggleBring(channelID, userID, index); err != nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
 	}
-	c.bot.RespondEphemeral(i.Interaction, "✅ 预设已删除")
+	c.handleShowBringMenu(i, channelID, userID, true)
 }
 
-// handleSavePresetSubmit 处理保存预设模态框提交
-func (c *PokemonCommands) handleSavePresetSubmit(i *discordgo.InteractionCreate) {
-	channelID := i.ChannelID
-	userID := i.Member.User.ID
-	data := i.ModalSubmitData()
-
-	// 获取预设名称
-	var presetName string
-	for _, row := range data.Components {
-		if actionRow, ok := row.(*discordgo.ActionsRow); ok {
-			for _, comp := range actionRow.Components {
-				if input, ok := comp.(*discordgo.TextInput); ok && input.CustomID == "preset_name" {
-					presetName = input.Value
-				}
-			}
-		}
+// handleResetBring 清空出战选择
+func (c *PokemonCommands) handleResetBring(i *discordgo.InteractionCreate, channelID, userID string) {
+	if err := c.handler.ResetBring(channelID, userID); err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
 	}
+	c.handleShowBringMenu(i, channelID, userID, true)
+}
 
-	if presetName == "" {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 预设名称不能为空")
+// handleConfirmBring 确认出战选择，双方都确认后公开双方的出战宝可梦
+func (c *PokemonCommands) handleConfirmBring(i *discordgo.InteractionCreate, channelID, userID string) {
+	started, err := c.handler.ConfirmBring(channelID, userID)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
 	}
 
-	config := c.handler.GetConfig(channelID, userID)
-	if config == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 请先选择宝可梦")
+	if !started {
+		c.bot.RespondPublic(i.Interaction, fmt.Sprintf("✅ **%s** 已确认出战宝可梦！等待对手...", i.Member.User.Username))
 		return
 	}
 
-	preset, err := c.handler.SavePreset(userID, presetName, config)
+	battle, err := c.handler.GetBattle(channelID)
 	if err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
 		return
 	}
 
-	c.bot.RespondEphemeral(i.Interaction, fmt.Sprintf("✅ 预设 **%s** 已保存！\n宝可梦: %s", preset.Name, preset.PokemonName))
+	// 同时公开双方的出战宝可梦
+	var lines []string
+	for _, player := range []*entity.BattlePlayer{battle.Player1, battle.Player2} {
+		var names []string
+		for _, battler := range player.Team {
+			names = append(names, battler.Pokemon.Name)
+		}
+		lines = append(lines, fmt.Sprintf("**%s**: %s", player.Username, strings.Join(names, "、")))
+	}
+	c.bot.RespondPublic(i.Interaction, "🎬 双方选择完毕，公开出战宝可梦！\n"+strings.Join(lines, "\n")+"\n\n⚔️ 对战开始！")
+	c.sendBattlePanel(i, channelID)
 }
 
 // handleShowSwitchMenu 显示换人菜单
@@ -1568,15 +3417,56 @@ func (c *PokemonCommands) handleShowSwitchMenu(i *discordgo.InteractionCreate, c
 		return
 	}
 
-	if player.Action != nil {
+	if !battle.IsPlayerTurn(userID) {
 		c.bot.RespondEphemeral(i.Interaction, "⏳ 你已选择行动，等待对手...")
 		return
 	}
 
-	// 构建可换上场的宝可梦按钮
+	rows := buildBenchRows(player, "pkm:doswitch:%d", true)
+	if rows == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可以换上场的宝可梦")
+		return
+	}
+
+	description := "选择要换上场的宝可梦："
+	if battle.IsDoubles() {
+		if current := player.GetSlot(battle.GetPendingSlot(player)); current != nil {
+			description = fmt.Sprintf("选择要替换 **%s** 上场的宝可梦：", current.Pokemon.Name)
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔄 换人",
+		Description: description,
+		Color:       0x3498DB,
+	}
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+}
+
+// handleDoSwitch 执行换人
+func (c *PokemonCommands) handleDoSwitch(i *discordgo.InteractionCreate, channelID, userID, indexStr string) {
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的选择")
+		return
+	}
+
+	logs, err := c.handler.SwitchPokemon(channelID, userID, index)
+	if err != nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+		return
+	}
+
+	c.respondAfterAction(i, channelID, userID, logs, "✅ 已选择换人，等待对手行动...")
+}
+
+// buildBenchRows 构建可换上场的宝可梦按钮（customIDFormat 为按钮ID格式，参数为队伍索引），没有可换上场的宝可梦时返回 nil
+// cancellable 为 true 时附加取消按钮
+func buildBenchRows(player *entity.BattlePlayer, customIDFormat string, cancellable bool) []discordgo.MessageComponent {
 	var buttons []discordgo.MessageComponent
 	for idx, battler := range player.Team {
-		if idx == player.ActiveIndex {
+		if player.IsActive(battler) {
 			continue // 跳过当前在场的宝可梦
 		}
 		if !battler.IsAlive() {
@@ -1587,21 +3477,22 @@ func (c *PokemonCommands) handleShowSwitchMenu(i *discordgo.InteractionCreate, c
 		buttons = append(buttons, discordgo.Button{
 			Label:    label,
 			Style:    discordgo.PrimaryButton,
-			CustomID: fmt.Sprintf("pkm:doswitch:%d", idx),
+			CustomID: fmt.Sprintf(customIDFormat, idx),
 		})
 	}
 
 	if len(buttons) == 0 {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可以换上场的宝可梦")
-		return
+		return nil
 	}
 
 	// 添加取消按钮
-	buttons = append(buttons, discordgo.Button{
-		Label:    "🔙 取消",
-		Style:    discordgo.SecondaryButton,
-		CustomID: "pkm:refresh",
-	})
+	if cancellable {
+		buttons = append(buttons, discordgo.Button{
+			Label:    "🔙 取消",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "pkm:refresh",
+		})
+	}
 
 	var rows []discordgo.MessageComponent
 	for j := 0; j < len(buttons); j += 5 {
@@ -1611,58 +3502,54 @@ func (c *PokemonCommands) handleShowSwitchMenu(i *discordgo.InteractionCreate, c
 		}
 		rows = append(rows, discordgo.ActionsRow{Components: buttons[j:end]})
 	}
-
-	embed := &discordgo.MessageEmbed{
-		Title:       "🔄 换人",
-		Description: "选择要换上场的宝可梦：",
-		Color:       0x3498DB,
-	}
-
-	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
+	return rows
 }
 
-// handleDoSwitch 执行换人
-func (c *PokemonCommands) handleDoSwitch(i *discordgo.InteractionCreate, channelID, userID, indexStr string) {
-	index, err := strconv.Atoi(indexStr)
+// handleShowReplacementMenu 显示替补选择菜单（宝可梦倒下或使用U转等技能后）
+func (c *PokemonCommands) handleShowReplacementMenu(i *discordgo.InteractionCreate, channelID, userID string) {
+	battle, err := c.handler.GetBattle(channelID)
 	if err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的选择")
+		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
 		return
 	}
 
-	logs, err := c.handler.SwitchPokemon(channelID, userID, index)
-	if err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
+	player := battle.GetPlayer(userID)
+	if player == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
 		return
 	}
 
-	// 检查是否为人机对战，如果是则触发 AI 行动
-	battle, _ := c.handler.GetBattle(channelID)
-	if battle != nil && battle.IsAIBattle && len(logs) == 0 {
-		// 玩家已行动，触发 AI 行动并执行回合
-		aiLogs, _ := c.handler.ExecuteAITurn(channelID)
-		logs = aiLogs
-		// 重新获取对战状态
-		battle, _ = c.handler.GetBattle(channelID)
+	if !battle.NeedsReplacement(userID) {
+		c.bot.RespondEphemeral(i.Interaction, "⏳ 你不需要选择替补，等待对手...")
+		return
 	}
 
-	if len(logs) > 0 {
-		// 回合执行完毕，发送战斗日志
-		logText := strings.Join(logs, "\n")
+	// 必须选择替补，不提供取消按钮
+	rows := buildBenchRows(player, "pkm:forceswitch:%d", false)
+	if rows == nil {
+		c.bot.RespondEphemeral(i.Interaction, "❌ 没有可以换上场的宝可梦")
+		return
+	}
 
-		if battle != nil && battle.State == entity.BattleStateFinished {
-			c.bot.RespondPublic(i.Interaction, logText)
-			c.handler.EndBattle(channelID)
+	description := "选择替补上场的宝可梦："
+	if current := player.GetSlot(player.GetReplacementSlot()); current != nil {
+		if current.IsAlive() {
+			description = fmt.Sprintf("**%s** 回到了队伍，选择替换上场的宝可梦：", current.Pokemon.Name)
 		} else {
-			c.bot.RespondPublic(i.Interaction, logText)
-			c.sendBattlePanel(i, channelID)
+			description = fmt.Sprintf("**%s** 倒下了，选择替补上场的宝可梦：", current.Pokemon.Name)
 		}
-	} else {
-		// 等待对手（普通 PVP 模式）
-		c.bot.RespondEphemeral(i.Interaction, "✅ 已选择换人，等待对手行动...")
 	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔄 选择替补",
+		Description: description,
+		Color:       0x3498DB,
+	}
+
+	c.bot.RespondWithEmbed(i.Interaction, embed, rows, true)
 }
 
-// handleForceSwitch 强制换人（宝可梦倒下时）
+// handleForceSwitch 选择替补上场（宝可梦倒下或使用U转等技能后）
 func (c *PokemonCommands) handleForceSwitch(i *discordgo.InteractionCreate, channelID, userID, indexStr string) {
 	index, err := strconv.Atoi(indexStr)
 	if err != nil {
@@ -1670,34 +3557,17 @@ func (c *PokemonCommands) handleForceSwitch(i *discordgo.InteractionCreate, chan
 		return
 	}
 
-	battle, err := c.handler.GetBattle(channelID)
+	logs, err := c.handler.ChooseReplacement(channelID, userID, index)
 	if err != nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 对战不存在")
-		return
-	}
-
-	player := battle.GetPlayer(userID)
-	if player == nil {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 你不在对战中")
-		return
-	}
-
-	if index < 0 || index >= len(player.Team) {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 无效的宝可梦")
+		c.bot.RespondEphemeral(i.Interaction, "❌ "+err.Error())
 		return
 	}
 
-	if !player.Team[index].IsAlive() {
-		c.bot.RespondEphemeral(i.Interaction, "❌ 该宝可梦已倒下")
+	c.bot.RespondPublic(i.Interaction, strings.Join(logs, "\n"))
+	if battle, err := c.handler.GetBattle(channelID); err == nil && battle.State == entity.BattleStateFinished {
+		c.handler.EndBattle(channelID)
 		return
 	}
-
-	// 直接换人，不消耗行动
-	oldName := player.Team[player.ActiveIndex].Pokemon.Name
-	player.ActiveIndex = index
-	newName := player.Team[index].Pokemon.Name
-
-	c.bot.RespondPublic(i.Interaction, fmt.Sprintf("🔄 **%s** 收回了 **%s**，换上了 **%s**！", player.Username, oldName, newName))
 	c.sendBattlePanel(i, channelID)
 }
 
@@ -1738,7 +3608,7 @@ func (c *PokemonCommands) handleSearchMoveModalSubmit(i *discordgo.InteractionCr
 	userID := i.Member.User.ID
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
@@ -1808,15 +3678,6 @@ func (c *PokemonCommands) handleSearchMoveModalSubmit(i *discordgo.InteractionCr
 
 	desc.WriteString("**搜索结果：**\n")
 
-	embed := &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("🔍 %s 的技能搜索", pokemon.Name),
-		Description: desc.String(),
-		Color:       0x3498DB,
-		Thumbnail: &discordgo.MessageEmbedThumbnail{
-			URL: fmt.Sprintf("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/%d.gif", pokemonID),
-		},
-	}
-
 	// 构建技能选择按钮 (每行最多 5 个)
 	var components []discordgo.MessageComponent
 	var currentRow []discordgo.MessageComponent
@@ -1843,6 +3704,8 @@ func (c *PokemonCommands) handleSearchMoveModalSubmit(i *discordgo.InteractionCr
 			}
 		}
 
+		desc.WriteString(fmt.Sprintf("• %s (%s) — %s\n", move.Name, string(move.Type), c.describeMoveLearn(pokemonID, move.ID, config.Format)))
+
 		label := move.Name
 		if isSelected {
 			label = "✓ " + label
@@ -1870,6 +3733,15 @@ func (c *PokemonCommands) handleSearchMoveModalSubmit(i *discordgo.InteractionCr
 		components = append(components, discordgo.ActionsRow{Components: currentRow})
 	}
 
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🔍 %s 的技能搜索", pokemon.Name),
+		Description: desc.String(),
+		Color:       0x3498DB,
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: fmt.Sprintf("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/other/showdown/%d.gif", pokemonID),
+		},
+	}
+
 	// 添加返回按钮
 	components = append(components, discordgo.ActionsRow{
 		Components: []discordgo.MessageComponent{
@@ -1895,7 +3767,7 @@ func (c *PokemonCommands) handleSelectSearchedMove(i *discordgo.InteractionCreat
 	pokemonID, _ := strconv.Atoi(pokemonIDStr)
 	moveIdx, _ := strconv.Atoi(moveIdxStr)
 
-	pokemon := c.handler.GetPokemonByID(pokemonID)
+	pokemon := c.configPokemon(channelID, userID, pokemonID)
 	if pokemon == nil {
 		c.bot.RespondEphemeral(i.Interaction, "❌ 未找到宝可梦")
 		return
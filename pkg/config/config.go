@@ -12,6 +12,8 @@ type Config struct {
 	Uno      UnoConfig      `yaml:"uno"`
 	LLM      LLMConfig      `yaml:"llm"`
 	Activity ActivityConfig `yaml:"activity"`
+	Storage  StorageConfig  `yaml:"storage"`
+	PokeAPI  PokeAPIConfig  `yaml:"pokeapi"`
 }
 
 type DiscordConfig struct {
@@ -42,6 +44,23 @@ type ActivityConfig struct {
 	VitePort     int    `yaml:"vite_port"` // Vite 开发服务器端口
 }
 
+// 存储后端
+const (
+	StorageMemory = "memory" // 内存存储（重启后数据丢失）
+	StorageFile   = "file"   // JSON 文件存储（启动时恢复）
+)
+
+type StorageConfig struct {
+	Backend string `yaml:"backend"` // memory 或 file
+	Path    string `yaml:"path"`    // file 后端的数据目录
+}
+
+type PokeAPIConfig struct {
+	CacheDir    string `yaml:"cache_dir"`     // CSV 缓存目录（留空则不缓存）
+	MaxAgeHours int    `yaml:"max_age_hours"` // 缓存有效期（小时），过期后联网校验
+	Offline     bool   `yaml:"offline"`       // 离线模式：只使用本地缓存与内置快照
+}
+
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -74,6 +93,21 @@ func Load(path string) (*Config, error) {
 	if cfg.Activity.VitePort == 0 {
 		cfg.Activity.VitePort = 5173
 	}
+	if cfg.Storage.Backend == "" {
+		cfg.Storage.Backend = StorageMemory
+	}
+	if cfg.Storage.Path == "" {
+		cfg.Storage.Path = "./data"
+	}
+	if cfg.PokeAPI.CacheDir == "" {
+		cfg.PokeAPI.CacheDir = "./data/pokeapi"
+	}
+	if cfg.PokeAPI.MaxAgeHours == 0 {
+		cfg.PokeAPI.MaxAgeHours = 168
+	}
+	if cfg.Storage.Backend != StorageMemory && cfg.Storage.Backend != StorageFile {
+		return nil, fmt.Errorf("不支持的存储后端: %s", cfg.Storage.Backend)
+	}
 
 	return &cfg, nil
 }